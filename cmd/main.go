@@ -43,27 +43,79 @@ func main() {
 		log.Fatal("Failed to initialize flashcard repository")
 	}
 
-	// Initialize LLM client if API key is provided
+	// Load the languages cards can be written in
+	languageData, err := config.LoadLanguages(cfg.LanguagesFile)
+	if err != nil {
+		log.Fatalf("Failed to read language registry: %v", err)
+	}
+	languages, err := services.LoadLanguageRegistry(languageData)
+	if err != nil {
+		log.Fatalf("Failed to load language registry: %v", err)
+	}
+
+	// Initialize LLM client if a provider is configured
 	var llmClient services.LLMClient
-	if cfg.OpenAIAPIKey != "" {
-		client, err := services.NewOpenAIClient(cfg.OpenAIAPIKey)
+	if cfg.LLMProvider != "" {
+		client, err := services.NewLLMClient(services.LLMConfig{
+			Provider: cfg.LLMProvider,
+			Model:    cfg.LLMModel,
+			BaseURL:  cfg.LLMBaseURL,
+			APIKey:   cfg.LLMAPIKey(),
+		}, languages)
 		if err != nil {
 			log.Printf("Warning: Failed to initialize AI translation: %v", err)
 			log.Println("AI translation features will be disabled")
 		} else {
 			llmClient = client
-			log.Println("AI translation enabled")
+			log.Printf("AI translation enabled (%s, model %s)", cfg.LLMProvider, client.Model())
 		}
 	} else {
-		log.Println("AI translation disabled (OPENAI_API_KEY not set)")
+		log.Println("AI translation disabled (LLM_PROVIDER and OPENAI_API_KEY not set)")
+	}
+
+	// Cache translations so that repeated requests and hints do not ask the model again
+	translationCache := services.NewTranslationCache(db.NewPostgresTranslationCacheRepository(dbConn),
+		cfg.TranslationCacheTTL, cfg.TranslationCacheSize)
+	if llmClient != nil && cfg.TranslationCacheTTL > 0 {
+		llmClient = services.NewCachingLLMClient(llmClient, cfg.LLMProvider, translationCache)
+		log.Printf("Caching translations for %s", cfg.TranslationCacheTTL)
 	}
+	translationCacheHandler := handlers.NewTranslationCacheHandler(translationCache)
 
-	flashcardService := services.NewFlashcardService(flashcardRepo, llmClient)
+	// FSRS schedules each user with the weights fitted by cmd/optimize when they exist, the defaults otherwise
+	scheduler, err := services.NewScheduler(cfg.Scheduler, db.NewPostgresFSRSParameterRepository(dbConn))
+	if err != nil {
+		log.Fatalf("Failed to initialize scheduler: %v", err)
+	}
+	log.Printf("Using %s scheduler", scheduler.Name())
+
+	flashcardService := services.NewFlashcardService(flashcardRepo, llmClient, scheduler, languages)
 	if flashcardService == nil {
 		log.Fatal("Failed to initialize flashcard service")
 	}
 
-	flashcardHandler := handlers.NewFlashcardHandler(flashcardService)
+	flashcardHandler := handlers.NewFlashcardHandler(flashcardService, languages)
+	languageHandler := handlers.NewLanguageHandler(languages)
+
+	// Initialize deck components
+	deckRepo := db.NewPostgresDeckRepository(dbConn)
+	deckService := services.NewDeckService(deckRepo)
+	deckHandler := handlers.NewDeckHandler(deckService)
+
+	// Initialize cloze note components
+	clozeNoteRepo := db.NewPostgresClozeNoteRepository(dbConn)
+	clozeNoteService := services.NewClozeNoteService(clozeNoteRepo)
+	clozeNoteHandler := handlers.NewClozeNoteHandler(clozeNoteService, languages)
+
+	// Initialize note type components
+	noteRepo := db.NewPostgresNoteRepository(dbConn)
+	noteService := services.NewNoteService(noteRepo)
+	noteHandler := handlers.NewNoteHandler(noteService, languages)
+
+	// Initialize quiz components
+	quizRepo := db.NewPostgresQuizRepository(dbConn)
+	quizService := services.NewQuizService(quizRepo)
+	quizHandler := handlers.NewQuizHandler(quizService)
 
 	router := mux.NewRouter()
 
@@ -73,6 +125,12 @@ func main() {
 	router.Use(jsonMiddleware)
 
 	flashcardHandler.RegisterRoutes(router)
+	deckHandler.RegisterRoutes(router)
+	clozeNoteHandler.RegisterRoutes(router)
+	noteHandler.RegisterRoutes(router)
+	quizHandler.RegisterRoutes(router)
+	languageHandler.RegisterRoutes(router)
+	translationCacheHandler.RegisterRoutes(router)
 
 	router.HandleFunc("/health", healthCheckHandler).Methods("GET")
 
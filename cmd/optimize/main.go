@@ -0,0 +1,93 @@
+// Command optimize fits personalised FSRS weights from the review history and stores them so the API
+// server schedules with them instead of the defaults.
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/akolybelnikov/flashcards/config"
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+	"github.com/akolybelnikov/flashcards/services"
+
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	iterations := flag.Int("iterations", services.DefaultFSRSOptimizerOptions.Iterations, "number of gradient descent steps")
+	learningRate := flag.Float64("learning-rate", services.DefaultFSRSOptimizerOptions.LearningRate, "gradient descent step size")
+	dryRun := flag.Bool("dry-run", false, "print the fitted weights without storing them")
+	flag.Parse()
+
+	cfg := config.Load()
+
+	dbConn, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := dbConn.Close(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}()
+
+	repo := db.NewPostgresFSRSParameterRepository(dbConn)
+
+	logs, err := repo.GetReviewHistory(models.DefaultUserID)
+	if err != nil {
+		log.Fatalf("Failed to load review history: %v", err)
+	}
+	log.Printf("Loaded %d reviews", len(logs))
+
+	// Continue from previously fitted weights so repeated runs refine rather than restart
+	var initial []float64
+	if existing, err := repo.Get(models.DefaultUserID); err != nil {
+		log.Fatalf("Failed to load existing weights: %v", err)
+	} else if existing != nil {
+		initial = existing.Weights
+	}
+
+	result, err := services.OptimizeFSRSWeights(logs, initial, services.FSRSOptimizerOptions{
+		Iterations:   *iterations,
+		LearningRate: *learningRate,
+	})
+	if errors.Is(err, services.ErrNotEnoughReviews) {
+		log.Fatalf("%v; keep reviewing and try again later", err)
+	}
+	if err != nil {
+		log.Fatalf("Failed to optimise weights: %v", err)
+	}
+
+	log.Printf("Log loss improved from %.4f to %.4f over %d reviews", result.InitialLogLoss, result.LogLoss, result.ReviewCount)
+	fmt.Println(formatWeights(result.Weights))
+
+	if *dryRun {
+		return
+	}
+
+	err = repo.Save(&models.FSRSParameters{
+		UserID:      models.DefaultUserID,
+		Weights:     result.Weights,
+		LogLoss:     result.LogLoss,
+		ReviewCount: result.ReviewCount,
+	})
+	if err != nil {
+		log.Fatalf("Failed to store weights: %v", err)
+	}
+	log.Printf("Stored weights")
+}
+
+func formatWeights(weights []float64) string {
+	out := ""
+	for i, w := range weights {
+		if i > 0 {
+			out += ", "
+		}
+		out += fmt.Sprintf("%.4f", w)
+	}
+	return out
+}
@@ -3,14 +3,24 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DatabaseURL  string
-	Port         string
-	OpenAIAPIKey string
+	DatabaseURL          string
+	Port                 string
+	LLMProvider          string
+	LLMModel             string
+	LLMBaseURL           string
+	OpenAIAPIKey         string
+	AnthropicAPIKey      string
+	TranslationCacheTTL  time.Duration
+	TranslationCacheSize int
+	Scheduler            string
+	LanguagesFile        string
 }
 
 func Load() *Config {
@@ -19,14 +29,40 @@ func Load() *Config {
 	}
 
 	config := &Config{
-		DatabaseURL:  getEnv("DB_URL"),
-		Port:         getEnvWithDefault("PORT", "8080"),
-		OpenAIAPIKey: os.Getenv("OPENAI_API_KEY"), // Optional
+		DatabaseURL:          getEnv("DB_URL"),
+		Port:                 getEnvWithDefault("PORT", "8080"),
+		LLMProvider:          os.Getenv("LLM_PROVIDER"), // Optional, AI features are disabled without a provider
+		LLMModel:             os.Getenv("LLM_MODEL"),    // Optional, defaults to the provider's default model
+		LLMBaseURL:           os.Getenv("LLM_BASE_URL"), // Optional, e.g. a local OpenAI-compatible server
+		OpenAIAPIKey:         os.Getenv("OPENAI_API_KEY"),
+		AnthropicAPIKey:      os.Getenv("ANTHROPIC_API_KEY"),
+		TranslationCacheTTL:  getDurationWithDefault("TRANSLATION_CACHE_TTL", 30*24*time.Hour), // 0 disables the cache
+		TranslationCacheSize: getIntWithDefault("TRANSLATION_CACHE_SIZE", 1000),                // 0 disables the in-memory cache
+		Scheduler:            getEnvWithDefault("SCHEDULER", "sm2"),
+		LanguagesFile:        os.Getenv("LANGUAGES_FILE"), // Optional, replaces the built-in language registry
+	}
+
+	// OpenAI remains the provider when only its API key is set
+	if config.LLMProvider == "" && config.OpenAIAPIKey != "" {
+		config.LLMProvider = "openai"
 	}
 
 	return config
 }
 
+// LLMAPIKey returns the API key of the configured LLM provider, or an empty string for providers that
+// need none.
+func (c *Config) LLMAPIKey() string {
+	switch c.LLMProvider {
+	case "openai":
+		return c.OpenAIAPIKey
+	case "anthropic":
+		return c.AnthropicAPIKey
+	default:
+		return ""
+	}
+}
+
 func getEnv(key string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -41,3 +77,27 @@ func getEnvWithDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getDurationWithDefault(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		panic("Invalid duration in environment variable: " + key)
+	}
+	return duration
+}
+
+func getIntWithDefault(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		panic("Invalid number in environment variable: " + key)
+	}
+	return n
+}
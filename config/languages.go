@@ -0,0 +1,20 @@
+package config
+
+import (
+	_ "embed"
+	"os"
+)
+
+// defaultLanguages is the language registry used when LANGUAGES_FILE is not set.
+//
+//go:embed languages.json
+var defaultLanguages []byte
+
+// LoadLanguages returns the JSON language registry stored at path, or the built-in registry if path
+// is empty.
+func LoadLanguages(path string) ([]byte, error) {
+	if path == "" {
+		return defaultLanguages, nil
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// GetExamples returns the example sentences stored for a flashcard in the order they were generated.
+func (r *PostgresFlashcardRepository) GetExamples(flashcardID int) ([]*models.CardExample, error) {
+	rows, err := r.db.Query(`SELECT id, flashcard_id, sentence, translation, level, created_at
+		FROM card_examples
+		WHERE flashcard_id = $1
+		ORDER BY position`, flashcardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var examples []*models.CardExample
+	for rows.Next() {
+		var example models.CardExample
+		if err := rows.Scan(&example.ID, &example.FlashcardID, &example.Sentence, &example.Translation, &example.Level, &example.CreatedAt); err != nil {
+			return nil, err
+		}
+		examples = append(examples, &example)
+	}
+
+	return examples, rows.Err()
+}
+
+// ReplaceExamples stores the example sentences of a flashcard, replacing any it already has. The
+// flashcard is locked so that concurrent replacements run one after the other instead of mixing
+// their examples.
+func (r *PostgresFlashcardRepository) ReplaceExamples(flashcardID int, sentences []models.ExampleSentence) ([]*models.CardExample, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = tx.QueryRow(`SELECT id FROM flashcards WHERE id = $1 FOR UPDATE`, flashcardID).Scan(&flashcardID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("flashcard with id %d not found", flashcardID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM card_examples WHERE flashcard_id = $1`, flashcardID); err != nil {
+		return nil, err
+	}
+
+	examples := make([]*models.CardExample, 0, len(sentences))
+	for i, sentence := range sentences {
+		example := &models.CardExample{FlashcardID: flashcardID, ExampleSentence: sentence}
+		err := tx.QueryRow(`INSERT INTO card_examples (flashcard_id, position, sentence, translation, level)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at`,
+			flashcardID, i, sentence.Sentence, sentence.Translation, sentence.Level,
+		).Scan(&example.ID, &example.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		examples = append(examples, example)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return examples, nil
+}
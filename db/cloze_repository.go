@@ -0,0 +1,241 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/akolybelnikov/flashcards/models"
+
+	"github.com/lib/pq"
+)
+
+type ClozeNoteRepository interface {
+	Create(req *models.CreateClozeNoteRequest, cards []models.ClozeCard) (*models.ClozeNote, error)
+	GetByID(id int) (*models.ClozeNote, error)
+	Update(id int, req *models.UpdateClozeNoteRequest, cards []models.ClozeCard) (*models.ClozeNote, error)
+	Delete(id int) error
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryRow(query string, args ...any) *sql.Row
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// upsertClozeCard inserts the card for one index of a note, or re-renders the existing card so that
+// its schedule and review history are kept.
+const upsertClozeCard = `INSERT INTO flashcards (question, answer, deck_id, question_lang, answer_lang, cloze_note_id, cloze_index)
+	VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($4, ''), $5, $6)
+	ON CONFLICT (cloze_note_id, cloze_index) DO UPDATE SET
+		question = EXCLUDED.question,
+		answer = EXCLUDED.answer,
+		updated_at = CURRENT_TIMESTAMP
+	RETURNING id`
+
+type PostgresClozeNoteRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresClozeNoteRepository(db *sql.DB) *PostgresClozeNoteRepository {
+	return &PostgresClozeNoteRepository{db: db}
+}
+
+// Create inserts a cloze note together with a flashcard for each of its rendered cards.
+func (r *PostgresClozeNoteRepository) Create(req *models.CreateClozeNoteRequest, cards []models.ClozeCard) (*models.ClozeNote, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var id int
+	query := `INSERT INTO cloze_notes (text, lang, deck_id) VALUES ($1, NULLIF($2, ''), $3) RETURNING id`
+	if err := tx.QueryRow(query, req.Text, req.Lang, req.DeckID).Scan(&id); err != nil {
+		return nil, deckError(err, req.DeckID)
+	}
+
+	for _, card := range cards {
+		var cardID int
+		if err := tx.QueryRow(upsertClozeCard, card.Question, card.Answer, req.DeckID, req.Lang, id, card.Index).Scan(&cardID); err != nil {
+			return nil, err
+		}
+		if err := replaceTags(tx, cardID, req.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	note, err := getClozeNote(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return note, tx.Commit()
+}
+
+func (r *PostgresClozeNoteRepository) GetByID(id int) (*models.ClozeNote, error) {
+	return getClozeNote(r.db, id)
+}
+
+// Update changes the text or tags of a cloze note. When cards is not nil it holds the re-rendered
+// cards of the new text: cards for indexes that are still present are updated in place, new indexes
+// get a card in the note's deck with the tags of their siblings, and cards for removed indexes are
+// deleted.
+func (r *PostgresClozeNoteRepository) Update(id int, req *models.UpdateClozeNoteRequest, cards []models.ClozeCard) (*models.ClozeNote, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var deckID sql.NullInt64
+	var lang string
+	query := `UPDATE cloze_notes SET text = COALESCE($1, text), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 RETURNING deck_id, COALESCE(lang, '')`
+	err = tx.QueryRow(query, req.Text, id).Scan(&deckID, &lang)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("cloze note with id %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cards != nil {
+		if err := updateClozeCards(tx, id, deckID, lang, cards); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Tags != nil {
+		rows, err := tx.Query(`SELECT id FROM flashcards WHERE cloze_note_id = $1`, id)
+		if err != nil {
+			return nil, err
+		}
+		cardIDs, err := scanIDs(rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, cardID := range cardIDs {
+			if err := replaceTags(tx, cardID, *req.Tags); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	note, err := getClozeNote(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return note, tx.Commit()
+}
+
+// updateClozeCards replaces the cards of a note with the given rendered cards.
+func updateClozeCards(tx *sql.Tx, noteID int, deckID sql.NullInt64, lang string, cards []models.ClozeCard) error {
+	var siblingTags []string
+	query := `SELECT ` + flashcardTagsColumn + ` FROM flashcards f WHERE f.cloze_note_id = $1 ORDER BY f.cloze_index LIMIT 1`
+	if err := tx.QueryRow(query, noteID).Scan(pq.Array(&siblingTags)); err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	rows, err := tx.Query(`SELECT cloze_index FROM flashcards WHERE cloze_note_id = $1`, noteID)
+	if err != nil {
+		return err
+	}
+	existing, err := scanIDs(rows)
+	if err != nil {
+		return err
+	}
+
+	indexes := make([]int64, 0, len(cards))
+	for _, card := range cards {
+		var cardID int
+		if err := tx.QueryRow(upsertClozeCard, card.Question, card.Answer, deckID, lang, noteID, card.Index).Scan(&cardID); err != nil {
+			return err
+		}
+		if !slices.Contains(existing, card.Index) {
+			if err := replaceTags(tx, cardID, siblingTags); err != nil {
+				return err
+			}
+		}
+		indexes = append(indexes, int64(card.Index))
+	}
+
+	_, err = tx.Exec(`DELETE FROM flashcards WHERE cloze_note_id = $1 AND NOT (cloze_index = ANY($2))`, noteID, pq.Array(indexes))
+	return err
+}
+
+func (r *PostgresClozeNoteRepository) Delete(id int) error {
+	query := `DELETE FROM cloze_notes WHERE id = $1`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("cloze note with id %d not found", id)
+	}
+
+	return nil
+}
+
+// getClozeNote reads a cloze note and its cards, ordered by cloze index.
+func getClozeNote(q queryer, id int) (*models.ClozeNote, error) {
+	var note models.ClozeNote
+	var deckID sql.NullInt64
+	query := `SELECT id, text, COALESCE(lang, ''), deck_id, created_at, updated_at FROM cloze_notes WHERE id = $1`
+	err := q.QueryRow(query, id).Scan(&note.ID, &note.Text, &note.Lang, &deckID, &note.CreatedAt, &note.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("cloze note with id %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deckID.Valid {
+		deck := int(deckID.Int64)
+		note.DeckID = &deck
+	}
+
+	rows, err := q.Query(`SELECT `+flashcardColumns+` FROM flashcards f WHERE f.cloze_note_id = $1 ORDER BY f.cloze_index`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	note.Cards = []*models.Flashcard{}
+	for rows.Next() {
+		flashcard, err := scanFlashcard(rows)
+		if err != nil {
+			return nil, err
+		}
+		note.Cards = append(note.Cards, flashcard)
+	}
+
+	return &note, rows.Err()
+}
+
+// scanIDs reads a single integer column from every row and closes the rows.
+func scanIDs(rows *sql.Rows) ([]int, error) {
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,223 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+type DeckRepository interface {
+	Create(req *models.CreateDeckRequest) (*models.Deck, error)
+	GetAll() ([]*models.Deck, error)
+	GetByID(id int) (*models.Deck, error)
+	Update(id int, req *models.UpdateDeckRequest) (*models.Deck, error)
+	Delete(id int) error
+	GetSubtree(id int) ([]*models.Deck, error)
+	Move(id int, parentID *int) (*models.Deck, error)
+}
+
+const deckColumns = `id, parent_id, name, description, created_at, updated_at`
+
+// deckSubtreeCTE selects the IDs of deck $1 and all of its descendants as "deck_tree".
+const deckSubtreeCTE = `WITH RECURSIVE deck_tree AS (
+		SELECT id FROM decks WHERE id = $1
+		UNION ALL
+		SELECT d.id FROM decks d JOIN deck_tree t ON d.parent_id = t.id
+	)`
+
+// deckMoveLockKey is the transaction-level advisory lock taken by every deck move. Locking only the
+// two decks involved is not enough: moves of unrelated decks can together close a cycle, so moves run
+// one at a time.
+const deckMoveLockKey = 0x6465636b // "deck"
+
+// ErrDeckCycle is returned when a deck would be moved under itself or one of its descendants.
+var ErrDeckCycle = errors.New("cannot move a deck into its own subtree")
+
+type PostgresDeckRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresDeckRepository(db *sql.DB) *PostgresDeckRepository {
+	return &PostgresDeckRepository{db: db}
+}
+
+func scanDeck(row rowScanner) (*models.Deck, error) {
+	var deck models.Deck
+	var parentID sql.NullInt64
+	err := row.Scan(
+		&deck.ID,
+		&parentID,
+		&deck.Name,
+		&deck.Description,
+		&deck.CreatedAt,
+		&deck.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		deck.ParentID = &id
+	}
+	return &deck, nil
+}
+
+func (r *PostgresDeckRepository) Create(req *models.CreateDeckRequest) (*models.Deck, error) {
+	query := `INSERT INTO decks (name, description, parent_id) VALUES ($1, $2, $3) RETURNING ` + deckColumns
+
+	deck, err := scanDeck(r.db.QueryRow(query, req.Name, req.Description, req.ParentID))
+	if err != nil {
+		return nil, deckError(err, req.ParentID)
+	}
+
+	return deck, nil
+}
+
+func (r *PostgresDeckRepository) GetAll() ([]*models.Deck, error) {
+	query := `SELECT ` + deckColumns + ` FROM decks ORDER BY name, id`
+
+	return r.queryDecks(query)
+}
+
+// GetSubtree returns a deck followed by all of its descendants.
+func (r *PostgresDeckRepository) GetSubtree(id int) ([]*models.Deck, error) {
+	query := deckSubtreeCTE + `
+		SELECT ` + deckColumns + ` FROM decks WHERE id IN (SELECT id FROM deck_tree)
+		ORDER BY id = $1 DESC, name, id`
+
+	decks, err := r.queryDecks(query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(decks) == 0 {
+		return nil, fmt.Errorf("deck with id %d not found", id)
+	}
+
+	return decks, nil
+}
+
+// Move re-parents a deck. Descendants reference the deck rather than its position, so the whole
+// subtree moves with it in a single update; the cycle check and the update share a transaction that
+// holds deckMoveLockKey and locks the deck rows involved.
+func (r *PostgresDeckRepository) Move(id int, parentID *int) (*models.Deck, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, deckMoveLockKey); err != nil {
+		return nil, err
+	}
+
+	var locked int
+	err = tx.QueryRow(`SELECT id FROM decks WHERE id = $1 FOR UPDATE`, id).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("deck with id %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID != nil {
+		err = tx.QueryRow(`SELECT id FROM decks WHERE id = $1 FOR UPDATE`, *parentID).Scan(&locked)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("deck with id %d not found", *parentID)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var inSubtree bool
+		query := deckSubtreeCTE + ` SELECT EXISTS (SELECT 1 FROM deck_tree WHERE id = $2)`
+		if err := tx.QueryRow(query, id, *parentID).Scan(&inSubtree); err != nil {
+			return nil, err
+		}
+		if inSubtree {
+			return nil, ErrDeckCycle
+		}
+	}
+
+	query := `UPDATE decks SET parent_id = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 RETURNING ` + deckColumns
+	deck, err := scanDeck(tx.QueryRow(query, parentID, id))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return deck, nil
+}
+
+func (r *PostgresDeckRepository) queryDecks(query string, args ...any) ([]*models.Deck, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var decks []*models.Deck
+	for rows.Next() {
+		deck, err := scanDeck(rows)
+		if err != nil {
+			return nil, err
+		}
+		decks = append(decks, deck)
+	}
+
+	return decks, rows.Err()
+}
+
+func (r *PostgresDeckRepository) GetByID(id int) (*models.Deck, error) {
+	query := `SELECT ` + deckColumns + ` FROM decks WHERE id = $1`
+
+	deck, err := scanDeck(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("deck with id %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return deck, nil
+}
+
+func (r *PostgresDeckRepository) Update(id int, req *models.UpdateDeckRequest) (*models.Deck, error) {
+	query := `UPDATE decks SET name = COALESCE($1, name), description = COALESCE($2, description), updated_at = CURRENT_TIMESTAMP WHERE id = $3 RETURNING ` + deckColumns
+
+	deck, err := scanDeck(r.db.QueryRow(query, req.Name, req.Description, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("deck with id %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return deck, nil
+}
+
+func (r *PostgresDeckRepository) Delete(id int) error {
+	query := `DELETE FROM decks WHERE id = $1`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("deck with id %d not found", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/akolybelnikov/flashcards/models"
+
+	"github.com/lib/pq"
+)
+
+// filterConditions translates a filter into SQL conditions on the flashcard aliased "f". Parameters
+// are appended to args and numbered after the ones already there.
+func filterConditions(filter *models.FlashcardFilter, args []any) ([]string, []any) {
+	if filter == nil {
+		return nil, args
+	}
+
+	var conditions []string
+	if filter.Tags != nil {
+		for _, group := range filter.Tags.AnyOf {
+			args = append(args, pq.Array(group))
+			conditions = append(conditions, fmt.Sprintf(hasTagCondition, fmt.Sprintf("$%d", len(args))))
+		}
+		if len(filter.Tags.NoneOf) > 0 {
+			args = append(args, pq.Array(filter.Tags.NoneOf))
+			conditions = append(conditions, "NOT "+fmt.Sprintf(hasTagCondition, fmt.Sprintf("$%d", len(args))))
+		}
+	}
+	if filter.QuestionLang != "" {
+		args = append(args, filter.QuestionLang)
+		conditions = append(conditions, languageCondition("f.question_lang", len(args)))
+	}
+	if filter.AnswerLang != "" {
+		args = append(args, filter.AnswerLang)
+		conditions = append(conditions, languageCondition("f.answer_lang", len(args)))
+	}
+	if filter.AIGenerated != nil {
+		if *filter.AIGenerated {
+			conditions = append(conditions, "cardinality(f.ai_fields) > 0")
+		} else {
+			conditions = append(conditions, "cardinality(f.ai_fields) = 0")
+		}
+	}
+
+	return conditions, args
+}
+
+// studyCondition keeps flashcards that are waiting for review out of study.
+const studyCondition = `f.status = 'active'`
+
+// languageCondition matches a language column against parameter n and its more specific forms, so
+// that "en" matches "en" and "en-US" but not "eng". Language tags contain no LIKE wildcards.
+func languageCondition(column string, n int) string {
+	return fmt.Sprintf("(%[1]s = $%[2]d OR %[1]s LIKE ($%[2]d || '-%%'))", column, n)
+}
+
+// whereClause joins conditions into a WHERE clause, or returns an empty string if there are none.
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
@@ -2,20 +2,100 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/akolybelnikov/flashcards/models"
+
+	"github.com/lib/pq"
 )
 
 type FlashcardRepository interface {
 	Create(req *models.CreateFlashcardRequest) (*models.Flashcard, error)
-	GetAll() ([]*models.Flashcard, error)
+	GetAll(opts *models.FlashcardListOptions) ([]*models.Flashcard, *models.FlashcardCursor, error)
+	GetAllByDeck(deckID int) ([]*models.Flashcard, error)
 	GetByID(id int) (*models.Flashcard, error)
 	Update(id int, req *models.UpdateFlashcardRequest) (*models.Flashcard, error)
 	Delete(id int) error
-	GetRandom() (*models.Flashcard, error)
+	GetRandom(filter *models.FlashcardFilter) (*models.Flashcard, error)
+	GetRandomByDeck(deckID int) (*models.Flashcard, error)
+	GetSchedule(flashcardID int) (*models.CardSchedule, error)
+	SaveReview(schedule *models.CardSchedule, log *models.ReviewLog) error
+	GetReviewLogs(flashcardID int, beforeID int64, limit int) ([]*models.ReviewLog, error)
+	GetDue(now time.Time, limit int) ([]*models.ScheduledFlashcard, error)
+	GetDueByDeck(deckID int, now time.Time, limit int) ([]*models.ScheduledFlashcard, error)
+	Search(query, lang string, limit int) ([]*models.SearchResult, error)
+	FuzzySearch(query string, threshold float64, limit int) ([]*models.SearchResult, error)
+	GetExamples(flashcardID int) ([]*models.CardExample, error)
+	ReplaceExamples(flashcardID int, sentences []models.ExampleSentence) ([]*models.CardExample, error)
+	GetPendingReview(limit int) ([]*models.Flashcard, error)
+	Approve(id int, approvedBy string, approvedAt time.Time, edit *models.UpdateFlashcardRequest) (*models.Flashcard, error)
+	Reject(id int, rejectedBy string, rejectedAt time.Time) (*models.Flashcard, error)
+	CreateTranslationPreview(preview *models.StoredTranslationPreview) error
+	GetTranslationPreview(id string) (*models.StoredTranslationPreview, error)
+	DeleteTranslationPreviewsCreatedBefore(t time.Time) (int64, error)
+}
+
+// flashcardColumns lists the columns scanned by scanFlashcard, qualified with the "f" alias.
+const flashcardColumns = `f.id, f.deck_id, f.question, f.answer, COALESCE(f.question_lang, ''), COALESCE(f.answer_lang, ''), ` +
+	flashcardTagsColumn + `, f.cloze_note_id, f.cloze_index, ` + flashcardReverseColumn + `, f.note_id, ` + flashcardNoteColumn +
+	`, f.grammar, ` + flashcardProvenanceColumn + `, f.status, COALESCE(f.approved_by, ''), f.approved_at, COALESCE(f.rejected_by, ''), f.rejected_at, f.created_at, f.updated_at`
+
+// flashcardReverseColumn selects the ID of the card linked to the flashcard aliased "f": the card it
+// was generated from if it is a reverse card, or its reverse card otherwise.
+const flashcardReverseColumn = `COALESCE(f.reverse_of, (SELECT rev.id FROM flashcards rev WHERE rev.reverse_of = f.id))`
+
+// flashcardProvenanceColumn selects the AI provenance of the flashcard aliased "f" as JSON, or NULL
+// when none of its fields are machine-generated.
+const flashcardProvenanceColumn = `CASE WHEN cardinality(f.ai_fields) > 0 THEN json_build_object(
+	'fields', f.ai_fields, 'model', COALESCE(f.ai_model, ''), 'prompt_version', COALESCE(f.ai_prompt_version, ''),
+	'generated_at', f.ai_generated_at) END`
+
+// syncReverseCard copies the sides, languages, deck, grammar, AI provenance and review status of
+// flashcard $1 to its linked reverse card, swapping question and answer.
+const syncReverseCard = `UPDATE flashcards rev SET
+		question = f.answer,
+		answer = f.question,
+		question_lang = f.answer_lang,
+		answer_lang = f.question_lang,
+		deck_id = f.deck_id,
+		grammar = f.grammar,
+		ai_fields = ` + swappedAIFields + `,
+		ai_model = f.ai_model,
+		ai_prompt_version = f.ai_prompt_version,
+		ai_generated_at = f.ai_generated_at,
+		status = f.status,
+		approved_by = f.approved_by,
+		approved_at = f.approved_at,
+		rejected_by = f.rejected_by,
+		rejected_at = f.rejected_at,
+		updated_at = CURRENT_TIMESTAMP
+	FROM flashcards f
+	WHERE f.id = $1 AND (rev.reverse_of = f.id OR rev.id = f.reverse_of)
+	RETURNING rev.id`
+
+// swappedAIFields lists the machine-generated fields of the flashcard aliased "f" as they are on its
+// reverse card, where question and answer trade places.
+const swappedAIFields = `ARRAY(SELECT CASE field WHEN 'question' THEN 'answer' WHEN 'answer' THEN 'question' ELSE field END
+		FROM unnest(f.ai_fields) AS field)`
+
+const selectFlashcardByID = `SELECT ` + flashcardColumns + ` FROM flashcards f WHERE f.id = $1`
+
+// flashcardSortKeys maps each listing order to its SQL expression and the type that the expression's
+// text form is cast back to when comparing against a cursor.
+var flashcardSortKeys = map[string]struct{ expr, sqlType string }{
+	models.SortByCreatedAt: {"f.created_at", "timestamp"},
+	models.SortByUpdatedAt: {"f.updated_at", "timestamp"},
+	models.SortByQuestion:  {"f.question", "text"},
+	// Cards that have never been reviewed are due from the moment they were created.
+	models.SortByDue: {"COALESCE(s.due_at, f.created_at)", "timestamp"},
 }
 
+// pgForeignKeyViolation is the Postgres error code raised when a referenced row does not exist.
+const pgForeignKeyViolation = "23503"
+
 type PostgresFlashcardRepository struct {
 	db *sql.DB
 }
@@ -24,63 +104,217 @@ func NewPostgresFlashcardRepository(db *sql.DB) *PostgresFlashcardRepository {
 	return &PostgresFlashcardRepository{db: db}
 }
 
-func (r *PostgresFlashcardRepository) Create(req *models.CreateFlashcardRequest) (*models.Flashcard, error) {
-	query := `INSERT INTO flashcards (question, answer) VALUES ($1, $2) RETURNING id, question, answer, created_at, updated_at`
+type rowScanner interface {
+	Scan(dest ...any) error
+}
 
-	var flashcard models.Flashcard
-	err := r.db.QueryRow(query, req.Question, req.Answer).Scan(
+// flashcardDest returns the scan destinations matching flashcardColumns.
+func flashcardDest(flashcard *models.Flashcard, deckID *sql.NullInt64) []any {
+	return []any{
 		&flashcard.ID,
+		deckID,
 		&flashcard.Question,
 		&flashcard.Answer,
+		&flashcard.QuestionLang,
+		&flashcard.AnswerLang,
+		pq.Array(&flashcard.Tags),
+		&flashcard.ClozeNoteID,
+		&flashcard.ClozeIndex,
+		&flashcard.ReverseID,
+		&flashcard.NoteID,
+		jsonDest[models.NoteContent]{&flashcard.Note},
+		jsonDest[models.Grammar]{&flashcard.Grammar},
+		jsonDest[models.AIProvenance]{&flashcard.AIProvenance},
+		&flashcard.Status,
+		&flashcard.ApprovedBy,
+		&flashcard.ApprovedAt,
+		&flashcard.RejectedBy,
+		&flashcard.RejectedAt,
 		&flashcard.CreatedAt,
 		&flashcard.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
 	}
+}
+
+// jsonDest scans a nullable JSON column into a pointer that is left nil for NULL.
+type jsonDest[T any] struct {
+	value **T
+}
+
+func (d jsonDest[T]) Scan(src any) error {
+	if src == nil {
+		*d.value = nil
+		return nil
+	}
+	data, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into %T", src, *d.value)
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d.value = &value
+	return nil
+}
 
+func scanFlashcard(row rowScanner) (*models.Flashcard, error) {
+	var flashcard models.Flashcard
+	var deckID sql.NullInt64
+	if err := row.Scan(flashcardDest(&flashcard, &deckID)...); err != nil {
+		return nil, err
+	}
+	setDeckID(&flashcard, deckID)
 	return &flashcard, nil
 }
 
-func (r *PostgresFlashcardRepository) GetAll() ([]*models.Flashcard, error) {
-	query := `SELECT id, question, answer, created_at, updated_at FROM flashcards ORDER BY created_at DESC`
+func setDeckID(flashcard *models.Flashcard, deckID sql.NullInt64) {
+	if deckID.Valid {
+		id := int(deckID.Int64)
+		flashcard.DeckID = &id
+	}
+}
+
+// deckError turns a foreign key violation on deck_id into a not found error.
+func deckError(err error, deckID *int) error {
+	var pqErr *pq.Error
+	if deckID != nil && errors.As(err, &pqErr) && pqErr.Code == pgForeignKeyViolation {
+		return fmt.Errorf("deck with id %d not found", *deckID)
+	}
+	return err
+}
+
+// Create inserts a flashcard together with its tags, and its reverse card if it is reversible.
+func (r *PostgresFlashcardRepository) Create(req *models.CreateFlashcardRequest) (*models.Flashcard, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	grammar, err := marshalNullableJSON(req.Grammar)
+	if err != nil {
+		return nil, err
+	}
+	provenance := req.AIProvenance
+	if provenance == nil {
+		provenance = &models.AIProvenance{}
+	}
+	var generatedAt *time.Time
+	if len(provenance.Fields) > 0 {
+		generatedAt = &provenance.GeneratedAt
+	}
+
+	var id int
+	query := `INSERT INTO flashcards (question, answer, deck_id, question_lang, answer_lang, grammar,
+			ai_fields, ai_model, ai_prompt_version, ai_generated_at, status)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10, COALESCE(NULLIF($11, ''), 'active'))
+		RETURNING id`
+	err = tx.QueryRow(query, req.Question, req.Answer, req.DeckID, req.QuestionLang, req.AnswerLang, grammar,
+		pq.Array(provenance.Fields), provenance.Model, provenance.PromptVersion, generatedAt, req.Status).Scan(&id)
+	if err != nil {
+		return nil, deckError(err, req.DeckID)
+	}
+
+	if err := replaceTags(tx, id, req.Tags); err != nil {
+		return nil, err
+	}
+
+	if req.Reversible {
+		var reverseID int
+		query := `INSERT INTO flashcards (question, answer, deck_id, question_lang, answer_lang, reverse_of)
+			VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6) RETURNING id`
+		err = tx.QueryRow(query, req.Answer, req.Question, req.DeckID, req.AnswerLang, req.QuestionLang, id).Scan(&reverseID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(syncReverseCard, id); err != nil {
+			return nil, err
+		}
+		if err := replaceTags(tx, reverseID, req.Tags); err != nil {
+			return nil, err
+		}
+	}
 
-	rows, err := r.db.Query(query)
+	flashcard, err := scanFlashcard(tx.QueryRow(selectFlashcardByID, id))
 	if err != nil {
 		return nil, err
 	}
+
+	return flashcard, tx.Commit()
+}
+
+// GetAll returns one page of flashcards using keyset pagination, along with the cursor of the next
+// page, which is nil on the last page.
+func (r *PostgresFlashcardRepository) GetAll(opts *models.FlashcardListOptions) ([]*models.Flashcard, *models.FlashcardCursor, error) {
+	key, ok := flashcardSortKeys[opts.Sort]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported sort %q", opts.Sort)
+	}
+	direction, comparison := "ASC", ">"
+	if opts.Order == models.OrderDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	conditions, args := filterConditions(opts.Filter, nil)
+	if opts.After != nil {
+		args = append(args, opts.After.SortKey, opts.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s, f.id) %s ($%d::%s, $%d)", key.expr, comparison, len(args)-1, key.sqlType, len(args)))
+	}
+	// One extra row tells whether there is a next page.
+	args = append(args, opts.Limit+1)
+
+	query := `SELECT ` + flashcardColumns + `, (` + key.expr + `)::text
+		FROM flashcards f
+		LEFT JOIN flashcard_schedules s ON s.flashcard_id = f.id` + whereClause(conditions) + fmt.Sprintf(`
+		ORDER BY %s %s, f.id %s
+		LIMIT $%d`, key.expr, direction, direction, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
 	defer rows.Close()
 
 	var flashcards []*models.Flashcard
+	var sortKeys []string
 	for rows.Next() {
 		var flashcard models.Flashcard
-		err := rows.Scan(
-			&flashcard.ID,
-			&flashcard.Question,
-			&flashcard.Answer,
-			&flashcard.CreatedAt,
-			&flashcard.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
+		var deckID sql.NullInt64
+		var sortKey string
+		if err := rows.Scan(append(flashcardDest(&flashcard, &deckID), &sortKey)...); err != nil {
+			return nil, nil, err
 		}
+		setDeckID(&flashcard, deckID)
 		flashcards = append(flashcards, &flashcard)
+		sortKeys = append(sortKeys, sortKey)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
 	}
 
-	return flashcards, nil
+	if len(flashcards) <= opts.Limit {
+		return flashcards, nil, nil
+	}
+	last := opts.Limit - 1
+	return flashcards[:opts.Limit], &models.FlashcardCursor{SortKey: sortKeys[last], ID: flashcards[last].ID}, nil
 }
 
-func (r *PostgresFlashcardRepository) GetByID(id int) (*models.Flashcard, error) {
-	query := `SELECT id, question, answer, created_at, updated_at FROM flashcards WHERE id = $1`
+// GetAllByDeck returns the flashcards of a deck and its sub-decks, newest first.
+func (r *PostgresFlashcardRepository) GetAllByDeck(deckID int) ([]*models.Flashcard, error) {
+	if err := r.ensureDeckExists(deckID); err != nil {
+		return nil, err
+	}
 
-	var flashcard models.Flashcard
-	err := r.db.QueryRow(query, id).Scan(
-		&flashcard.ID,
-		&flashcard.Question,
-		&flashcard.Answer,
-		&flashcard.CreatedAt,
-		&flashcard.UpdatedAt,
-	)
+	query := deckSubtreeCTE + `
+		SELECT ` + flashcardColumns + ` FROM flashcards f WHERE f.deck_id IN (SELECT id FROM deck_tree) ORDER BY f.created_at DESC`
+
+	return r.queryFlashcards(query, deckID)
+}
+
+func (r *PostgresFlashcardRepository) GetByID(id int) (*models.Flashcard, error) {
+	flashcard, err := scanFlashcard(r.db.QueryRow(selectFlashcardByID, id))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("flashcard with id %d not found", id)
 	}
@@ -88,28 +322,75 @@ func (r *PostgresFlashcardRepository) GetByID(id int) (*models.Flashcard, error)
 		return nil, err
 	}
 
-	return &flashcard, nil
+	return flashcard, nil
 }
 
+// Update changes the provided fields of a flashcard. Tags, when provided, replace the existing ones.
+// Grammar data is cleared when the question or answer changes, as it no longer describes the card, and
+// edited fields are no longer counted as machine-generated.
+// A linked reverse card is kept in sync, with question and answer swapped.
 func (r *PostgresFlashcardRepository) Update(id int, req *models.UpdateFlashcardRequest) (*models.Flashcard, error) {
-	query := `UPDATE flashcards SET question = COALESCE($1, question), answer = COALESCE($2, answer), updated_at = CURRENT_TIMESTAMP WHERE id = $3 RETURNING id, question, answer, created_at, updated_at`
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
 
-	var flashcard models.Flashcard
-	err := r.db.QueryRow(query, req.Question, req.Answer, id).Scan(
-		&flashcard.ID,
-		&flashcard.Question,
-		&flashcard.Answer,
-		&flashcard.CreatedAt,
-		&flashcard.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("flashcard with id %d not found", id)
+	if err := updateFlashcard(tx, id, req); err != nil {
+		return nil, err
 	}
+
+	flashcard, err := scanFlashcard(tx.QueryRow(selectFlashcardByID, id))
 	if err != nil {
 		return nil, err
 	}
 
-	return &flashcard, nil
+	return flashcard, tx.Commit()
+}
+
+// updateFlashcard applies an update within a transaction and syncs the linked reverse card.
+func updateFlashcard(tx *sql.Tx, id int, req *models.UpdateFlashcardRequest) error {
+	query := `UPDATE flashcards SET
+			question = COALESCE($1, question),
+			answer = COALESCE($2, answer),
+			deck_id = CASE WHEN $3::int IS NULL THEN deck_id ELSE NULLIF($3, 0) END,
+			question_lang = CASE WHEN $4::text IS NULL THEN question_lang ELSE NULLIF($4, '') END,
+			answer_lang = CASE WHEN $5::text IS NULL THEN answer_lang ELSE NULLIF($5, '') END,
+			grammar = CASE WHEN COALESCE($1, question) = question AND COALESCE($2, answer) = answer THEN grammar END,
+			ai_fields = array_remove(array_remove(array_remove(ai_fields,
+				CASE WHEN COALESCE($1, question) <> question THEN 'question' END),
+				CASE WHEN COALESCE($2, answer) <> answer THEN 'answer' END),
+				CASE WHEN COALESCE($1, question) <> question OR COALESCE($2, answer) <> answer THEN 'grammar' END),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6 RETURNING id`
+	err := tx.QueryRow(query, req.Question, req.Answer, req.DeckID, req.QuestionLang, req.AnswerLang, id).Scan(&id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("flashcard with id %d not found", id)
+	}
+	if err != nil {
+		return deckError(err, req.DeckID)
+	}
+
+	if req.Tags != nil {
+		if err := replaceTags(tx, id, *req.Tags); err != nil {
+			return err
+		}
+	}
+
+	var reverseID int
+	err = tx.QueryRow(syncReverseCard, id).Scan(&reverseID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil && req.Tags != nil {
+		if err := replaceTags(tx, reverseID, *req.Tags); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (r *PostgresFlashcardRepository) Delete(id int) error {
@@ -132,23 +413,74 @@ func (r *PostgresFlashcardRepository) Delete(id int) error {
 	return nil
 }
 
-func (r *PostgresFlashcardRepository) GetRandom() (*models.Flashcard, error) {
-	query := `SELECT id, question, answer, created_at, updated_at FROM flashcards ORDER BY RANDOM() LIMIT 1`
+// ErrNoFlashcards is returned when no flashcard can be studied among those a random pick is made
+// from, because the filter or deck matches none or they are all waiting for review.
+var ErrNoFlashcards = errors.New("no flashcards found")
 
-	var flashcard models.Flashcard
-	err := r.db.QueryRow(query).Scan(
-		&flashcard.ID,
-		&flashcard.Question,
-		&flashcard.Answer,
-		&flashcard.CreatedAt,
-		&flashcard.UpdatedAt,
-	)
+func (r *PostgresFlashcardRepository) GetRandom(filter *models.FlashcardFilter) (*models.Flashcard, error) {
+	conditions, args := filterConditions(filter, nil)
+	conditions = append(conditions, studyCondition)
+	query := `SELECT ` + flashcardColumns + ` FROM flashcards f` + whereClause(conditions) + ` ORDER BY RANDOM() LIMIT 1`
+
+	flashcard, err := scanFlashcard(r.db.QueryRow(query, args...))
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("no flashcards found")
+		return nil, ErrNoFlashcards
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &flashcard, nil
+	return flashcard, nil
+}
+
+// GetRandomByDeck returns a random flashcard from a deck or one of its sub-decks that is not waiting
+// for review.
+func (r *PostgresFlashcardRepository) GetRandomByDeck(deckID int) (*models.Flashcard, error) {
+	if err := r.ensureDeckExists(deckID); err != nil {
+		return nil, err
+	}
+
+	query := deckSubtreeCTE + `
+		SELECT ` + flashcardColumns + ` FROM flashcards f
+		WHERE f.deck_id IN (SELECT id FROM deck_tree) AND ` + studyCondition + ` ORDER BY RANDOM() LIMIT 1`
+
+	flashcard, err := scanFlashcard(r.db.QueryRow(query, deckID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w in deck %d", ErrNoFlashcards, deckID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return flashcard, nil
+}
+
+func (r *PostgresFlashcardRepository) queryFlashcards(query string, args ...any) ([]*models.Flashcard, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flashcards []*models.Flashcard
+	for rows.Next() {
+		flashcard, err := scanFlashcard(rows)
+		if err != nil {
+			return nil, err
+		}
+		flashcards = append(flashcards, flashcard)
+	}
+
+	return flashcards, rows.Err()
+}
+
+func (r *PostgresFlashcardRepository) ensureDeckExists(deckID int) error {
+	var exists bool
+	if err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM decks WHERE id = $1)`, deckID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("deck with id %d not found", deckID)
+	}
+	return nil
 }
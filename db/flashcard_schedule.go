@@ -0,0 +1,246 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+const scheduleColumns = `s.flashcard_id, s.scheduler, s.ease_factor, s.stability, s.difficulty, s.retrievability,
+	s.interval_days, s.repetitions, s.due_at, s.last_reviewed_at`
+
+// nullableSchedule receives the schedule columns of a row that may have been produced by a LEFT JOIN.
+type nullableSchedule struct {
+	flashcardID    sql.NullInt64
+	scheduler      sql.NullString
+	easeFactor     sql.NullFloat64
+	stability      sql.NullFloat64
+	difficulty     sql.NullFloat64
+	retrievability sql.NullFloat64
+	intervalDays   sql.NullInt64
+	repetitions    sql.NullInt64
+	dueAt          sql.NullTime
+	lastReviewedAt sql.NullTime
+}
+
+func (n *nullableSchedule) dest() []any {
+	return []any{
+		&n.flashcardID,
+		&n.scheduler,
+		&n.easeFactor,
+		&n.stability,
+		&n.difficulty,
+		&n.retrievability,
+		&n.intervalDays,
+		&n.repetitions,
+		&n.dueAt,
+		&n.lastReviewedAt,
+	}
+}
+
+// schedule returns the scanned schedule, or nil if the row had none.
+func (n *nullableSchedule) schedule() *models.CardSchedule {
+	if !n.flashcardID.Valid {
+		return nil
+	}
+
+	schedule := &models.CardSchedule{
+		FlashcardID:  int(n.flashcardID.Int64),
+		Scheduler:    n.scheduler.String,
+		EaseFactor:   n.easeFactor.Float64,
+		Stability:    n.stability.Float64,
+		Difficulty:   n.difficulty.Float64,
+		IntervalDays: int(n.intervalDays.Int64),
+		Repetitions:  int(n.repetitions.Int64),
+		DueAt:        n.dueAt.Time,
+	}
+	if n.retrievability.Valid {
+		schedule.Retrievability = &n.retrievability.Float64
+	}
+	if n.lastReviewedAt.Valid {
+		schedule.LastReviewedAt = &n.lastReviewedAt.Time
+	}
+
+	return schedule
+}
+
+// GetSchedule returns the schedule of a flashcard, or nil if the card has never been reviewed.
+func (r *PostgresFlashcardRepository) GetSchedule(flashcardID int) (*models.CardSchedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM flashcard_schedules s WHERE s.flashcard_id = $1`
+
+	var n nullableSchedule
+	err := r.db.QueryRow(query, flashcardID).Scan(n.dest()...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return n.schedule(), nil
+}
+
+var (
+	// ErrFlashcardNotActive is returned when a flashcard that is pending review or was rejected is
+	// reviewed.
+	ErrFlashcardNotActive = errors.New("flashcard is not active: it is pending review or was rejected")
+	// ErrScheduleChanged is returned by SaveReview when the flashcard has been reviewed since the
+	// schedule in the log's StateBefore was read.
+	ErrScheduleChanged = errors.New("flashcard was reviewed concurrently")
+)
+
+// SaveReview stores the new schedule of a flashcard and appends the review to its log in a single
+// transaction. The flashcard is locked while it is checked to be active and its schedule to still be
+// the log's StateBefore, so that concurrent reviews cannot overwrite each other. The log's ID is
+// populated on success.
+func (r *PostgresFlashcardRepository) SaveReview(schedule *models.CardSchedule, log *models.ReviewLog) error {
+	stateBefore, err := marshalNullableJSON(log.StateBefore)
+	if err != nil {
+		return err
+	}
+	stateAfter, err := json.Marshal(log.StateAfter)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var status string
+	err = tx.QueryRow(`SELECT status FROM flashcards WHERE id = $1 FOR UPDATE`, schedule.FlashcardID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("flashcard with id %d not found", schedule.FlashcardID)
+	}
+	if err != nil {
+		return err
+	}
+	if status != models.FlashcardStatusActive {
+		return ErrFlashcardNotActive
+	}
+
+	var lastReviewedAt sql.NullTime
+	err = tx.QueryRow(`SELECT last_reviewed_at FROM flashcard_schedules WHERE flashcard_id = $1`, schedule.FlashcardID).Scan(&lastReviewedAt)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	var readAt *time.Time
+	if log.StateBefore != nil {
+		readAt = log.StateBefore.LastReviewedAt
+	}
+	if lastReviewedAt.Valid != (readAt != nil) || (readAt != nil && !lastReviewedAt.Time.Equal(*readAt)) {
+		return ErrScheduleChanged
+	}
+
+	scheduleQuery := `INSERT INTO flashcard_schedules (flashcard_id, scheduler, ease_factor, stability, difficulty, retrievability,
+			interval_days, repetitions, due_at, last_reviewed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (flashcard_id) DO UPDATE SET
+			scheduler = EXCLUDED.scheduler,
+			ease_factor = EXCLUDED.ease_factor,
+			stability = EXCLUDED.stability,
+			difficulty = EXCLUDED.difficulty,
+			retrievability = EXCLUDED.retrievability,
+			interval_days = EXCLUDED.interval_days,
+			repetitions = EXCLUDED.repetitions,
+			due_at = EXCLUDED.due_at,
+			last_reviewed_at = EXCLUDED.last_reviewed_at`
+
+	_, err = tx.Exec(scheduleQuery,
+		schedule.FlashcardID,
+		schedule.Scheduler,
+		schedule.EaseFactor,
+		schedule.Stability,
+		schedule.Difficulty,
+		schedule.Retrievability,
+		schedule.IntervalDays,
+		schedule.Repetitions,
+		schedule.DueAt,
+		schedule.LastReviewedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	logQuery := `INSERT INTO review_logs (flashcard_id, user_id, grade, duration_ms, elapsed_days, scheduler, state_before, state_after, reviewed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+
+	err = tx.QueryRow(logQuery,
+		log.FlashcardID,
+		log.UserID,
+		log.Grade,
+		log.DurationMS,
+		log.ElapsedDays,
+		log.Scheduler,
+		stateBefore,
+		stateAfter,
+		log.ReviewedAt,
+	).Scan(&log.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetDue returns the flashcards due at the given time, most overdue first. Cards that have never been
+// reviewed are due from the moment they were created, and cards waiting for review are left out.
+func (r *PostgresFlashcardRepository) GetDue(now time.Time, limit int) ([]*models.ScheduledFlashcard, error) {
+	query := `SELECT ` + flashcardColumns + `, ` + scheduleColumns + `
+		FROM flashcards f
+		LEFT JOIN flashcard_schedules s ON s.flashcard_id = f.id
+		WHERE COALESCE(s.due_at, f.created_at) <= $1 AND ` + studyCondition + `
+		ORDER BY COALESCE(s.due_at, f.created_at) ASC, f.id ASC
+		LIMIT $2`
+
+	return r.queryScheduled(query, now, limit)
+}
+
+// GetDueByDeck is GetDue restricted to the flashcards of a deck and its sub-decks.
+func (r *PostgresFlashcardRepository) GetDueByDeck(deckID int, now time.Time, limit int) ([]*models.ScheduledFlashcard, error) {
+	if err := r.ensureDeckExists(deckID); err != nil {
+		return nil, err
+	}
+
+	query := deckSubtreeCTE + `
+		SELECT ` + flashcardColumns + `, ` + scheduleColumns + `
+		FROM flashcards f
+		LEFT JOIN flashcard_schedules s ON s.flashcard_id = f.id
+		WHERE f.deck_id IN (SELECT id FROM deck_tree) AND COALESCE(s.due_at, f.created_at) <= $2 AND ` + studyCondition + `
+		ORDER BY COALESCE(s.due_at, f.created_at) ASC, f.id ASC
+		LIMIT $3`
+
+	return r.queryScheduled(query, deckID, now, limit)
+}
+
+// queryScheduled runs a query selecting flashcardColumns followed by scheduleColumns.
+func (r *PostgresFlashcardRepository) queryScheduled(query string, args ...any) ([]*models.ScheduledFlashcard, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scheduled []*models.ScheduledFlashcard
+	for rows.Next() {
+		var flashcard models.Flashcard
+		var deckID sql.NullInt64
+		var n nullableSchedule
+		if err := rows.Scan(append(flashcardDest(&flashcard, &deckID), n.dest()...)...); err != nil {
+			return nil, err
+		}
+		setDeckID(&flashcard, deckID)
+
+		scheduled = append(scheduled, &models.ScheduledFlashcard{Flashcard: &flashcard, Schedule: n.schedule()})
+	}
+
+	return scheduled, rows.Err()
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/akolybelnikov/flashcards/models"
+
+	"github.com/lib/pq"
+)
+
+// FSRSParameterRepository stores per-user FSRS weights and provides the review history they are
+// fitted from.
+type FSRSParameterRepository interface {
+	Get(userID string) (*models.FSRSParameters, error)
+	Save(params *models.FSRSParameters) error
+	GetReviewHistory(userID string) ([]*models.ReviewLog, error)
+}
+
+type PostgresFSRSParameterRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresFSRSParameterRepository(db *sql.DB) *PostgresFSRSParameterRepository {
+	return &PostgresFSRSParameterRepository{db: db}
+}
+
+// Get returns the fitted weights of a user, or nil if none have been stored yet.
+func (r *PostgresFSRSParameterRepository) Get(userID string) (*models.FSRSParameters, error) {
+	query := `SELECT user_id, weights, log_loss, review_count, updated_at FROM fsrs_parameters WHERE user_id = $1`
+
+	var params models.FSRSParameters
+	err := r.db.QueryRow(query, userID).Scan(
+		&params.UserID,
+		pq.Array(&params.Weights),
+		&params.LogLoss,
+		&params.ReviewCount,
+		&params.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &params, nil
+}
+
+// Save inserts or replaces the weights of a user.
+func (r *PostgresFSRSParameterRepository) Save(params *models.FSRSParameters) error {
+	query := `INSERT INTO fsrs_parameters (user_id, weights, log_loss, review_count, updated_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id) DO UPDATE SET
+			weights = EXCLUDED.weights,
+			log_loss = EXCLUDED.log_loss,
+			review_count = EXCLUDED.review_count,
+			updated_at = EXCLUDED.updated_at
+		RETURNING updated_at`
+
+	return r.db.QueryRow(query,
+		params.UserID,
+		pq.Array(params.Weights),
+		params.LogLoss,
+		params.ReviewCount,
+	).Scan(&params.UpdatedAt)
+}
+
+// GetReviewHistory returns every review of a user grouped by flashcard, oldest first within each card.
+func (r *PostgresFSRSParameterRepository) GetReviewHistory(userID string) ([]*models.ReviewLog, error) {
+	query := `SELECT id, flashcard_id, user_id, grade, duration_ms, elapsed_days, scheduler, state_before, state_after, reviewed_at
+		FROM review_logs
+		WHERE user_id = $1
+		ORDER BY flashcard_id, reviewed_at, id`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*models.ReviewLog
+	for rows.Next() {
+		log, err := scanReviewLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
@@ -0,0 +1,329 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/akolybelnikov/flashcards/models"
+
+	"github.com/lib/pq"
+)
+
+type NoteRepository interface {
+	CreateType(req *models.CreateNoteTypeRequest) (*models.NoteType, error)
+	GetAllTypes() ([]*models.NoteType, error)
+	GetTypeByID(id int) (*models.NoteType, error)
+	UpdateType(id int, req *models.UpdateNoteTypeRequest, notes []models.RenderedNote) (*models.NoteType, error)
+	DeleteType(id int) error
+	Create(req *models.CreateNoteRequest, question, answer string) (*models.Note, error)
+	GetByID(id int) (*models.Note, error)
+	GetByType(noteTypeID int) ([]*models.Note, error)
+	Update(id int, req *models.UpdateNoteRequest, question, answer string) (*models.Note, error)
+	Delete(id int) error
+}
+
+// ErrNoteTypeInUse is returned when a note type that still has notes is deleted.
+var ErrNoteTypeInUse = errors.New("note type still has notes")
+
+// flashcardNoteColumn selects what the card of the flashcard aliased "f" is rendered from as a JSON
+// models.NoteContent, or NULL if it was not created from a note.
+const flashcardNoteColumn = `(SELECT json_build_object('fields', n.fields, 'front_template', nt.front_template, 'back_template', nt.back_template)
+		FROM notes n JOIN note_types nt ON nt.id = n.note_type_id WHERE n.id = f.note_id)`
+
+const noteTypeColumns = `id, name, fields, front_template, back_template, created_at, updated_at`
+
+type PostgresNoteRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresNoteRepository(db *sql.DB) *PostgresNoteRepository {
+	return &PostgresNoteRepository{db: db}
+}
+
+func scanNoteType(row rowScanner) (*models.NoteType, error) {
+	var noteType models.NoteType
+	err := row.Scan(
+		&noteType.ID,
+		&noteType.Name,
+		pq.Array(&noteType.Fields),
+		&noteType.FrontTemplate,
+		&noteType.BackTemplate,
+		&noteType.CreatedAt,
+		&noteType.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &noteType, nil
+}
+
+func (r *PostgresNoteRepository) CreateType(req *models.CreateNoteTypeRequest) (*models.NoteType, error) {
+	query := `INSERT INTO note_types (name, fields, front_template, back_template) VALUES ($1, $2, $3, $4)
+		RETURNING ` + noteTypeColumns
+
+	return scanNoteType(r.db.QueryRow(query, req.Name, pq.Array(req.Fields), req.FrontTemplate, req.BackTemplate))
+}
+
+func (r *PostgresNoteRepository) GetAllTypes() ([]*models.NoteType, error) {
+	rows, err := r.db.Query(`SELECT ` + noteTypeColumns + ` FROM note_types ORDER BY name, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var noteTypes []*models.NoteType
+	for rows.Next() {
+		noteType, err := scanNoteType(rows)
+		if err != nil {
+			return nil, err
+		}
+		noteTypes = append(noteTypes, noteType)
+	}
+
+	return noteTypes, rows.Err()
+}
+
+func (r *PostgresNoteRepository) GetTypeByID(id int) (*models.NoteType, error) {
+	noteType, err := scanNoteType(r.db.QueryRow(`SELECT `+noteTypeColumns+` FROM note_types WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("note type with id %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return noteType, nil
+}
+
+// UpdateType changes a note type and stores the cards of its notes re-rendered with the new
+// templates in the same transaction.
+func (r *PostgresNoteRepository) UpdateType(id int, req *models.UpdateNoteTypeRequest, notes []models.RenderedNote) (*models.NoteType, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `UPDATE note_types SET
+			name = COALESCE($1, name),
+			front_template = COALESCE($2, front_template),
+			back_template = COALESCE($3, back_template),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 RETURNING ` + noteTypeColumns
+	noteType, err := scanNoteType(tx.QueryRow(query, req.Name, req.FrontTemplate, req.BackTemplate, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("note type with id %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, note := range notes {
+		if err := updateNoteCard(tx, note.NoteID, note.Question, note.Answer); err != nil {
+			return nil, err
+		}
+	}
+
+	return noteType, tx.Commit()
+}
+
+func (r *PostgresNoteRepository) DeleteType(id int) error {
+	result, err := r.db.Exec(`DELETE FROM note_types WHERE id = $1`, id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pgForeignKeyViolation {
+		return ErrNoteTypeInUse
+	}
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("note type with id %d not found", id)
+	}
+
+	return nil
+}
+
+// Create inserts a note together with its card, rendered as question and answer.
+func (r *PostgresNoteRepository) Create(req *models.CreateNoteRequest, question, answer string) (*models.Note, error) {
+	fields, err := json.Marshal(req.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var id int
+	err = tx.QueryRow(`INSERT INTO notes (note_type_id, fields) VALUES ($1, $2) RETURNING id`, req.NoteTypeID, fields).Scan(&id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pgForeignKeyViolation {
+		return nil, fmt.Errorf("note type with id %d not found", req.NoteTypeID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cardID int
+	query := `INSERT INTO flashcards (question, answer, deck_id, question_lang, answer_lang, note_id)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6) RETURNING id`
+	err = tx.QueryRow(query, question, answer, req.DeckID, req.QuestionLang, req.AnswerLang, id).Scan(&cardID)
+	if err != nil {
+		return nil, deckError(err, req.DeckID)
+	}
+
+	if err := replaceTags(tx, cardID, req.Tags); err != nil {
+		return nil, err
+	}
+
+	note, err := getNote(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return note, tx.Commit()
+}
+
+func (r *PostgresNoteRepository) GetByID(id int) (*models.Note, error) {
+	return getNote(r.db, id)
+}
+
+// GetByType returns the notes of a note type without their cards.
+func (r *PostgresNoteRepository) GetByType(noteTypeID int) ([]*models.Note, error) {
+	rows, err := r.db.Query(`SELECT id, note_type_id, fields, created_at, updated_at FROM notes WHERE note_type_id = $1 ORDER BY id`, noteTypeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []*models.Note
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, rows.Err()
+}
+
+// Update replaces the field values or card tags of a note and stores its card rendered as question
+// and answer.
+func (r *PostgresNoteRepository) Update(id int, req *models.UpdateNoteRequest, question, answer string) (*models.Note, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var fields []byte
+	if req.Fields != nil {
+		if fields, err = json.Marshal(req.Fields); err != nil {
+			return nil, err
+		}
+	}
+
+	var locked int
+	query := `UPDATE notes SET fields = COALESCE($1, fields), updated_at = CURRENT_TIMESTAMP WHERE id = $2 RETURNING id`
+	err = tx.QueryRow(query, fields, id).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("note with id %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := updateNoteCard(tx, id, question, answer); err != nil {
+		return nil, err
+	}
+
+	if req.Tags != nil {
+		var cardID int
+		if err := tx.QueryRow(`SELECT id FROM flashcards WHERE note_id = $1`, id).Scan(&cardID); err != nil {
+			return nil, err
+		}
+		if err := replaceTags(tx, cardID, *req.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	note, err := getNote(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return note, tx.Commit()
+}
+
+func (r *PostgresNoteRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM notes WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("note with id %d not found", id)
+	}
+
+	return nil
+}
+
+// updateNoteCard stores the rendered question and answer of a note's card.
+func updateNoteCard(tx *sql.Tx, noteID int, question, answer string) error {
+	query := `UPDATE flashcards SET question = $1, answer = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE note_id = $3 AND (question <> $1 OR answer <> $2)`
+	_, err := tx.Exec(query, question, answer, noteID)
+	return err
+}
+
+func scanNote(row rowScanner) (*models.Note, error) {
+	var note models.Note
+	var fields []byte
+	if err := row.Scan(&note.ID, &note.NoteTypeID, &fields, &note.CreatedAt, &note.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(fields, &note.Fields); err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// getNote reads a note together with its card.
+func getNote(q queryer, id int) (*models.Note, error) {
+	note, err := scanNote(q.QueryRow(`SELECT id, note_type_id, fields, created_at, updated_at FROM notes WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("note with id %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	note.Card, err = scanFlashcard(q.QueryRow(`SELECT `+flashcardColumns+` FROM flashcards f WHERE f.note_id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
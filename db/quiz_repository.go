@@ -0,0 +1,190 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/models"
+
+	"github.com/lib/pq"
+)
+
+type QuizRepository interface {
+	GetQuizFlashcard(filter *models.FlashcardFilter, distractors int) (*models.Flashcard, error)
+	GetDistractors(flashcard *models.Flashcard, n int) ([]string, error)
+	Create(quiz *models.Quiz) error
+	GetByID(id string) (*models.Quiz, error)
+	SaveAnswer(id string, choice int) error
+	DeleteCreatedBefore(t time.Time) (int64, error)
+}
+
+var (
+	// ErrNoQuizFlashcard is returned when no flashcard has enough other answers in its language pair to
+	// draw distractors from.
+	ErrNoQuizFlashcard = errors.New("not enough flashcards in the same language pair to build a quiz")
+	// ErrQuizAnswered is returned when a quiz that has already been answered is answered again.
+	ErrQuizAnswered = errors.New("quiz has already been answered")
+)
+
+// samePairCondition matches active flashcards "o" in the same language pair as the flashcard aliased
+// "f" whose answer differs from its answer.
+const samePairCondition = `o.status = 'active'
+	AND COALESCE(o.question_lang, '') = COALESCE(f.question_lang, '')
+	AND COALESCE(o.answer_lang, '') = COALESCE(f.answer_lang, '')
+	AND lower(o.answer) <> lower(f.answer)`
+
+type PostgresQuizRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresQuizRepository(db *sql.DB) *PostgresQuizRepository {
+	return &PostgresQuizRepository{db: db}
+}
+
+// GetQuizFlashcard returns a random flashcard matching the filter whose language pair has at least
+// the given number of other answers to draw distractors from. The answers of each language pair are
+// counted once rather than for every candidate card.
+func (r *PostgresQuizRepository) GetQuizFlashcard(filter *models.FlashcardFilter, distractors int) (*models.Flashcard, error) {
+	conditions, args := filterConditions(filter, nil)
+	conditions = append(conditions, studyCondition)
+	// The card's own answer is one of the distinct answers of its pair
+	args = append(args, distractors)
+	conditions = append(conditions, fmt.Sprintf(`p.answers > $%d`, len(args)))
+	query := `WITH pairs AS (
+			SELECT COALESCE(question_lang, '') AS question_lang, COALESCE(answer_lang, '') AS answer_lang,
+				count(DISTINCT lower(answer)) AS answers
+			FROM flashcards
+			WHERE status = 'active'
+			GROUP BY 1, 2
+		)
+		SELECT ` + flashcardColumns + ` FROM flashcards f
+		JOIN pairs p ON p.question_lang = COALESCE(f.question_lang, '') AND p.answer_lang = COALESCE(f.answer_lang, '')` +
+		whereClause(conditions) + ` ORDER BY RANDOM() LIMIT 1`
+
+	flashcard, err := scanFlashcard(r.db.QueryRow(query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrNoQuizFlashcard
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return flashcard, nil
+}
+
+// GetDistractors returns up to n distinct wrong answers for a flashcard, taken from other flashcards
+// in its language pair. Answers of related cards, those sharing its tags or deck, come first, then
+// answers that are spelled most alike.
+func (r *PostgresQuizRepository) GetDistractors(flashcard *models.Flashcard, n int) ([]string, error) {
+	query := `SELECT answer FROM (
+			SELECT DISTINCT ON (lower(o.answer)) o.answer,
+				(SELECT count(*) FROM flashcard_tags a JOIN flashcard_tags b ON b.tag_id = a.tag_id
+					WHERE a.flashcard_id = o.id AND b.flashcard_id = f.id)
+					+ CASE WHEN o.deck_id = f.deck_id THEN 1 ELSE 0 END AS related,
+				similarity(o.answer, f.answer) AS similarity
+			FROM flashcards f
+			JOIN flashcards o ON ` + samePairCondition + `
+			WHERE f.id = $1
+			ORDER BY lower(o.answer), related DESC, similarity DESC
+		) candidates
+		ORDER BY related DESC, similarity DESC, RANDOM()
+		LIMIT $2`
+
+	rows, err := r.db.Query(query, flashcard.ID, n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var distractors []string
+	for rows.Next() {
+		var answer string
+		if err := rows.Scan(&answer); err != nil {
+			return nil, err
+		}
+		distractors = append(distractors, answer)
+	}
+
+	return distractors, rows.Err()
+}
+
+// Create stores a new quiz. Its CreatedAt is populated on success.
+func (r *PostgresQuizRepository) Create(quiz *models.Quiz) error {
+	query := `INSERT INTO quizzes (id, flashcard_id, choices, correct_index) VALUES ($1, $2, $3, $4) RETURNING created_at`
+
+	return r.db.QueryRow(query, quiz.ID, quiz.FlashcardID, pq.Array(quiz.Choices), quiz.CorrectIndex).Scan(&quiz.CreatedAt)
+}
+
+func (r *PostgresQuizRepository) GetByID(id string) (*models.Quiz, error) {
+	query := `SELECT q.id, q.flashcard_id, f.question, COALESCE(f.question_lang, ''), COALESCE(f.answer_lang, ''),
+			q.choices, q.correct_index, q.choice, q.answered_at, q.created_at
+		FROM quizzes q
+		JOIN flashcards f ON f.id = q.flashcard_id
+		WHERE q.id = $1`
+
+	var quiz models.Quiz
+	var choice sql.NullInt64
+	var answeredAt sql.NullTime
+	err := r.db.QueryRow(query, id).Scan(
+		&quiz.ID,
+		&quiz.FlashcardID,
+		&quiz.Question,
+		&quiz.QuestionLang,
+		&quiz.AnswerLang,
+		pq.Array(&quiz.Choices),
+		&quiz.CorrectIndex,
+		&choice,
+		&answeredAt,
+		&quiz.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("quiz with id %s not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if choice.Valid {
+		c := int(choice.Int64)
+		quiz.Choice = &c
+	}
+	if answeredAt.Valid {
+		quiz.AnsweredAt = &answeredAt.Time
+	}
+
+	return &quiz, nil
+}
+
+// SaveAnswer records the learner's choice. A quiz can only be answered once, so concurrent answers
+// cannot both be accepted.
+func (r *PostgresQuizRepository) SaveAnswer(id string, choice int) error {
+	query := `UPDATE quizzes SET choice = $1, answered_at = CURRENT_TIMESTAMP WHERE id = $2 AND answered_at IS NULL`
+
+	result, err := r.db.Exec(query, choice, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrQuizAnswered
+	}
+
+	return nil
+}
+
+// DeleteCreatedBefore deletes the quizzes created before t, answered or not, and returns how many
+// were deleted.
+func (r *PostgresQuizRepository) DeleteCreatedBefore(t time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM quizzes WHERE created_at < $1`, t)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// GetReviewLogs returns up to limit reviews of a flashcard, newest first. When beforeID is positive only
+// reviews with a smaller ID are returned, which lets callers page through the history.
+func (r *PostgresFlashcardRepository) GetReviewLogs(flashcardID int, beforeID int64, limit int) ([]*models.ReviewLog, error) {
+	query := `SELECT id, flashcard_id, user_id, grade, duration_ms, elapsed_days, scheduler, state_before, state_after, reviewed_at
+		FROM review_logs
+		WHERE flashcard_id = $1 AND ($2::bigint <= 0 OR id < $2::bigint)
+		ORDER BY id DESC
+		LIMIT $3`
+
+	rows, err := r.db.Query(query, flashcardID, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*models.ReviewLog
+	for rows.Next() {
+		log, err := scanReviewLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
+
+func scanReviewLog(row rowScanner) (*models.ReviewLog, error) {
+	var log models.ReviewLog
+	var (
+		durationMS  sql.NullInt64
+		elapsedDays sql.NullFloat64
+		stateBefore []byte
+		stateAfter  []byte
+	)
+	err := row.Scan(
+		&log.ID,
+		&log.FlashcardID,
+		&log.UserID,
+		&log.Grade,
+		&durationMS,
+		&elapsedDays,
+		&log.Scheduler,
+		&stateBefore,
+		&stateAfter,
+		&log.ReviewedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if durationMS.Valid {
+		ms := int(durationMS.Int64)
+		log.DurationMS = &ms
+	}
+	if elapsedDays.Valid {
+		log.ElapsedDays = &elapsedDays.Float64
+	}
+	if stateBefore != nil {
+		if err := json.Unmarshal(stateBefore, &log.StateBefore); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal(stateAfter, &log.StateAfter); err != nil {
+		return nil, err
+	}
+
+	return &log, nil
+}
+
+// marshalNullableJSON encodes v as JSON, or returns an untyped nil so that a nil pointer is stored as
+// SQL NULL.
+func marshalNullableJSON[T any](v *T) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
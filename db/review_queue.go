@@ -0,0 +1,109 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// ErrNotPendingReview is returned when a flashcard that is not waiting for review is approved or
+// rejected.
+var ErrNotPendingReview = errors.New("flashcard is not pending review")
+
+// GetPendingReview returns up to limit flashcards waiting for review, oldest first. Reverse cards are
+// reviewed together with the card they were generated from, so only the latter are listed.
+func (r *PostgresFlashcardRepository) GetPendingReview(limit int) ([]*models.Flashcard, error) {
+	query := `SELECT ` + flashcardColumns + ` FROM flashcards f
+		WHERE f.status = 'pending_review' AND f.reverse_of IS NULL
+		ORDER BY f.created_at ASC, f.id ASC
+		LIMIT $1`
+
+	return r.queryFlashcards(query, limit)
+}
+
+// Approve makes a flashcard waiting for review, and its reverse card, available for study and records
+// who approved it. A non-nil edit is applied first, in the same transaction.
+func (r *PostgresFlashcardRepository) Approve(id int, approvedBy string, approvedAt time.Time, edit *models.UpdateFlashcardRequest) (*models.Flashcard, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := lockPendingReview(tx, id); err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE flashcards SET status = 'active', approved_by = $1, approved_at = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3`
+	if _, err := tx.Exec(query, approvedBy, approvedAt, id); err != nil {
+		return nil, err
+	}
+
+	// Updating syncs the reverse card, which otherwise has to be synced here
+	if edit != nil {
+		if err := updateFlashcard(tx, id, edit); err != nil {
+			return nil, err
+		}
+	} else if _, err := tx.Exec(syncReverseCard, id); err != nil {
+		return nil, err
+	}
+
+	flashcard, err := scanFlashcard(tx.QueryRow(selectFlashcardByID, id))
+	if err != nil {
+		return nil, err
+	}
+
+	return flashcard, tx.Commit()
+}
+
+// Reject marks a flashcard waiting for review and its reverse card as rejected and records who
+// rejected them. Rejected cards are kept for the record but never studied.
+func (r *PostgresFlashcardRepository) Reject(id int, rejectedBy string, rejectedAt time.Time) (*models.Flashcard, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := lockPendingReview(tx, id); err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE flashcards SET status = 'rejected', rejected_by = $1, rejected_at = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 OR reverse_of = $3 OR id = (SELECT reverse_of FROM flashcards WHERE id = $3)`
+	if _, err := tx.Exec(query, rejectedBy, rejectedAt, id); err != nil {
+		return nil, err
+	}
+
+	flashcard, err := scanFlashcard(tx.QueryRow(selectFlashcardByID, id))
+	if err != nil {
+		return nil, err
+	}
+
+	return flashcard, tx.Commit()
+}
+
+// lockPendingReview locks a flashcard for the rest of the transaction and checks that it is waiting
+// for review.
+func lockPendingReview(tx *sql.Tx, id int) error {
+	var status string
+	err := tx.QueryRow(`SELECT status FROM flashcards WHERE id = $1 FOR UPDATE`, id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("flashcard with id %d not found", id)
+	}
+	if err != nil {
+		return err
+	}
+	if status != models.FlashcardStatusPendingReview {
+		return ErrNotPendingReview
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// searchConfigs maps the language of a search query to the text search configuration that parses it.
+// Both configurations fold case and diacritics.
+var searchConfigs = map[string]string{
+	"en": "flashcards_en",
+	"el": "flashcards_el",
+}
+
+// Snippets returned by Search delimit matched words with these control characters rather than HTML
+// tags, since the card text around them is not escaped.
+const (
+	SnippetStartSel = "\x02"
+	SnippetStopSel  = "\x03"
+)
+
+// headlineOptions delimits matched words with SnippetStartSel and SnippetStopSel and keeps snippets
+// short.
+const headlineOptions = `StartSel="` + SnippetStartSel + `", StopSel="` + SnippetStopSel + `", MaxFragments=2, MinWords=3, MaxWords=12`
+
+// Search runs a full-text search over questions and answers. The query uses web search syntax
+// (quoted phrases, "or", leading "-" to exclude) and is parsed in the given language. Results are
+// ordered by relevance, with question matches ranking above answer matches.
+func (r *PostgresFlashcardRepository) Search(query, lang string, limit int) ([]*models.SearchResult, error) {
+	config, ok := searchConfigs[lang]
+	if !ok {
+		return nil, fmt.Errorf("unsupported search language %q", lang)
+	}
+
+	sqlQuery := `SELECT ` + flashcardColumns + `,
+			ts_rank(f.search_vector, q) AS rank,
+			ts_headline($1::regconfig, f.question || ' — ' || f.answer, q, '` + headlineOptions + `')
+		FROM flashcards f, websearch_to_tsquery($1::regconfig, $2) q
+		WHERE f.search_vector @@ q
+		ORDER BY rank DESC, f.id ASC
+		LIMIT $3`
+
+	rows, err := r.db.Query(sqlQuery, config, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*models.SearchResult
+	for rows.Next() {
+		var flashcard models.Flashcard
+		var deckID sql.NullInt64
+		result := models.SearchResult{Flashcard: &flashcard}
+		if err := rows.Scan(append(flashcardDest(&flashcard, &deckID), &result.Rank, &result.Snippet)...); err != nil {
+			return nil, err
+		}
+		setDeckID(&flashcard, deckID)
+		results = append(results, &result)
+	}
+
+	return results, rows.Err()
+}
+
+// FuzzySearch finds flashcards whose question or answer is similar to the query by trigram
+// similarity, tolerating typos. Only matches scoring at least threshold are returned, most similar
+// first.
+func (r *PostgresFlashcardRepository) FuzzySearch(query string, threshold float64, limit int) ([]*models.SearchResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// The % operator can use the trigram indexes but compares against this setting rather than a
+	// parameter; set_config with is_local applies it to this transaction only.
+	if _, err := tx.Exec(`SELECT set_config('pg_trgm.similarity_threshold', $1, true)`, fmt.Sprint(threshold)); err != nil {
+		return nil, err
+	}
+
+	sqlQuery := `SELECT ` + flashcardColumns + `,
+			GREATEST(similarity(f.question, $1), similarity(f.answer, $1)) AS score
+		FROM flashcards f
+		WHERE f.question % $1 OR f.answer % $1
+		ORDER BY score DESC, f.id ASC
+		LIMIT $2`
+
+	rows, err := tx.Query(sqlQuery, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*models.SearchResult
+	for rows.Next() {
+		var flashcard models.Flashcard
+		var deckID sql.NullInt64
+		var score float64
+		if err := rows.Scan(append(flashcardDest(&flashcard, &deckID), &score)...); err != nil {
+			return nil, err
+		}
+		setDeckID(&flashcard, deckID)
+		results = append(results, &models.SearchResult{Flashcard: &flashcard, Rank: score, Score: &score})
+	}
+
+	return results, rows.Err()
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// flashcardTagsColumn aggregates the tags of the flashcard aliased "f" into a sorted text array.
+const flashcardTagsColumn = `COALESCE((
+		SELECT array_agg(t.name ORDER BY t.name)
+		FROM flashcard_tags ft JOIN tags t ON t.id = ft.tag_id
+		WHERE ft.flashcard_id = f.id
+	), '{}') AS tags`
+
+// hasTagCondition matches flashcards having at least one of the tags in the array parameter %s.
+const hasTagCondition = `EXISTS (
+		SELECT 1 FROM flashcard_tags ft JOIN tags t ON t.id = ft.tag_id
+		WHERE ft.flashcard_id = f.id AND t.name = ANY(%s)
+	)`
+
+// replaceTags sets the tags of a flashcard to exactly the given names, creating missing tags.
+func replaceTags(tx *sql.Tx, flashcardID int, tags []string) error {
+	if _, err := tx.Exec(`DELETE FROM flashcard_tags WHERE flashcard_id = $1`, flashcardID); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(`INSERT INTO tags (name) SELECT unnest($1::text[]) ON CONFLICT (name) DO NOTHING`, pq.Array(tags)); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO flashcard_tags (flashcard_id, tag_id) SELECT $1, id FROM tags WHERE name = ANY($2)`
+	_, err := tx.Exec(query, flashcardID, pq.Array(tags))
+	return err
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// TranslationCacheRepository stores translations returned by a language model until they expire.
+type TranslationCacheRepository interface {
+	Get(key models.TranslationCacheKey, now time.Time) (*models.Translation, time.Time, error)
+	Put(key models.TranslationCacheKey, translation *models.Translation, expiresAt time.Time) error
+	Purge(expiredBefore *time.Time) (int64, error)
+}
+
+type PostgresTranslationCacheRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTranslationCacheRepository(db *sql.DB) *PostgresTranslationCacheRepository {
+	return &PostgresTranslationCacheRepository{db: db}
+}
+
+// Get returns the cached translation for a key with the time it expires, or nil if there is none or
+// it expired before now.
+func (r *PostgresTranslationCacheRepository) Get(key models.TranslationCacheKey, now time.Time) (*models.Translation, time.Time, error) {
+	query := `SELECT translation, expires_at FROM translation_cache
+		WHERE text = $1 AND source_lang = $2 AND target_lang = $3 AND prompt_version = $4
+			AND provider = $5 AND model = $6 AND expires_at > $7`
+
+	var data []byte
+	var expiresAt time.Time
+	err := r.db.QueryRow(query, key.Text, key.SourceLang, key.TargetLang, key.PromptVersion, key.Provider, key.Model, now).Scan(&data, &expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var translation models.Translation
+	if err := json.Unmarshal(data, &translation); err != nil {
+		return nil, time.Time{}, err
+	}
+	return &translation, expiresAt, nil
+}
+
+// Put inserts or replaces the cached translation for a key.
+func (r *PostgresTranslationCacheRepository) Put(key models.TranslationCacheKey, translation *models.Translation, expiresAt time.Time) error {
+	data, err := json.Marshal(translation)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO translation_cache (text, source_lang, target_lang, prompt_version, provider, model, translation, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (text, source_lang, target_lang, prompt_version, provider, model) DO UPDATE SET
+			translation = EXCLUDED.translation,
+			created_at = CURRENT_TIMESTAMP,
+			expires_at = EXCLUDED.expires_at`
+
+	_, err = r.db.Exec(query, key.Text, key.SourceLang, key.TargetLang, key.PromptVersion, key.Provider, key.Model, data, expiresAt)
+	return err
+}
+
+// Purge deletes the cached translations that expired before the given time, or all of them if it is
+// nil, and returns how many were deleted.
+func (r *PostgresTranslationCacheRepository) Purge(expiredBefore *time.Time) (int64, error) {
+	query := `DELETE FROM translation_cache WHERE $1::timestamp IS NULL OR expires_at <= $1`
+
+	result, err := r.db.Exec(query, expiredBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
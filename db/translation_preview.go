@@ -0,0 +1,68 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// CreateTranslationPreview stores a translation preview and sets its creation time.
+func (r *PostgresFlashcardRepository) CreateTranslationPreview(preview *models.StoredTranslationPreview) error {
+	candidates, err := json.Marshal(preview.Candidates)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO translation_previews (id, source, translated_field, question_lang, answer_lang, candidates, model, prompt_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at`
+
+	return r.db.QueryRow(query, preview.ID, preview.Source, preview.TranslatedField, preview.QuestionLang,
+		preview.AnswerLang, candidates, preview.Model, preview.PromptVersion).Scan(&preview.CreatedAt)
+}
+
+// GetTranslationPreview returns a stored translation preview, expired or not.
+func (r *PostgresFlashcardRepository) GetTranslationPreview(id string) (*models.StoredTranslationPreview, error) {
+	query := `SELECT id, source, translated_field, question_lang, answer_lang, candidates, model, prompt_version, created_at
+		FROM translation_previews
+		WHERE id = $1`
+
+	var preview models.StoredTranslationPreview
+	var candidates []byte
+	err := r.db.QueryRow(query, id).Scan(
+		&preview.ID,
+		&preview.Source,
+		&preview.TranslatedField,
+		&preview.QuestionLang,
+		&preview.AnswerLang,
+		&candidates,
+		&preview.Model,
+		&preview.PromptVersion,
+		&preview.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("translation preview with id %s not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(candidates, &preview.Candidates); err != nil {
+		return nil, err
+	}
+
+	return &preview, nil
+}
+
+// DeleteTranslationPreviewsCreatedBefore deletes the translation previews created before t and returns
+// how many were deleted.
+func (r *PostgresFlashcardRepository) DeleteTranslationPreviewsCreatedBefore(t time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM translation_previews WHERE created_at < $1`, t)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -10,6 +10,7 @@ require (
 require (
 	github.com/joho/godotenv v1.5.1
 	github.com/tmc/langchaingo v0.1.14
+	golang.org/x/text v0.28.0
 )
 
 require (
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/akolybelnikov/flashcards/models"
+	"github.com/akolybelnikov/flashcards/services"
+
+	"github.com/gorilla/mux"
+)
+
+type ClozeNoteHandler struct {
+	service   services.ClozeNoteServiceInterface
+	languages *services.LanguageRegistry
+}
+
+func NewClozeNoteHandler(service services.ClozeNoteServiceInterface, languages *services.LanguageRegistry) *ClozeNoteHandler {
+	if service == nil {
+		panic("service is nil")
+	}
+	if languages == nil {
+		panic("language registry is nil")
+	}
+	return &ClozeNoteHandler{service: service, languages: languages}
+}
+
+func (h *ClozeNoteHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/cloze-notes", h.CreateClozeNote).Methods("POST")
+	router.HandleFunc("/cloze-notes/{id:[0-9]+}", h.GetClozeNote).Methods("GET")
+	router.HandleFunc("/cloze-notes/{id:[0-9]+}", h.UpdateClozeNote).Methods("PUT")
+	router.HandleFunc("/cloze-notes/{id:[0-9]+}", h.DeleteClozeNote).Methods("DELETE")
+}
+
+func (h *ClozeNoteHandler) CreateClozeNote(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateClozeNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	if req.Lang != "" {
+		if _, ok := h.languages.Lookup(req.Lang); !ok {
+			writeErrorResponse(w, http.StatusBadRequest, "Unsupported language: "+req.Lang)
+			return
+		}
+	}
+
+	note, err := h.service.CreateClozeNote(&req)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, note)
+}
+
+func (h *ClozeNoteHandler) GetClozeNote(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid cloze note ID")
+		return
+	}
+
+	note, err := h.service.GetClozeNote(id)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve cloze note")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, note)
+}
+
+func (h *ClozeNoteHandler) UpdateClozeNote(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid cloze note ID")
+		return
+	}
+
+	var req models.UpdateClozeNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	note, err := h.service.UpdateClozeNote(id, &req)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, note)
+}
+
+func (h *ClozeNoteHandler) DeleteClozeNote(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid cloze note ID")
+		return
+	}
+
+	if err := h.service.DeleteClozeNote(id); err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to delete cloze note")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akolybelnikov/flashcards/models"
+	"github.com/akolybelnikov/flashcards/services"
+	"github.com/gorilla/mux"
+)
+
+// mockClozeNoteService implements the ClozeNoteServiceInterface for handler tests.
+type mockClozeNoteService struct{}
+
+func (m *mockClozeNoteService) CreateClozeNote(req *models.CreateClozeNoteRequest) (*models.ClozeNote, error) {
+	cards, err := services.RenderCloze(req.Text)
+	if err != nil {
+		return nil, err
+	}
+	note := &models.ClozeNote{ID: 1, Text: req.Text, Lang: req.Lang}
+	for i, card := range cards {
+		index := card.Index
+		note.Cards = append(note.Cards, &models.Flashcard{ID: i + 1, Question: card.Question, Answer: card.Answer, ClozeNoteID: &note.ID, ClozeIndex: &index})
+	}
+	return note, nil
+}
+
+func (m *mockClozeNoteService) GetClozeNote(id int) (*models.ClozeNote, error) {
+	if id != 1 {
+		return nil, fmt.Errorf("cloze note with id %d not found", id)
+	}
+	return &models.ClozeNote{ID: 1, Cards: []*models.Flashcard{}}, nil
+}
+
+func (m *mockClozeNoteService) UpdateClozeNote(id int, req *models.UpdateClozeNoteRequest) (*models.ClozeNote, error) {
+	return m.GetClozeNote(id)
+}
+
+func (m *mockClozeNoteService) DeleteClozeNote(id int) error {
+	_, err := m.GetClozeNote(id)
+	return err
+}
+
+func TestCreateClozeNoteHandler(t *testing.T) {
+	r := mux.NewRouter()
+	NewClozeNoteHandler(&mockClozeNoteService{}, testLanguages(t)).RegisterRoutes(r)
+
+	b, _ := json.Marshal(map[string]string{"text": "Η {{c1::λέξη}} {{c2::είναι}}", "lang": "el"})
+	req := httptest.NewRequest("POST", "/cloze-notes", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var note models.ClozeNote
+	if err := json.NewDecoder(rr.Body).Decode(&note); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(note.Cards) != 2 || *note.Cards[1].ClozeIndex != 2 || note.Cards[1].Question != "Η λέξη [...]" {
+		t.Fatalf("unexpected cards: %+v", note.Cards)
+	}
+}
+
+func TestClozeNoteHandlerErrors(t *testing.T) {
+	r := mux.NewRouter()
+	NewClozeNoteHandler(&mockClozeNoteService{}, testLanguages(t)).RegisterRoutes(r)
+
+	tests := []struct {
+		method string
+		url    string
+		body   string
+		want   int
+	}{
+		{"POST", "/cloze-notes", `{"text": "no deletions"}`, http.StatusBadRequest},
+		{"POST", "/cloze-notes", `{"text": "{{c1::mot}}", "lang": "fr"}`, http.StatusBadRequest},
+		{"POST", "/cloze-notes", `{`, http.StatusBadRequest},
+		{"GET", "/cloze-notes/2", ``, http.StatusNotFound},
+		{"PUT", "/cloze-notes/2", `{"tags": []}`, http.StatusNotFound},
+		{"DELETE", "/cloze-notes/1", ``, http.StatusNoContent},
+		{"DELETE", "/cloze-notes/2", ``, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.url, bytes.NewReader([]byte(tt.body))))
+		if rr.Code != tt.want {
+			t.Errorf("%s %s %s: expected status %d, got %d", tt.method, tt.url, tt.body, tt.want, rr.Code)
+		}
+	}
+}
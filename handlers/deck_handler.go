@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+	"github.com/akolybelnikov/flashcards/services"
+
+	"github.com/gorilla/mux"
+)
+
+type DeckHandler struct {
+	service services.DeckServiceInterface
+}
+
+func NewDeckHandler(service services.DeckServiceInterface) *DeckHandler {
+	if service == nil {
+		panic("service is nil")
+	}
+	return &DeckHandler{service: service}
+}
+
+func (h *DeckHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/decks", h.CreateDeck).Methods("POST")
+	router.HandleFunc("/decks", h.GetAllDecks).Methods("GET")
+	router.HandleFunc("/decks/{id:[0-9]+}", h.GetDeckByID).Methods("GET")
+	router.HandleFunc("/decks/{id:[0-9]+}", h.UpdateDeck).Methods("PUT")
+	router.HandleFunc("/decks/{id:[0-9]+}", h.DeleteDeck).Methods("DELETE")
+	router.HandleFunc("/decks/{id:[0-9]+}/tree", h.GetDeckTree).Methods("GET")
+	router.HandleFunc("/decks/{id:[0-9]+}/move", h.MoveDeck).Methods("POST")
+}
+
+func (h *DeckHandler) CreateDeck(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateDeckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	deck, err := h.service.CreateDeck(&req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, deck)
+}
+
+func (h *DeckHandler) GetAllDecks(w http.ResponseWriter, _ *http.Request) {
+	decks, err := h.service.GetAllDecks()
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve decks")
+		return
+	}
+
+	if decks == nil {
+		decks = []*models.Deck{}
+	}
+
+	writeJSONResponse(w, http.StatusOK, decks)
+}
+
+func (h *DeckHandler) GetDeckByID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid deck ID")
+		return
+	}
+
+	deck, err := h.service.GetDeckByID(id)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve deck")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, deck)
+}
+
+func (h *DeckHandler) UpdateDeck(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid deck ID")
+		return
+	}
+
+	var req models.UpdateDeckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	deck, err := h.service.UpdateDeck(id, &req)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, deck)
+}
+
+func (h *DeckHandler) DeleteDeck(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid deck ID")
+		return
+	}
+
+	err = h.service.DeleteDeck(id)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to delete deck")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *DeckHandler) GetDeckTree(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid deck ID")
+		return
+	}
+
+	tree, err := h.service.GetDeckTree(id)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve deck tree")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, tree)
+}
+
+func (h *DeckHandler) MoveDeck(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid deck ID")
+		return
+	}
+
+	var req models.MoveDeckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	deck, err := h.service.MoveDeck(id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrDeckCycle):
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		case containsNotFoundFlashcard(err.Error()):
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		default:
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to move deck")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, deck)
+}
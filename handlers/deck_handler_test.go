@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+	"github.com/gorilla/mux"
+)
+
+// mockDeckService implements the DeckServiceInterface for handler tests and returns deterministic values.
+type mockDeckService struct{}
+
+func (m *mockDeckService) CreateDeck(req *models.CreateDeckRequest) (*models.Deck, error) {
+	if req.Name == "" {
+		return nil, errors.New("deck name cannot be empty")
+	}
+	now := time.Now()
+	return &models.Deck{ID: 1, Name: req.Name, Description: req.Description, CreatedAt: now, UpdatedAt: now}, nil
+}
+
+func (m *mockDeckService) GetAllDecks() ([]*models.Deck, error) {
+	now := time.Now()
+	return []*models.Deck{{ID: 1, Name: "Greek", CreatedAt: now, UpdatedAt: now}}, nil
+}
+
+func (m *mockDeckService) GetDeckByID(id int) (*models.Deck, error) {
+	if id != 1 {
+		return nil, errors.New("deck with id not found")
+	}
+	now := time.Now()
+	return &models.Deck{ID: 1, Name: "Greek", CreatedAt: now, UpdatedAt: now}, nil
+}
+
+func (m *mockDeckService) UpdateDeck(id int, req *models.UpdateDeckRequest) (*models.Deck, error) {
+	deck, err := m.GetDeckByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		deck.Name = *req.Name
+	}
+	return deck, nil
+}
+
+func (m *mockDeckService) DeleteDeck(id int) error {
+	_, err := m.GetDeckByID(id)
+	return err
+}
+
+func (m *mockDeckService) MoveDeck(id int, req *models.MoveDeckRequest) (*models.Deck, error) {
+	if req.ParentID != nil && *req.ParentID == id {
+		return nil, db.ErrDeckCycle
+	}
+	deck, err := m.GetDeckByID(id)
+	if err != nil {
+		return nil, err
+	}
+	deck.ParentID = req.ParentID
+	return deck, nil
+}
+
+func (m *mockDeckService) GetDeckTree(id int) (*models.DeckTree, error) {
+	deck, err := m.GetDeckByID(id)
+	if err != nil {
+		return nil, err
+	}
+	child := &models.DeckTree{Deck: models.Deck{ID: 2, ParentID: &deck.ID, Name: "Unit 1"}, Children: []*models.DeckTree{}}
+	return &models.DeckTree{Deck: *deck, Children: []*models.DeckTree{child}}, nil
+}
+
+func TestCreateDeckHandler(t *testing.T) {
+	h := NewDeckHandler(&mockDeckService{})
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	b, _ := json.Marshal(map[string]string{"name": "Greek A2"})
+	req := httptest.NewRequest("POST", "/decks", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", rr.Code)
+	}
+
+	var resp models.Deck
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Name != "Greek A2" {
+		t.Fatalf("expected name 'Greek A2', got '%s'", resp.Name)
+	}
+}
+
+func TestCreateDeckHandlerEmptyName(t *testing.T) {
+	h := NewDeckHandler(&mockDeckService{})
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("POST", "/decks", bytes.NewReader([]byte(`{"name": ""}`)))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rr.Code)
+	}
+}
+
+func TestGetDeckByIDNotFound(t *testing.T) {
+	h := NewDeckHandler(&mockDeckService{})
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/decks/2", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 Not Found, got %d", rr.Code)
+	}
+}
+
+func TestDeleteDeckHandler(t *testing.T) {
+	h := NewDeckHandler(&mockDeckService{})
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("DELETE", "/decks/1", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 No Content, got %d", rr.Code)
+	}
+}
+
+func TestGetDeckTreeHandler(t *testing.T) {
+	h := NewDeckHandler(&mockDeckService{})
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/decks/1/tree", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+
+	var resp models.DeckTree
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.ID != 1 || len(resp.Children) != 1 || resp.Children[0].Name != "Unit 1" {
+		t.Fatalf("unexpected tree: %+v", resp)
+	}
+}
+
+func TestMoveDeckHandlerRejectsCycle(t *testing.T) {
+	h := NewDeckHandler(&mockDeckService{})
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("POST", "/decks/1/move", bytes.NewReader([]byte(`{"parent_id": 1}`)))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 Bad Request, got %d", rr.Code)
+	}
+}
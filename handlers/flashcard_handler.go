@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/akolybelnikov/flashcards/db"
 	"github.com/akolybelnikov/flashcards/models"
 	"github.com/akolybelnikov/flashcards/services"
 
@@ -13,29 +15,47 @@ import (
 )
 
 type FlashcardHandler struct {
-	service services.FlashcardServiceInterface
+	service   services.FlashcardServiceInterface
+	languages *services.LanguageRegistry
 }
 
-func NewFlashcardHandler(service services.FlashcardServiceInterface) *FlashcardHandler {
+func NewFlashcardHandler(service services.FlashcardServiceInterface, languages *services.LanguageRegistry) *FlashcardHandler {
 	if service == nil {
 		panic("service is nil")
 	}
-	return &FlashcardHandler{service: service}
+	if languages == nil {
+		panic("language registry is nil")
+	}
+	return &FlashcardHandler{service: service, languages: languages}
 }
 
 func (h *FlashcardHandler) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc("/flashcards", h.CreateFlashcard).Methods("POST")
 	router.HandleFunc("/flashcards", h.GetAllFlashcards).Methods("GET")
+	router.HandleFunc("/flashcards/translate-preview", h.PreviewTranslation).Methods("POST")
 	router.HandleFunc("/flashcards/random", h.GetRandomFlashcard).Methods("GET")
+	router.HandleFunc("/flashcards/due", h.GetDueFlashcards).Methods("GET")
+	router.HandleFunc("/flashcards/search", h.SearchFlashcards).Methods("GET")
 	router.HandleFunc("/flashcards/{id:[0-9]+}", h.GetFlashcardByID).Methods("GET")
 	router.HandleFunc("/flashcards/{id:[0-9]+}", h.UpdateFlashcard).Methods("PUT")
 	router.HandleFunc("/flashcards/{id:[0-9]+}", h.DeleteFlashcard).Methods("DELETE")
+	router.HandleFunc("/flashcards/{id:[0-9]+}/review", h.ReviewFlashcard).Methods("POST")
+	router.HandleFunc("/flashcards/{id:[0-9]+}/answer", h.AnswerFlashcard).Methods("POST")
+	router.HandleFunc("/flashcards/{id:[0-9]+}/reviews", h.GetReviewLogs).Methods("GET")
+	router.HandleFunc("/flashcards/{id:[0-9]+}/examples", h.GenerateExamples).Methods("POST")
+	router.HandleFunc("/flashcards/{id:[0-9]+}/examples", h.GetExamples).Methods("GET")
+	router.HandleFunc("/review-queue", h.GetReviewQueue).Methods("GET")
+	router.HandleFunc("/review-queue/{id:[0-9]+}/approve", h.ApproveFlashcard).Methods("POST")
+	router.HandleFunc("/review-queue/{id:[0-9]+}/reject", h.RejectFlashcard).Methods("POST")
+	router.HandleFunc("/decks/{id:[0-9]+}/flashcards", h.GetFlashcardsByDeck).Methods("GET")
+	router.HandleFunc("/decks/{id:[0-9]+}/random", h.GetRandomFlashcardByDeck).Methods("GET")
+	router.HandleFunc("/decks/{id:[0-9]+}/due", h.GetDueFlashcardsByDeck).Methods("GET")
 }
 
 func (h *FlashcardHandler) CreateFlashcard(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateFlashcardRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
 		return
 	}
 
@@ -44,21 +64,36 @@ func (h *FlashcardHandler) CreateFlashcard(w http.ResponseWriter, r *http.Reques
 	answerEmpty := strings.TrimSpace(req.Answer) == ""
 
 	if questionEmpty && answerEmpty {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Both question and answer cannot be empty")
+		writeErrorResponse(w, http.StatusBadRequest, "Both question and answer cannot be empty")
 		return
 	}
 
 	// If either field is empty (translation needed), we need BOTH language fields
 	if questionEmpty || answerEmpty {
 		if req.QuestionLang == "" || req.AnswerLang == "" {
-			h.writeErrorResponse(w, http.StatusBadRequest, "Both question_lang and answer_lang are required when translation is needed")
+			writeErrorResponse(w, http.StatusBadRequest, "Both question_lang and answer_lang are required when translation is needed")
 			return
 		}
 	}
 
+	// Languages must be registered so that they can be translated and graded
+	if lang, ok := h.unsupportedLanguage(req.QuestionLang, req.AnswerLang); ok {
+		writeErrorResponse(w, http.StatusBadRequest, "Unsupported language: "+lang)
+		return
+	}
+
 	flashcard, aiUsed, translatedField, err := h.service.CreateFlashcard(&req)
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		switch {
+		case errors.Is(err, services.ErrAIUnavailable):
+			writeErrorResponse(w, http.StatusServiceUnavailable, err.Error())
+		case errors.Is(err, services.ErrTranslationFailed):
+			writeErrorResponse(w, http.StatusBadGateway, err.Error())
+		case errors.Is(err, services.ErrTranslationPreviewExpired):
+			writeErrorResponse(w, http.StatusGone, err.Error())
+		default:
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		}
 		return
 	}
 
@@ -66,28 +101,120 @@ func (h *FlashcardHandler) CreateFlashcard(w http.ResponseWriter, r *http.Reques
 		Flashcard:         flashcard,
 		AITranslationUsed: aiUsed,
 		TranslatedField:   translatedField,
+		Warnings:          req.Warnings,
 	}
 
-	h.writeJSONResponse(w, http.StatusCreated, response)
+	writeJSONResponse(w, http.StatusCreated, response)
 }
 
-func (h *FlashcardHandler) GetAllFlashcards(w http.ResponseWriter, _ *http.Request) {
-	flashcards, err := h.service.GetAllFlashcards()
+func (h *FlashcardHandler) PreviewTranslation(w http.ResponseWriter, r *http.Request) {
+	var req models.TranslatePreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	if strings.TrimSpace(req.Question) == "" {
+		req.Question = ""
+	}
+	if strings.TrimSpace(req.Answer) == "" {
+		req.Answer = ""
+	}
+	if req.QuestionLang == "" || req.AnswerLang == "" {
+		writeErrorResponse(w, http.StatusBadRequest, "Both question_lang and answer_lang are required")
+		return
+	}
+	if lang, ok := h.unsupportedLanguage(req.QuestionLang, req.AnswerLang); ok {
+		writeErrorResponse(w, http.StatusBadRequest, "Unsupported language: "+lang)
+		return
+	}
+
+	preview, err := h.service.PreviewTranslation(&req)
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve flashcards")
+		switch {
+		case errors.Is(err, services.ErrTranslationSide), errors.Is(err, services.ErrInvalidCandidateCount), errors.Is(err, services.ErrInvalidLanguageTag):
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrAIUnavailable):
+			writeErrorResponse(w, http.StatusServiceUnavailable, err.Error())
+		default:
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to translate")
+		}
 		return
 	}
 
-	h.writeJSONResponse(w, http.StatusOK, flashcards)
+	writeJSONResponse(w, http.StatusOK, preview)
+}
+
+// unsupportedLanguage returns the first non-empty language code that is not in the registry.
+func (h *FlashcardHandler) unsupportedLanguage(langs ...string) (string, bool) {
+	for _, lang := range langs {
+		if lang == "" {
+			continue
+		}
+		if _, ok := h.languages.Lookup(lang); !ok {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+func (h *FlashcardHandler) GetAllFlashcards(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFlashcardFilter(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit, err := parseOptionalInt(r, "limit")
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+
+	query := r.URL.Query()
+	opts := &models.FlashcardListOptions{
+		Filter: filter,
+		Sort:   query.Get("sort"),
+		Order:  query.Get("order"),
+		Limit:  limit,
+	}
+
+	page, err := h.service.GetAllFlashcards(opts, query.Get("cursor"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid cursor")
+		} else if errors.Is(err, services.ErrInvalidSort) {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve flashcards")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, page)
 }
 
 func (h *FlashcardHandler) GetRandomFlashcard(w http.ResponseWriter, r *http.Request) {
 	// Optional query param 'lang' for desired AI hint language (e.g., 'el' for Greek)
 	lang := r.URL.Query().Get("lang")
+	if lang, ok := h.unsupportedLanguage(lang); ok {
+		writeErrorResponse(w, http.StatusBadRequest, "Unsupported language: "+lang)
+		return
+	}
 
-	flashcard, err := h.service.GetRandomFlashcard()
+	filter, err := parseFlashcardFilter(r)
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve random flashcard")
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	flashcard, err := h.service.GetRandomFlashcard(filter)
+	if err != nil {
+		if errors.Is(err, db.ErrNoFlashcards) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve random flashcard")
+		}
 		return
 	}
 
@@ -100,71 +227,71 @@ func (h *FlashcardHandler) GetRandomFlashcard(w http.ResponseWriter, r *http.Req
 		AIHint:    aiHint,
 	}
 
-	h.writeJSONResponse(w, http.StatusOK, resp)
+	writeJSONResponse(w, http.StatusOK, resp)
 }
 
 func (h *FlashcardHandler) GetFlashcardByID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid flashcard ID")
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid flashcard ID")
 		return
 	}
 
 	flashcard, err := h.service.GetFlashcardByID(id)
 	if err != nil {
 		if containsNotFoundFlashcard(err.Error()) {
-			h.writeErrorResponse(w, http.StatusNotFound, err.Error())
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
 		} else {
-			h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve flashcard")
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve flashcard")
 		}
 		return
 	}
 
-	h.writeJSONResponse(w, http.StatusOK, flashcard)
+	writeJSONResponse(w, http.StatusOK, flashcard)
 }
 
 func (h *FlashcardHandler) UpdateFlashcard(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid flashcard ID")
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid flashcard ID")
 		return
 	}
 
 	var req models.UpdateFlashcardRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
 		return
 	}
 
 	flashcard, err := h.service.UpdateFlashcard(id, &req)
 	if err != nil {
 		if containsNotFoundFlashcard(err.Error()) {
-			h.writeErrorResponse(w, http.StatusNotFound, err.Error())
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
 		} else {
-			h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
 		}
 		return
 	}
 
-	h.writeJSONResponse(w, http.StatusOK, flashcard)
+	writeJSONResponse(w, http.StatusOK, flashcard)
 }
 
 func (h *FlashcardHandler) DeleteFlashcard(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		h.writeErrorResponse(w, http.StatusBadRequest, "Invalid flashcard ID")
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid flashcard ID")
 		return
 	}
 
 	err = h.service.DeleteFlashcard(id)
 	if err != nil {
 		if containsNotFoundFlashcard(err.Error()) {
-			h.writeErrorResponse(w, http.StatusNotFound, err.Error())
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
 		} else {
-			h.writeErrorResponse(w, http.StatusInternalServerError, "Failed to delete flashcard")
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to delete flashcard")
 		}
 		return
 	}
@@ -172,24 +299,423 @@ func (h *FlashcardHandler) DeleteFlashcard(w http.ResponseWriter, r *http.Reques
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *FlashcardHandler) writeJSONResponse(w http.ResponseWriter, statusCode int, data any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	err := json.NewEncoder(w).Encode(data)
+func (h *FlashcardHandler) ReviewFlashcard(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid flashcard ID")
+		return
+	}
+
+	var req models.ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	if req.Grade == nil {
+		writeErrorResponse(w, http.StatusBadRequest, "grade is required")
+		return
+	}
+
+	scheduled, err := h.service.ReviewFlashcard(id, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrFlashcardNotActive) || errors.Is(err, db.ErrScheduleChanged) {
+			writeErrorResponse(w, http.StatusConflict, err.Error())
+		} else if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, scheduled)
+}
+
+func (h *FlashcardHandler) AnswerFlashcard(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid flashcard ID")
+		return
+	}
+
+	var req models.AnswerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	result, err := h.service.AnswerFlashcard(id, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrEmptyAnswer) {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if errors.Is(err, services.ErrFlashcardNotActive) || errors.Is(err, db.ErrScheduleChanged) {
+			writeErrorResponse(w, http.StatusConflict, err.Error())
+		} else if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		}
 		return
 	}
+
+	writeJSONResponse(w, http.StatusOK, result)
 }
 
-func (h *FlashcardHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	err := json.NewEncoder(w).Encode(map[string]string{"error": message})
+func (h *FlashcardHandler) GetDueFlashcards(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseOptionalInt(r, "limit")
 	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid limit")
 		return
 	}
+
+	due, err := h.service.GetDueFlashcards(limit)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve due flashcards")
+		return
+	}
+
+	if due == nil {
+		due = []*models.ScheduledFlashcard{}
+	}
+
+	writeJSONResponse(w, http.StatusOK, due)
 }
 
-func containsNotFoundFlashcard(message string) bool {
-	return strings.Contains(message, "not found") || strings.Contains(message, "flashcard with id")
+func (h *FlashcardHandler) SearchFlashcards(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseOptionalInt(r, "limit")
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+
+	query := r.URL.Query()
+	req := &models.SearchRequest{
+		Query: query.Get("q"),
+		Mode:  query.Get("mode"),
+		Limit: limit,
+	}
+	if threshold := query.Get("threshold"); threshold != "" {
+		value, err := strconv.ParseFloat(threshold, 64)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid threshold")
+			return
+		}
+		req.Threshold = &value
+	}
+
+	results, err := h.service.SearchFlashcards(req)
+	if err != nil {
+		if errors.Is(err, services.ErrEmptySearchQuery) || errors.Is(err, services.ErrInvalidSearch) {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to search flashcards")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, results)
+}
+
+func (h *FlashcardHandler) GetReviewLogs(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid flashcard ID")
+		return
+	}
+
+	limit, err := parseOptionalInt(r, "limit")
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+
+	page, err := h.service.GetReviewLogs(id, limit, r.URL.Query().Get("cursor"))
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, services.ErrInvalidCursor) {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid cursor")
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve review history")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, page)
+}
+
+func (h *FlashcardHandler) GenerateExamples(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid flashcard ID")
+		return
+	}
+
+	n, err := parseOptionalInt(r, "n")
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid n")
+		return
+	}
+
+	req := &models.GenerateExamplesRequest{N: n}
+	if regenerate := r.URL.Query().Get("regenerate"); regenerate != "" {
+		req.Regenerate, err = strconv.ParseBool(regenerate)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid regenerate")
+			return
+		}
+	}
+
+	examples, err := h.service.GenerateExamples(id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidExampleCount), errors.Is(err, services.ErrExampleLanguages):
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrAIUnavailable):
+			writeErrorResponse(w, http.StatusServiceUnavailable, err.Error())
+		case containsNotFoundFlashcard(err.Error()):
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		default:
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to generate examples")
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if examples.Generated {
+		status = http.StatusCreated
+	}
+	writeJSONResponse(w, status, examples)
+}
+
+func (h *FlashcardHandler) GetExamples(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid flashcard ID")
+		return
+	}
+
+	examples, err := h.service.GetExamples(id)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve examples")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, examples)
+}
+
+func (h *FlashcardHandler) GetReviewQueue(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseOptionalInt(r, "limit")
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+
+	flashcards, err := h.service.GetReviewQueue(limit)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve review queue")
+		return
+	}
+
+	if flashcards == nil {
+		flashcards = []*models.Flashcard{}
+	}
+
+	writeJSONResponse(w, http.StatusOK, flashcards)
+}
+
+func (h *FlashcardHandler) ApproveFlashcard(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid flashcard ID")
+		return
+	}
+
+	var req models.ApproveFlashcardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	flashcard, err := h.service.ApproveFlashcard(id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrNotPendingReview):
+			writeErrorResponse(w, http.StatusConflict, err.Error())
+		case containsNotFoundFlashcard(err.Error()):
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		default:
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, flashcard)
+}
+
+func (h *FlashcardHandler) RejectFlashcard(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid flashcard ID")
+		return
+	}
+
+	var req models.RejectFlashcardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	flashcard, err := h.service.RejectFlashcard(id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMissingRejecter):
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, db.ErrNotPendingReview):
+			writeErrorResponse(w, http.StatusConflict, err.Error())
+		case containsNotFoundFlashcard(err.Error()):
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		default:
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to reject flashcard")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, flashcard)
+}
+
+func (h *FlashcardHandler) GetFlashcardsByDeck(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	deckID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid deck ID")
+		return
+	}
+
+	flashcards, err := h.service.GetFlashcardsByDeck(deckID)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve flashcards")
+		}
+		return
+	}
+
+	if flashcards == nil {
+		flashcards = []*models.Flashcard{}
+	}
+
+	writeJSONResponse(w, http.StatusOK, flashcards)
+}
+
+func (h *FlashcardHandler) GetRandomFlashcardByDeck(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	deckID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid deck ID")
+		return
+	}
+
+	lang := r.URL.Query().Get("lang")
+	if lang, ok := h.unsupportedLanguage(lang); ok {
+		writeErrorResponse(w, http.StatusBadRequest, "Unsupported language: "+lang)
+		return
+	}
+
+	flashcard, err := h.service.GetRandomFlashcardByDeck(deckID)
+	if err != nil {
+		if errors.Is(err, db.ErrNoFlashcards) || containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve random flashcard")
+		}
+		return
+	}
+
+	resp := models.RandomFlashcardResponse{
+		Flashcard: flashcard,
+		AIHint:    h.service.GenerateAIHint(flashcard, lang),
+	}
+
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+func (h *FlashcardHandler) GetDueFlashcardsByDeck(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	deckID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid deck ID")
+		return
+	}
+
+	limit, err := parseOptionalInt(r, "limit")
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+
+	due, err := h.service.GetDueFlashcardsByDeck(deckID, limit)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve due flashcards")
+		}
+		return
+	}
+
+	if due == nil {
+		due = []*models.ScheduledFlashcard{}
+	}
+
+	writeJSONResponse(w, http.StatusOK, due)
+}
+
+// parseFlashcardFilter reads the filter query parameters shared by the flashcard list endpoints.
+func parseFlashcardFilter(r *http.Request) (*models.FlashcardFilter, error) {
+	query := r.URL.Query()
+
+	tags, err := services.ParseTagFilter(query["tag"])
+	if err != nil {
+		return nil, err
+	}
+	filter := &models.FlashcardFilter{Tags: tags}
+
+	langs := []struct {
+		param string
+		dest  *string
+	}{
+		{"question_lang", &filter.QuestionLang},
+		{"answer_lang", &filter.AnswerLang},
+	}
+	for _, lang := range langs {
+		if value := query.Get(lang.param); value != "" {
+			if *lang.dest, err = services.CanonicalLanguageTag(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if value := query.Get("ai_generated"); value != "" {
+		aiGenerated, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("ai_generated must be true or false")
+		}
+		filter.AIGenerated = &aiGenerated
+	}
+
+	return filter, nil
 }
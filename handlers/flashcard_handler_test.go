@@ -4,19 +4,54 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/akolybelnikov/flashcards/db"
 	"github.com/akolybelnikov/flashcards/models"
+	"github.com/akolybelnikov/flashcards/services"
 	"github.com/gorilla/mux"
 )
 
+// testLanguages returns a registry with English and Greek.
+func testLanguages(t *testing.T) *services.LanguageRegistry {
+	t.Helper()
+	registry, err := services.NewLanguageRegistry([]models.Language{
+		{Code: "en", Name: "English", NativeName: "English", Script: "Latn"},
+		{Code: "el", Name: "Greek", NativeName: "Ελληνικά", Script: "Grek", DiacriticsSignificant: true},
+	})
+	if err != nil {
+		t.Fatalf("failed to create language registry: %v", err)
+	}
+	return registry
+}
+
 // mockService implements the FlashcardServiceInterface for handler tests and returns deterministic values.
-type mockService struct{}
+type mockService struct {
+	lastFilter      *models.FlashcardFilter
+	lastListOptions *models.FlashcardListOptions
+	lastSearch      *models.SearchRequest
+}
 
 func (m *mockService) CreateFlashcard(req *models.CreateFlashcardRequest) (*models.Flashcard, bool, string, error) {
+	switch req.Question {
+	case "unavailable":
+		return nil, false, "", services.ErrAIUnavailable
+	case "timeout":
+		return nil, false, "", fmt.Errorf("%w question to answer: timeout", services.ErrTranslationFailed)
+	case "#bad tag":
+		return nil, false, "", services.ErrInvalidTag
+	}
+	if req.PreviewID == "expired" {
+		return nil, false, "", services.ErrTranslationPreviewExpired
+	}
+	if req.EnrichGrammar {
+		req.Warnings = append(req.Warnings, "grammar not enriched")
+	}
+
 	now := time.Now()
 	fc := &models.Flashcard{ID: 1, Question: req.Question, Answer: req.Answer, CreatedAt: now, UpdatedAt: now}
 
@@ -37,9 +72,37 @@ func (m *mockService) CreateFlashcard(req *models.CreateFlashcardRequest) (*mode
 	return fc, aiUsed, translatedField, nil
 }
 
-func (m *mockService) GetAllFlashcards() ([]*models.Flashcard, error) {
+func (m *mockService) PreviewTranslation(req *models.TranslatePreviewRequest) (*models.TranslatePreview, error) {
+	if req.Question != "" && req.Answer != "" {
+		return nil, services.ErrTranslationSide
+	}
+	if req.N > 5 {
+		return nil, services.ErrInvalidCandidateCount
+	}
+	return &models.TranslatePreview{
+		Source:          req.Question,
+		TranslatedField: "answer",
+		Candidates: []models.TranslationCandidate{
+			{Translation: "βιβλίο", PartOfSpeech: "noun", Usage: "a book to read"},
+			{Translation: "κλείνω", PartOfSpeech: "verb", Usage: "to book a table"},
+		},
+	}, nil
+}
+
+func (m *mockService) GetAllFlashcards(opts *models.FlashcardListOptions, cursor string) (*models.FlashcardPage, error) {
+	if cursor == "bad" {
+		return nil, services.ErrInvalidCursor
+	}
+	if opts.Sort == "bogus" {
+		return nil, services.ErrInvalidSort
+	}
+	m.lastListOptions = opts
+	m.lastFilter = opts.Filter
 	now := time.Now()
-	return []*models.Flashcard{{ID: 1, Question: "q", Answer: "a", CreatedAt: now, UpdatedAt: now}}, nil
+	return &models.FlashcardPage{
+		Flashcards: []*models.Flashcard{{ID: 1, Question: "q", Answer: "a", CreatedAt: now, UpdatedAt: now}},
+		NextCursor: "next",
+	}, nil
 }
 
 func (m *mockService) GetFlashcardByID(id int) (*models.Flashcard, error) {
@@ -73,7 +136,11 @@ func (m *mockService) DeleteFlashcard(id int) error {
 	return nil
 }
 
-func (m *mockService) GetRandomFlashcard() (*models.Flashcard, error) {
+func (m *mockService) GetRandomFlashcard(filter *models.FlashcardFilter) (*models.Flashcard, error) {
+	m.lastFilter = filter
+	if filter != nil && filter.QuestionLang == "fr" {
+		return nil, db.ErrNoFlashcards
+	}
 	now := time.Now()
 	return &models.Flashcard{ID: 1, Question: "hello", Answer: "γεια σασ", CreatedAt: now, UpdatedAt: now}, nil
 }
@@ -83,10 +150,157 @@ func (m *mockService) GenerateAIHint(_ *models.Flashcard, _ string) *string {
 	return &h
 }
 
+func (m *mockService) ReviewFlashcard(id int, req *models.ReviewRequest) (*models.ScheduledFlashcard, error) {
+	if id == 3 {
+		return nil, services.ErrFlashcardNotActive
+	}
+	if id == 5 {
+		return nil, db.ErrScheduleChanged
+	}
+	if id != 1 {
+		return nil, errors.New("flashcard with id not found")
+	}
+	if *req.Grade < 0 || *req.Grade > 5 {
+		return nil, errors.New("grade must be between 0 and 5")
+	}
+	now := time.Now()
+	return &models.ScheduledFlashcard{
+		Flashcard: &models.Flashcard{ID: 1, Question: "q", Answer: "a", CreatedAt: now, UpdatedAt: now},
+		Schedule:  &models.CardSchedule{FlashcardID: 1, EaseFactor: 2.5, IntervalDays: 1, Repetitions: 1, DueAt: now.AddDate(0, 0, 1)},
+	}, nil
+}
+
+func (m *mockService) GetDueFlashcards(_ int) ([]*models.ScheduledFlashcard, error) {
+	now := time.Now()
+	return []*models.ScheduledFlashcard{
+		{Flashcard: &models.Flashcard{ID: 1, Question: "q", Answer: "a", CreatedAt: now, UpdatedAt: now}},
+	}, nil
+}
+
+func (m *mockService) GetReviewLogs(id int, _ int, cursor string) (*models.ReviewLogPage, error) {
+	if id != 1 {
+		return nil, errors.New("flashcard with id not found")
+	}
+	if cursor == "bad" {
+		return nil, services.ErrInvalidCursor
+	}
+	return &models.ReviewLogPage{
+		Reviews:    []*models.ReviewLog{{ID: 2, FlashcardID: 1, Grade: 4, Scheduler: "sm2", ReviewedAt: time.Now()}},
+		NextCursor: "2",
+	}, nil
+}
+
+func (m *mockService) SearchFlashcards(req *models.SearchRequest) ([]*models.SearchResult, error) {
+	if req.Query == "" {
+		return nil, services.ErrEmptySearchQuery
+	}
+	m.lastSearch = req
+	now := time.Now()
+	fc := &models.Flashcard{ID: 1, Question: "hello", Answer: "γεια σας", CreatedAt: now, UpdatedAt: now}
+	return []*models.SearchResult{{Flashcard: fc, Rank: 0.6, Snippet: "hello — <mark>γεια</mark> σας"}}, nil
+}
+
+func (m *mockService) AnswerFlashcard(id int, req *models.AnswerRequest) (*models.AnswerResult, error) {
+	if id == 3 {
+		return nil, services.ErrFlashcardNotActive
+	}
+	if id != 1 {
+		return nil, errors.New("flashcard with id not found")
+	}
+	if req.Answer == "" {
+		return nil, services.ErrEmptyAnswer
+	}
+	return &models.AnswerResult{
+		Verdict:      models.VerdictAlmost,
+		Expected:     "γεια σας",
+		Diff:         []models.DiffSegment{{Op: models.DiffEqual, Text: "γεια σα"}, {Op: models.DiffDelete, Text: "σ"}, {Op: models.DiffInsert, Text: "ς"}},
+		Explanations: []string{"Use the final sigma ς at the end of a word"},
+		Grade:        3,
+		Schedule:     &models.CardSchedule{FlashcardID: id, Scheduler: "sm2", IntervalDays: 1, DueAt: time.Now()},
+	}, nil
+}
+
+func (m *mockService) GetExamples(id int) (*models.CardExamples, error) {
+	if id != 1 {
+		return nil, errors.New("flashcard with id not found")
+	}
+	return &models.CardExamples{FlashcardID: id, Examples: []*models.CardExample{}}, nil
+}
+
+func (m *mockService) GenerateExamples(id int, req *models.GenerateExamplesRequest) (*models.CardExamples, error) {
+	switch {
+	case req.N > 10:
+		return nil, services.ErrInvalidExampleCount
+	case id == 3:
+		return nil, services.ErrAIUnavailable
+	case id != 1:
+		return nil, errors.New("flashcard with id not found")
+	}
+	example := &models.CardExample{ID: 1, FlashcardID: id, ExampleSentence: models.ExampleSentence{Sentence: "Το σπίτι μου.", Translation: "My house.", Level: "A1"}}
+	return &models.CardExamples{FlashcardID: id, Examples: []*models.CardExample{example}, Generated: req.Regenerate}, nil
+}
+
+func (m *mockService) GetFlashcardsByDeck(deckID int) ([]*models.Flashcard, error) {
+	if deckID != 1 {
+		return nil, errors.New("deck with id not found")
+	}
+	now := time.Now()
+	return []*models.Flashcard{{ID: 1, DeckID: &deckID, Question: "q", Answer: "a", CreatedAt: now, UpdatedAt: now}}, nil
+}
+
+func (m *mockService) GetRandomFlashcardByDeck(deckID int) (*models.Flashcard, error) {
+	if deckID == 4 {
+		return nil, fmt.Errorf("%w in deck %d", db.ErrNoFlashcards, deckID)
+	}
+	if deckID != 1 {
+		return nil, errors.New("deck with id not found")
+	}
+	now := time.Now()
+	return &models.Flashcard{ID: 1, DeckID: &deckID, Question: "hello", Answer: "γεια σασ", CreatedAt: now, UpdatedAt: now}, nil
+}
+
+func (m *mockService) GetDueFlashcardsByDeck(deckID int, limit int) ([]*models.ScheduledFlashcard, error) {
+	if deckID != 1 {
+		return nil, errors.New("deck with id not found")
+	}
+	return m.GetDueFlashcards(limit)
+}
+
+func (m *mockService) GetReviewQueue(limit int) ([]*models.Flashcard, error) {
+	now := time.Now()
+	return []*models.Flashcard{{ID: 1, Question: "house", Answer: "σπίτι", Status: models.FlashcardStatusPendingReview, CreatedAt: now, UpdatedAt: now}}, nil
+}
+
+func (m *mockService) ApproveFlashcard(id int, req *models.ApproveFlashcardRequest) (*models.Flashcard, error) {
+	switch {
+	case req.ApprovedBy == "":
+		return nil, services.ErrMissingApprover
+	case id == 3:
+		return nil, db.ErrNotPendingReview
+	case id != 1:
+		return nil, errors.New("flashcard with id not found")
+	}
+	now := time.Now()
+	return &models.Flashcard{ID: id, Question: "house", Answer: "σπίτι", Status: models.FlashcardStatusActive, ApprovedBy: req.ApprovedBy, ApprovedAt: &now}, nil
+}
+
+func (m *mockService) RejectFlashcard(id int, req *models.RejectFlashcardRequest) (*models.Flashcard, error) {
+	switch {
+	case req.RejectedBy == "":
+		return nil, services.ErrMissingRejecter
+	case id == 3:
+		return nil, db.ErrNotPendingReview
+	case id != 1:
+		return nil, errors.New("flashcard with id not found")
+	}
+	now := time.Now()
+	return &models.Flashcard{ID: id, Question: "house", Answer: "σπίτι", Status: models.FlashcardStatusRejected, RejectedBy: req.RejectedBy, RejectedAt: &now}, nil
+}
+
 func TestCreateFlashcardHandler(t *testing.T) {
 	// use a mock service that provides deterministic results
 	svc := &mockService{}
-	h := NewFlashcardHandler(svc)
+	h := NewFlashcardHandler(svc, testLanguages(t))
 
 	// use gorilla/mux so path variables are parsed correctly
 	r := mux.NewRouter()
@@ -122,7 +336,7 @@ func TestCreateFlashcardHandler(t *testing.T) {
 
 func TestCreateFlashcardBothFieldsEmpty(t *testing.T) {
 	svc := &mockService{}
-	h := NewFlashcardHandler(svc)
+	h := NewFlashcardHandler(svc, testLanguages(t))
 
 	r := mux.NewRouter()
 	h.RegisterRoutes(r)
@@ -151,7 +365,7 @@ func TestCreateFlashcardBothFieldsEmpty(t *testing.T) {
 
 func TestCreateFlashcardQuestionEmptyNoLang(t *testing.T) {
 	svc := &mockService{}
-	h := NewFlashcardHandler(svc)
+	h := NewFlashcardHandler(svc, testLanguages(t))
 
 	r := mux.NewRouter()
 	h.RegisterRoutes(r)
@@ -180,7 +394,7 @@ func TestCreateFlashcardQuestionEmptyNoLang(t *testing.T) {
 
 func TestCreateFlashcardAnswerEmptyNoLang(t *testing.T) {
 	svc := &mockService{}
-	h := NewFlashcardHandler(svc)
+	h := NewFlashcardHandler(svc, testLanguages(t))
 
 	r := mux.NewRouter()
 	h.RegisterRoutes(r)
@@ -207,9 +421,38 @@ func TestCreateFlashcardAnswerEmptyNoLang(t *testing.T) {
 	}
 }
 
+func TestCreateFlashcardUnsupportedLanguage(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	payload := map[string]string{"question": "hello", "answer": "", "question_lang": "en", "answer_lang": "sw"}
+	b, _ := json.Marshal(payload)
+
+	req := httptest.NewRequest("POST", "/flashcards", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rr.Code)
+	}
+
+	var errResp map[string]string
+	if err := json.NewDecoder(rr.Body).Decode(&errResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if errResp["error"] != "Unsupported language: sw" {
+		t.Fatalf("unexpected error message: %s", errResp["error"])
+	}
+}
+
 func TestCreateFlashcardWithTranslation(t *testing.T) {
 	svc := &mockService{}
-	h := NewFlashcardHandler(svc)
+	h := NewFlashcardHandler(svc, testLanguages(t))
 
 	r := mux.NewRouter()
 	h.RegisterRoutes(r)
@@ -239,9 +482,48 @@ func TestCreateFlashcardWithTranslation(t *testing.T) {
 	}
 }
 
+func TestCreateFlashcardStatuses(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		expected int
+	}{
+		{"grammar warning", `{"question":"street","answer":"δρόμος","question_lang":"en","answer_lang":"el","enrich_grammar":true}`, http.StatusCreated},
+		{"no language model", `{"question":"unavailable","question_lang":"en","answer_lang":"el"}`, http.StatusServiceUnavailable},
+		{"translation failed", `{"question":"timeout","question_lang":"en","answer_lang":"el"}`, http.StatusBadGateway},
+		{"invalid request", `{"question":"#bad tag","answer":"x"}`, http.StatusBadRequest},
+		{"expired preview", `{"question":"book","question_lang":"en","answer_lang":"el","preview_id":"expired","candidate":1}`, http.StatusGone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewFlashcardHandler(&mockService{}, testLanguages(t))
+			r := mux.NewRouter()
+			h.RegisterRoutes(r)
+
+			req := httptest.NewRequest("POST", "/flashcards", bytes.NewBufferString(tt.payload))
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Fatalf("expected status %d, got %d: %s", tt.expected, rr.Code, rr.Body.String())
+			}
+			if tt.expected == http.StatusCreated {
+				var resp models.CreateFlashcardResponse
+				if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if len(resp.Warnings) != 1 {
+					t.Fatalf("expected a grammar warning, got %v", resp.Warnings)
+				}
+			}
+		})
+	}
+}
+
 func TestGetAllFlashcardsHandler(t *testing.T) {
 	svc := &mockService{}
-	h := NewFlashcardHandler(svc)
+	h := NewFlashcardHandler(svc, testLanguages(t))
 
 	r := mux.NewRouter()
 	h.RegisterRoutes(r)
@@ -256,9 +538,179 @@ func TestGetAllFlashcardsHandler(t *testing.T) {
 	}
 }
 
+func TestGetAllFlashcardsPagination(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/flashcards?limit=10&sort=due&order=asc&cursor=abc", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+	opts := svc.lastListOptions
+	if opts.Limit != 10 || opts.Sort != "due" || opts.Order != "asc" {
+		t.Fatalf("unexpected list options: %+v", opts)
+	}
+
+	var page models.FlashcardPage
+	if err := json.NewDecoder(rr.Body).Decode(&page); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(page.Flashcards) != 1 || page.NextCursor != "next" {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+}
+
+func TestGetAllFlashcardsInvalidParameters(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	for _, target := range []string{"/flashcards?limit=x", "/flashcards?cursor=bad", "/flashcards?sort=bogus"} {
+		req := httptest.NewRequest("GET", target, nil)
+		rr := httptest.NewRecorder()
+
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 Bad Request, got %d", target, rr.Code)
+		}
+	}
+}
+
+func TestGetAllFlashcardsTagFilter(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/flashcards?tag=verb&tag=A1|A2&tag=!food", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+	tags := svc.lastFilter.Tags
+	if tags == nil || len(tags.AnyOf) != 2 || len(tags.AnyOf[1]) != 2 || len(tags.NoneOf) != 1 || tags.NoneOf[0] != "food" {
+		t.Fatalf("unexpected tag filter: %+v", tags)
+	}
+}
+
+func TestGetAllFlashcardsAIGeneratedFilter(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/flashcards?ai_generated=true", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+	if svc.lastFilter.AIGenerated == nil || !*svc.lastFilter.AIGenerated {
+		t.Fatalf("expected an ai_generated filter, got %+v", svc.lastFilter)
+	}
+
+	req = httptest.NewRequest("GET", "/flashcards?ai_generated=maybe", nil)
+	rr = httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 Bad Request for an invalid ai_generated, got %d", rr.Code)
+	}
+}
+
+func TestGetAllFlashcardsLanguageFilter(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/flashcards?question_lang=EN&answer_lang=el-gr", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+	if svc.lastFilter.QuestionLang != "en" || svc.lastFilter.AnswerLang != "el-GR" {
+		t.Fatalf("unexpected language filter: %+v", svc.lastFilter)
+	}
+
+	req = httptest.NewRequest("GET", "/flashcards/random?answer_lang=greek!", nil)
+	rr = httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 Bad Request for invalid language, got %d", rr.Code)
+	}
+}
+
+func TestGetRandomFlashcardInvalidTagFilter(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/flashcards/random?tag=verb|", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 Bad Request, got %d", rr.Code)
+	}
+}
+
+func TestGetRandomFlashcardNoMatch(t *testing.T) {
+	r := mux.NewRouter()
+	NewFlashcardHandler(&mockService{}, testLanguages(t)).RegisterRoutes(r)
+
+	for _, url := range []string{"/flashcards/random?question_lang=fr", "/decks/4/random"} {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest("GET", url, nil))
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404 Not Found, got %d", url, rr.Code)
+		}
+	}
+}
+
+func TestGetRandomFlashcardUnsupportedHintLanguage(t *testing.T) {
+	r := mux.NewRouter()
+	NewFlashcardHandler(&mockService{}, testLanguages(t)).RegisterRoutes(r)
+
+	for _, url := range []string{"/flashcards/random?lang=xx", "/decks/1/random?lang=xx"} {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest("GET", url, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 Bad Request, got %d", url, rr.Code)
+		}
+	}
+}
+
 func TestGetFlashcardByIDNotFound(t *testing.T) {
 	svc := &mockService{}
-	h := NewFlashcardHandler(svc)
+	h := NewFlashcardHandler(svc, testLanguages(t))
 
 	r := mux.NewRouter()
 	h.RegisterRoutes(r)
@@ -275,7 +727,7 @@ func TestGetFlashcardByIDNotFound(t *testing.T) {
 
 func TestUpdateFlashcardInvalidID(t *testing.T) {
 	svc := &mockService{}
-	h := NewFlashcardHandler(svc)
+	h := NewFlashcardHandler(svc, testLanguages(t))
 
 	r := mux.NewRouter()
 	h.RegisterRoutes(r)
@@ -297,7 +749,7 @@ func TestUpdateFlashcardInvalidID(t *testing.T) {
 
 func TestDeleteFlashcardHandler(t *testing.T) {
 	svc := &mockService{}
-	h := NewFlashcardHandler(svc)
+	h := NewFlashcardHandler(svc, testLanguages(t))
 
 	r := mux.NewRouter()
 	h.RegisterRoutes(r)
@@ -314,7 +766,7 @@ func TestDeleteFlashcardHandler(t *testing.T) {
 
 func TestGetRandomFlashcardHandler(t *testing.T) {
 	svc := &mockService{}
-	h := NewFlashcardHandler(svc)
+	h := NewFlashcardHandler(svc, testLanguages(t))
 
 	r := mux.NewRouter()
 	h.RegisterRoutes(r)
@@ -339,3 +791,422 @@ func TestGetRandomFlashcardHandler(t *testing.T) {
 		t.Fatalf("expected ai_hint 'hint', got %v", resp.AIHint)
 	}
 }
+
+func TestReviewFlashcardHandler(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("POST", "/flashcards/1/review", bytes.NewReader([]byte(`{"grade": 4}`)))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+
+	var resp models.ScheduledFlashcard
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Schedule == nil || resp.Schedule.Repetitions != 1 {
+		t.Fatalf("expected schedule in response, got %+v", resp.Schedule)
+	}
+}
+
+func TestReviewFlashcardHandlerMissingGrade(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("POST", "/flashcards/1/review", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 Bad Request, got %d", rr.Code)
+	}
+}
+
+func TestGetDueFlashcardsHandler(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/flashcards/due?limit=10", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+
+	var resp []models.ScheduledFlashcard
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp) != 1 {
+		t.Fatalf("expected 1 due flashcard, got %d", len(resp))
+	}
+}
+
+func TestGetReviewLogsHandler(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/flashcards/1/reviews?limit=1", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+
+	var resp models.ReviewLogPage
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Reviews) != 1 || resp.NextCursor != "2" {
+		t.Fatalf("unexpected page: %d reviews, cursor %q", len(resp.Reviews), resp.NextCursor)
+	}
+}
+
+func TestGetReviewLogsHandlerInvalidCursor(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/flashcards/1/reviews?cursor=bad", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 Bad Request, got %d", rr.Code)
+	}
+}
+
+func TestGetFlashcardsByDeckHandler(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/decks/1/flashcards", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+
+	var resp []models.Flashcard
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp) != 1 || resp[0].DeckID == nil || *resp[0].DeckID != 1 {
+		t.Fatalf("expected one flashcard from deck 1, got %+v", resp)
+	}
+}
+
+func TestGetRandomFlashcardByDeckNotFound(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/decks/2/random", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 Not Found, got %d", rr.Code)
+	}
+}
+
+func TestGetDueFlashcardsByDeckHandler(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/decks/1/due", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+}
+
+func TestSearchFlashcardsHandler(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/flashcards/search?q=%CE%B3%CE%B5%CE%B9%CE%B1&limit=5", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+	if svc.lastSearch.Query != "γεια" || svc.lastSearch.Limit != 5 {
+		t.Fatalf("unexpected search request: %+v", svc.lastSearch)
+	}
+
+	var results []models.SearchResult
+	if err := json.NewDecoder(rr.Body).Decode(&results); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(results) != 1 || results[0].Snippet == "" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+}
+
+func TestSearchFlashcardsFuzzyParameters(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/flashcards/search?q=helo&mode=fuzzy&threshold=0.4", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+	if svc.lastSearch.Mode != models.SearchModeFuzzy || svc.lastSearch.Threshold == nil || *svc.lastSearch.Threshold != 0.4 {
+		t.Fatalf("unexpected search request: %+v", svc.lastSearch)
+	}
+
+	req = httptest.NewRequest("GET", "/flashcards/search?q=helo&mode=fuzzy&threshold=high", nil)
+	rr = httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 Bad Request for invalid threshold, got %d", rr.Code)
+	}
+}
+
+func TestSearchFlashcardsMissingQuery(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/flashcards/search", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 Bad Request, got %d", rr.Code)
+	}
+}
+
+func TestAnswerFlashcardHandler(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	b, _ := json.Marshal(map[string]string{"answer": "γεια σασ"})
+	req := httptest.NewRequest("POST", "/flashcards/1/answer", bytes.NewReader(b))
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+
+	var result models.AnswerResult
+	if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Verdict != models.VerdictAlmost || len(result.Diff) != 3 || result.Schedule == nil {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestAnswerFlashcardHandlerErrors(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	tests := []struct {
+		target string
+		body   string
+		status int
+	}{
+		{"/flashcards/1/answer", `{"answer": ""}`, http.StatusBadRequest},
+		{"/flashcards/1/answer", `{`, http.StatusBadRequest},
+		{"/flashcards/2/answer", `{"answer": "x"}`, http.StatusNotFound},
+		{"/flashcards/3/answer", `{"answer": "x"}`, http.StatusConflict},
+		{"/flashcards/3/review", `{"grade": 4}`, http.StatusConflict},
+		{"/flashcards/5/review", `{"grade": 4}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", tt.target, bytes.NewReader([]byte(tt.body)))
+		rr := httptest.NewRecorder()
+
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s %s: expected %d, got %d", tt.target, tt.body, tt.status, rr.Code)
+		}
+	}
+}
+
+func TestExamplesHandler(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	tests := []struct {
+		method string
+		target string
+		status int
+	}{
+		{"POST", "/flashcards/1/examples", http.StatusOK},
+		{"POST", "/flashcards/1/examples?n=2&regenerate=true", http.StatusCreated},
+		{"POST", "/flashcards/1/examples?n=20", http.StatusBadRequest},
+		{"POST", "/flashcards/1/examples?n=two", http.StatusBadRequest},
+		{"POST", "/flashcards/1/examples?regenerate=maybe", http.StatusBadRequest},
+		{"POST", "/flashcards/2/examples", http.StatusNotFound},
+		{"POST", "/flashcards/3/examples", http.StatusServiceUnavailable},
+		{"GET", "/flashcards/1/examples", http.StatusOK},
+		{"GET", "/flashcards/2/examples", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, nil)
+		rr := httptest.NewRecorder()
+
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.target, tt.status, rr.Code)
+		}
+	}
+}
+
+func TestPreviewTranslationHandler(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("POST", "/flashcards/translate-preview", bytes.NewReader([]byte(`{"question": "book", "question_lang": "en", "answer_lang": "el"}`)))
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+	var preview models.TranslatePreview
+	if err := json.NewDecoder(rr.Body).Decode(&preview); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(preview.Candidates) != 2 || preview.Candidates[1].Translation != "κλείνω" {
+		t.Fatalf("unexpected preview: %+v", preview)
+	}
+
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{`, http.StatusBadRequest},
+		{`{"question": "book", "question_lang": "en"}`, http.StatusBadRequest},
+		{`{"question": "book", "question_lang": "en", "answer_lang": "xx"}`, http.StatusBadRequest},
+		{`{"question": "book", "answer": "βιβλίο", "question_lang": "en", "answer_lang": "el"}`, http.StatusBadRequest},
+		{`{"question": "book", "question_lang": "en", "answer_lang": "el", "n": 9}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/flashcards/translate-preview", bytes.NewReader([]byte(tt.body)))
+		rr := httptest.NewRecorder()
+
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.body, tt.status, rr.Code)
+		}
+	}
+}
+
+func TestReviewQueueHandler(t *testing.T) {
+	svc := &mockService{}
+	h := NewFlashcardHandler(svc, testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	tests := []struct {
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{"GET", "/review-queue", "", http.StatusOK},
+		{"GET", "/review-queue?limit=many", "", http.StatusBadRequest},
+		{"POST", "/review-queue/1/approve", `{"approved_by": "maria"}`, http.StatusOK},
+		{"POST", "/review-queue/1/approve", `{"approved_by": "maria", "edit": {"answer": "το σπίτι"}}`, http.StatusOK},
+		{"POST", "/review-queue/1/approve", `{}`, http.StatusBadRequest},
+		{"POST", "/review-queue/1/approve", `{`, http.StatusBadRequest},
+		{"POST", "/review-queue/2/approve", `{"approved_by": "maria"}`, http.StatusNotFound},
+		{"POST", "/review-queue/3/approve", `{"approved_by": "maria"}`, http.StatusConflict},
+		{"POST", "/review-queue/1/reject", `{"rejected_by": "maria"}`, http.StatusOK},
+		{"POST", "/review-queue/1/reject", `{}`, http.StatusBadRequest},
+		{"POST", "/review-queue/1/reject", "", http.StatusBadRequest},
+		{"POST", "/review-queue/2/reject", `{"rejected_by": "maria"}`, http.StatusNotFound},
+		{"POST", "/review-queue/3/reject", `{"rejected_by": "maria"}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, bytes.NewReader([]byte(tt.body)))
+		rr := httptest.NewRecorder()
+
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s %s %s: expected %d, got %d", tt.method, tt.target, tt.body, tt.status, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/akolybelnikov/flashcards/services"
+
+	"github.com/gorilla/mux"
+)
+
+type LanguageHandler struct {
+	languages *services.LanguageRegistry
+}
+
+func NewLanguageHandler(languages *services.LanguageRegistry) *LanguageHandler {
+	if languages == nil {
+		panic("language registry is nil")
+	}
+	return &LanguageHandler{languages: languages}
+}
+
+func (h *LanguageHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/languages", h.GetLanguages).Methods("GET")
+}
+
+func (h *LanguageHandler) GetLanguages(w http.ResponseWriter, _ *http.Request) {
+	writeJSONResponse(w, http.StatusOK, h.languages.All())
+}
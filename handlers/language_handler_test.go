@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akolybelnikov/flashcards/models"
+	"github.com/gorilla/mux"
+)
+
+func TestGetLanguagesHandler(t *testing.T) {
+	h := NewLanguageHandler(testLanguages(t))
+
+	r := mux.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/languages", nil)
+	rr := httptest.NewRecorder()
+
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+
+	var languages []models.Language
+	if err := json.NewDecoder(rr.Body).Decode(&languages); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(languages) != 2 || languages[1].Code != "el" || languages[1].NativeName != "Ελληνικά" || languages[1].Direction != models.DirectionLTR {
+		t.Fatalf("unexpected languages: %+v", languages)
+	}
+}
+
+func TestNewLanguageHandlerPanicsOnNilRegistry(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic")
+		}
+	}()
+	NewLanguageHandler(nil)
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+	"github.com/akolybelnikov/flashcards/services"
+
+	"github.com/gorilla/mux"
+)
+
+type NoteHandler struct {
+	service   services.NoteServiceInterface
+	languages *services.LanguageRegistry
+}
+
+func NewNoteHandler(service services.NoteServiceInterface, languages *services.LanguageRegistry) *NoteHandler {
+	if service == nil {
+		panic("service is nil")
+	}
+	if languages == nil {
+		panic("language registry is nil")
+	}
+	return &NoteHandler{service: service, languages: languages}
+}
+
+func (h *NoteHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/note-types", h.CreateNoteType).Methods("POST")
+	router.HandleFunc("/note-types", h.GetAllNoteTypes).Methods("GET")
+	router.HandleFunc("/note-types/{id:[0-9]+}", h.GetNoteType).Methods("GET")
+	router.HandleFunc("/note-types/{id:[0-9]+}", h.UpdateNoteType).Methods("PUT")
+	router.HandleFunc("/note-types/{id:[0-9]+}", h.DeleteNoteType).Methods("DELETE")
+	router.HandleFunc("/notes", h.CreateNote).Methods("POST")
+	router.HandleFunc("/notes/{id:[0-9]+}", h.GetNote).Methods("GET")
+	router.HandleFunc("/notes/{id:[0-9]+}", h.UpdateNote).Methods("PUT")
+	router.HandleFunc("/notes/{id:[0-9]+}", h.DeleteNote).Methods("DELETE")
+}
+
+func (h *NoteHandler) CreateNoteType(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateNoteTypeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	noteType, err := h.service.CreateNoteType(&req)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, noteType)
+}
+
+func (h *NoteHandler) GetAllNoteTypes(w http.ResponseWriter, _ *http.Request) {
+	noteTypes, err := h.service.GetAllNoteTypes()
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve note types")
+		return
+	}
+
+	if noteTypes == nil {
+		noteTypes = []*models.NoteType{}
+	}
+
+	writeJSONResponse(w, http.StatusOK, noteTypes)
+}
+
+func (h *NoteHandler) GetNoteType(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid note type ID")
+		return
+	}
+
+	noteType, err := h.service.GetNoteType(id)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve note type")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, noteType)
+}
+
+func (h *NoteHandler) UpdateNoteType(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid note type ID")
+		return
+	}
+
+	var req models.UpdateNoteTypeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	noteType, err := h.service.UpdateNoteType(id, &req)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, noteType)
+}
+
+func (h *NoteHandler) DeleteNoteType(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid note type ID")
+		return
+	}
+
+	if err := h.service.DeleteNoteType(id); err != nil {
+		switch {
+		case errors.Is(err, db.ErrNoteTypeInUse):
+			writeErrorResponse(w, http.StatusConflict, err.Error())
+		case containsNotFoundFlashcard(err.Error()):
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		default:
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to delete note type")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *NoteHandler) CreateNote(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	for _, lang := range []string{req.QuestionLang, req.AnswerLang} {
+		if lang == "" {
+			continue
+		}
+		if _, ok := h.languages.Lookup(lang); !ok {
+			writeErrorResponse(w, http.StatusBadRequest, "Unsupported language: "+lang)
+			return
+		}
+	}
+
+	note, err := h.service.CreateNote(&req)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, note)
+}
+
+func (h *NoteHandler) GetNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid note ID")
+		return
+	}
+
+	note, err := h.service.GetNote(id)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve note")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, note)
+}
+
+func (h *NoteHandler) UpdateNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid note ID")
+		return
+	}
+
+	var req models.UpdateNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	note, err := h.service.UpdateNote(id, &req)
+	if err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, note)
+}
+
+func (h *NoteHandler) DeleteNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid note ID")
+		return
+	}
+
+	if err := h.service.DeleteNote(id); err != nil {
+		if containsNotFoundFlashcard(err.Error()) {
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to delete note")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
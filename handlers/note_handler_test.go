@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+	"github.com/akolybelnikov/flashcards/services"
+	"github.com/gorilla/mux"
+)
+
+// mockNoteService implements the NoteServiceInterface for handler tests.
+type mockNoteService struct{}
+
+func (m *mockNoteService) CreateNoteType(req *models.CreateNoteTypeRequest) (*models.NoteType, error) {
+	if len(req.Fields) == 0 {
+		return nil, fmt.Errorf("%w: a note type must have fields", services.ErrInvalidNoteType)
+	}
+	return &models.NoteType{ID: 1, Name: req.Name, Fields: req.Fields, FrontTemplate: req.FrontTemplate, BackTemplate: req.BackTemplate}, nil
+}
+
+func (m *mockNoteService) GetAllNoteTypes() ([]*models.NoteType, error) {
+	return nil, nil
+}
+
+func (m *mockNoteService) GetNoteType(id int) (*models.NoteType, error) {
+	if id != 1 {
+		return nil, fmt.Errorf("note type with id %d not found", id)
+	}
+	return &models.NoteType{ID: 1, Name: "Greek noun"}, nil
+}
+
+func (m *mockNoteService) UpdateNoteType(id int, req *models.UpdateNoteTypeRequest) (*models.NoteType, error) {
+	return m.GetNoteType(id)
+}
+
+func (m *mockNoteService) DeleteNoteType(id int) error {
+	if id == 1 {
+		return db.ErrNoteTypeInUse
+	}
+	return fmt.Errorf("note type with id %d not found", id)
+}
+
+func (m *mockNoteService) CreateNote(req *models.CreateNoteRequest) (*models.Note, error) {
+	if _, err := m.GetNoteType(req.NoteTypeID); err != nil {
+		return nil, err
+	}
+	if req.Fields["gender"] != "" {
+		return nil, fmt.Errorf("%w: note type has no field \"gender\"", services.ErrInvalidNoteFields)
+	}
+	return &models.Note{ID: 1, NoteTypeID: req.NoteTypeID, Fields: req.Fields, Card: &models.Flashcard{ID: 5, Question: req.Fields["meaning"], Answer: req.Fields["word"], NoteID: intPtr(1)}}, nil
+}
+
+func (m *mockNoteService) GetNote(id int) (*models.Note, error) {
+	if id != 1 {
+		return nil, fmt.Errorf("note with id %d not found", id)
+	}
+	return &models.Note{ID: 1, NoteTypeID: 1, Card: &models.Flashcard{ID: 5}}, nil
+}
+
+func (m *mockNoteService) UpdateNote(id int, req *models.UpdateNoteRequest) (*models.Note, error) {
+	return m.GetNote(id)
+}
+
+func (m *mockNoteService) DeleteNote(id int) error {
+	_, err := m.GetNote(id)
+	return err
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestCreateNoteHandler(t *testing.T) {
+	r := mux.NewRouter()
+	NewNoteHandler(&mockNoteService{}, testLanguages(t)).RegisterRoutes(r)
+
+	b, _ := json.Marshal(map[string]any{
+		"note_type_id": 1,
+		"fields":       map[string]string{"word": "σπίτι", "meaning": "house"},
+		"answer_lang":  "el",
+	})
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("POST", "/notes", bytes.NewReader(b)))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var note models.Note
+	if err := json.NewDecoder(rr.Body).Decode(&note); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if note.Card == nil || note.Card.Question != "house" || *note.Card.NoteID != 1 {
+		t.Fatalf("unexpected note: %+v", note)
+	}
+}
+
+func TestNoteHandlerErrors(t *testing.T) {
+	r := mux.NewRouter()
+	NewNoteHandler(&mockNoteService{}, testLanguages(t)).RegisterRoutes(r)
+
+	tests := []struct {
+		method string
+		url    string
+		body   string
+		want   int
+	}{
+		{"POST", "/note-types", `{"name": "empty"}`, http.StatusBadRequest},
+		{"GET", "/note-types", ``, http.StatusOK},
+		{"GET", "/note-types/2", ``, http.StatusNotFound},
+		{"DELETE", "/note-types/1", ``, http.StatusConflict},
+		{"DELETE", "/note-types/2", ``, http.StatusNotFound},
+		{"POST", "/notes", `{"note_type_id": 2, "fields": {"word": "σπίτι"}}`, http.StatusNotFound},
+		{"POST", "/notes", `{"note_type_id": 1, "fields": {"gender": "neuter"}}`, http.StatusBadRequest},
+		{"POST", "/notes", `{"note_type_id": 1, "fields": {"word": "maison"}, "answer_lang": "fr"}`, http.StatusBadRequest},
+		{"GET", "/notes/2", ``, http.StatusNotFound},
+		{"PUT", "/notes/1", `{"tags": ["A1"]}`, http.StatusOK},
+		{"DELETE", "/notes/1", ``, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.url, bytes.NewReader([]byte(tt.body))))
+		if rr.Code != tt.want {
+			t.Errorf("%s %s %s: expected status %d, got %d", tt.method, tt.url, tt.body, tt.want, rr.Code)
+		}
+	}
+}
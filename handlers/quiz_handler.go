@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+	"github.com/akolybelnikov/flashcards/services"
+
+	"github.com/gorilla/mux"
+)
+
+type QuizHandler struct {
+	service services.QuizServiceInterface
+}
+
+func NewQuizHandler(service services.QuizServiceInterface) *QuizHandler {
+	if service == nil {
+		panic("service is nil")
+	}
+	return &QuizHandler{service: service}
+}
+
+func (h *QuizHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/quiz/multiple-choice", h.CreateMultipleChoiceQuiz).Methods("GET")
+	router.HandleFunc("/quiz/{quizId}/answer", h.AnswerQuiz).Methods("POST")
+}
+
+func (h *QuizHandler) CreateMultipleChoiceQuiz(w http.ResponseWriter, r *http.Request) {
+	distractors, err := parseOptionalInt(r, "n")
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid n parameter")
+		return
+	}
+
+	filter, err := parseFlashcardFilter(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quiz, err := h.service.CreateMultipleChoiceQuiz(filter, distractors)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidQuizSize):
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, db.ErrNoQuizFlashcard):
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		default:
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to create quiz")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, quiz)
+}
+
+func (h *QuizHandler) AnswerQuiz(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["quizId"]
+
+	var req models.QuizAnswerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	result, err := h.service.AnswerQuiz(id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMissingChoice), errors.Is(err, services.ErrInvalidChoice):
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, db.ErrQuizAnswered):
+			writeErrorResponse(w, http.StatusConflict, err.Error())
+		case errors.Is(err, services.ErrQuizExpired):
+			writeErrorResponse(w, http.StatusGone, err.Error())
+		case containsNotFoundFlashcard(err.Error()):
+			writeErrorResponse(w, http.StatusNotFound, err.Error())
+		default:
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to answer quiz")
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, result)
+}
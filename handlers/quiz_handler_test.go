@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+	"github.com/akolybelnikov/flashcards/services"
+	"github.com/gorilla/mux"
+)
+
+// mockQuizService implements the QuizServiceInterface for handler tests.
+type mockQuizService struct {
+	lastFilter      *models.FlashcardFilter
+	lastDistractors int
+}
+
+func (m *mockQuizService) CreateMultipleChoiceQuiz(filter *models.FlashcardFilter, distractors int) (*models.MultipleChoiceQuiz, error) {
+	m.lastFilter, m.lastDistractors = filter, distractors
+	if distractors > 9 {
+		return nil, services.ErrInvalidQuizSize
+	}
+	if filter.AnswerLang == "fr" {
+		return nil, db.ErrNoQuizFlashcard
+	}
+	return &models.MultipleChoiceQuiz{
+		ID:          "abc",
+		FlashcardID: 1,
+		Question:    "when?",
+		Choices:     []string{"πού", "πότε", "πώς", "ποτέ"},
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+func (m *mockQuizService) AnswerQuiz(id string, req *models.QuizAnswerRequest) (*models.QuizAnswerResult, error) {
+	switch {
+	case id != "abc":
+		return nil, errors.New("quiz with id " + id + " not found")
+	case req.Choice == nil:
+		return nil, services.ErrMissingChoice
+	case *req.Choice == 3:
+		return nil, db.ErrQuizAnswered
+	case *req.Choice == 2:
+		return nil, services.ErrQuizExpired
+	}
+	return &models.QuizAnswerResult{Correct: *req.Choice == 1, Choice: *req.Choice, CorrectIndex: 1, CorrectAnswer: "πότε"}, nil
+}
+
+func TestCreateMultipleChoiceQuizHandler(t *testing.T) {
+	svc := &mockQuizService{}
+	r := mux.NewRouter()
+	NewQuizHandler(svc).RegisterRoutes(r)
+
+	req := httptest.NewRequest("GET", "/quiz/multiple-choice?n=3&answer_lang=el&tag=A1", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if svc.lastDistractors != 3 || svc.lastFilter.AnswerLang != "el" || svc.lastFilter.Tags == nil {
+		t.Fatalf("unexpected arguments: n=%d filter=%+v", svc.lastDistractors, svc.lastFilter)
+	}
+	if strings.Contains(rr.Body.String(), "correct") {
+		t.Fatalf("expected the quiz not to reveal the correct choice, got %s", rr.Body.String())
+	}
+
+	var quiz models.MultipleChoiceQuiz
+	if err := json.NewDecoder(rr.Body).Decode(&quiz); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if quiz.ID != "abc" || len(quiz.Choices) != 4 {
+		t.Fatalf("unexpected quiz: %+v", quiz)
+	}
+}
+
+func TestCreateMultipleChoiceQuizHandlerErrors(t *testing.T) {
+	r := mux.NewRouter()
+	NewQuizHandler(&mockQuizService{}).RegisterRoutes(r)
+
+	tests := []struct {
+		url  string
+		want int
+	}{
+		{"/quiz/multiple-choice?n=abc", http.StatusBadRequest},
+		{"/quiz/multiple-choice?n=10", http.StatusBadRequest},
+		{"/quiz/multiple-choice?answer_lang=e_", http.StatusBadRequest},
+		{"/quiz/multiple-choice?answer_lang=fr", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest("GET", tt.url, nil))
+		if rr.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.url, tt.want, rr.Code)
+		}
+	}
+}
+
+func TestAnswerQuizHandler(t *testing.T) {
+	r := mux.NewRouter()
+	NewQuizHandler(&mockQuizService{}).RegisterRoutes(r)
+
+	req := httptest.NewRequest("POST", "/quiz/abc/answer", bytes.NewReader([]byte(`{"choice": 1}`)))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var result models.QuizAnswerResult
+	if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !result.Correct || result.CorrectAnswer != "πότε" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestAnswerQuizHandlerErrors(t *testing.T) {
+	r := mux.NewRouter()
+	NewQuizHandler(&mockQuizService{}).RegisterRoutes(r)
+
+	tests := []struct {
+		url  string
+		body string
+		want int
+	}{
+		{"/quiz/abc/answer", `{`, http.StatusBadRequest},
+		{"/quiz/abc/answer", `{}`, http.StatusBadRequest},
+		{"/quiz/abc/answer", `{"choice": 3}`, http.StatusConflict},
+		{"/quiz/abc/answer", `{"choice": 2}`, http.StatusGone},
+		{"/quiz/xyz/answer", `{"choice": 0}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest("POST", tt.url, bytes.NewReader([]byte(tt.body))))
+		if rr.Code != tt.want {
+			t.Errorf("%s %s: expected status %d, got %d", tt.url, tt.body, tt.want, rr.Code)
+		}
+	}
+}
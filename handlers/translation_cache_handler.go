@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/akolybelnikov/flashcards/services"
+
+	"github.com/gorilla/mux"
+)
+
+type TranslationCacheHandler struct {
+	cache services.TranslationCacheInterface
+}
+
+func NewTranslationCacheHandler(cache services.TranslationCacheInterface) *TranslationCacheHandler {
+	if cache == nil {
+		panic("translation cache is nil")
+	}
+	return &TranslationCacheHandler{cache: cache}
+}
+
+func (h *TranslationCacheHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/translation-cache", h.PurgeTranslationCache).Methods("DELETE")
+}
+
+func (h *TranslationCacheHandler) PurgeTranslationCache(w http.ResponseWriter, r *http.Request) {
+	expiredOnly := false
+	if value := r.URL.Query().Get("expired"); value != "" {
+		var err error
+		expiredOnly, err = strconv.ParseBool(value)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid expired")
+			return
+		}
+	}
+
+	purge, err := h.cache.Purge(expiredOnly)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to purge translation cache")
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, purge)
+}
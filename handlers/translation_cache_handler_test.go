@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akolybelnikov/flashcards/models"
+	"github.com/gorilla/mux"
+)
+
+// mockTranslationCache records how it was purged.
+type mockTranslationCache struct {
+	expiredOnly bool
+	err         error
+}
+
+func (m *mockTranslationCache) Purge(expiredOnly bool) (*models.TranslationCachePurge, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.expiredOnly = expiredOnly
+	return &models.TranslationCachePurge{Deleted: 3}, nil
+}
+
+func TestPurgeTranslationCacheHandler(t *testing.T) {
+	tests := []struct {
+		target      string
+		err         error
+		status      int
+		expiredOnly bool
+	}{
+		{"/translation-cache", nil, http.StatusOK, false},
+		{"/translation-cache?expired=true", nil, http.StatusOK, true},
+		{"/translation-cache?expired=soon", nil, http.StatusBadRequest, false},
+		{"/translation-cache", errors.New("connection refused"), http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		cache := &mockTranslationCache{err: tt.err}
+		r := mux.NewRouter()
+		NewTranslationCacheHandler(cache).RegisterRoutes(r)
+
+		req := httptest.NewRequest("DELETE", tt.target, nil)
+		rr := httptest.NewRecorder()
+
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.target, tt.status, rr.Code)
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		var purge models.TranslationCachePurge
+		if err := json.NewDecoder(rr.Body).Decode(&purge); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if purge.Deleted != 3 || cache.expiredOnly != tt.expiredOnly {
+			t.Errorf("%s: unexpected purge %+v, expired only %v", tt.target, purge, cache.expiredOnly)
+		}
+	}
+}
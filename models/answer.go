@@ -0,0 +1,39 @@
+package models
+
+// Verdicts of a graded answer.
+const (
+	VerdictCorrect   = "correct"
+	VerdictAlmost    = "almost"
+	VerdictIncorrect = "incorrect"
+)
+
+// Operations of a DiffSegment.
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert" // missing from the typed answer
+	DiffDelete = "delete" // typed but not part of the expected answer
+)
+
+// AnswerRequest is the payload for answering a flashcard by typing the answer.
+type AnswerRequest struct {
+	Answer     string `json:"answer"`
+	DurationMS *int   `json:"duration_ms,omitempty"` // time the learner took to answer
+}
+
+// DiffSegment is a run of characters that the typed answer and the expected answer share, or that
+// only one of them has.
+type DiffSegment struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// AnswerResult is the outcome of grading a typed answer. The card is reviewed with Grade and Schedule
+// holds its new schedule.
+type AnswerResult struct {
+	Verdict      string        `json:"verdict"`
+	Expected     string        `json:"expected"`
+	Diff         []DiffSegment `json:"diff"`                   // turns the typed answer into the expected one
+	Explanations []string      `json:"explanations,omitempty"` // why an answer is almost correct
+	Grade        int           `json:"grade"`
+	Schedule     *CardSchedule `json:"schedule"`
+}
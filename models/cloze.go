@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// ClozeNote is a text with cloze deletions such as {{c1::λέξη}} or {{c1::λέξη::hint}}. Each distinct
+// index becomes a flashcard that hides the deletions with that index, and the cards are scheduled
+// independently.
+type ClozeNote struct {
+	ID        int          `json:"id"`
+	Text      string       `json:"text"`
+	Lang      string       `json:"lang,omitempty"`
+	DeckID    *int         `json:"deck_id,omitempty"`
+	Cards     []*Flashcard `json:"cards"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+type CreateClozeNoteRequest struct {
+	Text   string   `json:"text"`
+	Lang   string   `json:"lang"` // BCP 47 code, e.g. "el"
+	DeckID *int     `json:"deck_id,omitempty"`
+	Tags   []string `json:"tags,omitempty"` // applied to every card of the note
+}
+
+type UpdateClozeNoteRequest struct {
+	Text *string   `json:"text,omitempty"` // re-renders the cards, keeping the schedules of unchanged indexes
+	Tags *[]string `json:"tags,omitempty"` // replaces the tags of every card of the note
+}
+
+// ClozeCard is the rendered flashcard for one cloze index of a note.
+type ClozeCard struct {
+	Index    int
+	Question string
+	Answer   string
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type Deck struct {
+	ID          int       `json:"id"`
+	ParentID    *int      `json:"parent_id,omitempty"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CreateDeckRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ParentID    *int   `json:"parent_id,omitempty"`
+}
+
+type UpdateDeckRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// MoveDeckRequest re-parents a deck together with its whole subtree. A nil ParentID makes the deck a
+// top-level deck.
+type MoveDeckRequest struct {
+	ParentID *int `json:"parent_id"`
+}
+
+// DeckTree is a deck together with all of its descendants.
+type DeckTree struct {
+	Deck
+	Children []*DeckTree `json:"children"`
+}
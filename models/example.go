@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// CEFRLevels lists the Common European Framework of Reference levels that example sentences are
+// graded by, from easiest to hardest.
+var CEFRLevels = []string{"A1", "A2", "B1", "B2", "C1", "C2"}
+
+// ExampleSentence is a sentence using a flashcard's word in the language being learned, with its
+// translation and the CEFR level it is written at.
+type ExampleSentence struct {
+	Sentence    string `json:"sentence"`
+	Translation string `json:"translation"`
+	Level       string `json:"level,omitempty"`
+}
+
+// CardExample is an example sentence stored for a flashcard.
+type CardExample struct {
+	ID          int `json:"id"`
+	FlashcardID int `json:"flashcard_id"`
+	ExampleSentence
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GenerateExamplesRequest asks for N example sentences for a flashcard. Examples that were already
+// generated are returned as they are unless Regenerate is set.
+type GenerateExamplesRequest struct {
+	N          int
+	Regenerate bool
+}
+
+// CardExamples are the example sentences of a flashcard. Generated reports whether they were just
+// generated rather than loaded.
+type CardExamples struct {
+	FlashcardID int            `json:"flashcard_id"`
+	Examples    []*CardExample `json:"examples"`
+	Generated   bool           `json:"generated"`
+}
@@ -3,23 +3,131 @@ package models
 import "time"
 
 type Flashcard struct {
-	ID        int       `json:"id"`
-	Question  string    `json:"question"`
-	Answer    string    `json:"answer"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           int           `json:"id"`
+	DeckID       *int          `json:"deck_id,omitempty"`
+	Question     string        `json:"question"`
+	Answer       string        `json:"answer"`
+	QuestionLang string        `json:"question_lang,omitempty"` // BCP 47 code, e.g. "en" or "el-GR"
+	AnswerLang   string        `json:"answer_lang,omitempty"`
+	Tags         []string      `json:"tags"`
+	ClozeNoteID  *int          `json:"cloze_note_id,omitempty"` // set on cards generated from a cloze note
+	ClozeIndex   *int          `json:"cloze_index,omitempty"`
+	ReverseID    *int          `json:"reverse_id,omitempty"` // the linked card asking the other way round
+	NoteID       *int          `json:"note_id,omitempty"`    // set on cards rendered from a note
+	Note         *NoteContent  `json:"-"`
+	Grammar      *Grammar      `json:"grammar,omitempty"`       // grammar of the card's Greek word, when enriched
+	AIProvenance *AIProvenance `json:"ai_provenance,omitempty"` // set when fields were machine-generated
+	Status       string        `json:"status"`                  // FlashcardStatus constant
+	ApprovedBy   string        `json:"approved_by,omitempty"`   // who approved the card in the review queue
+	ApprovedAt   *time.Time    `json:"approved_at,omitempty"`
+	RejectedBy   string        `json:"rejected_by,omitempty"` // who rejected the card in the review queue
+	RejectedAt   *time.Time    `json:"rejected_at,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
 }
 
 type CreateFlashcardRequest struct {
-	Question     string `json:"question"`
-	Answer       string `json:"answer"`
-	QuestionLang string `json:"question_lang"` // e.g., "en" or "el"
-	AnswerLang   string `json:"answer_lang"`   // e.g., "en" or "el"
+	Question      string        `json:"question"`
+	Answer        string        `json:"answer"`
+	QuestionLang  string        `json:"question_lang"` // BCP 47 code, e.g. "en" or "el-GR"
+	AnswerLang    string        `json:"answer_lang"`   // BCP 47 code, e.g. "en" or "el-GR"
+	DeckID        *int          `json:"deck_id,omitempty"`
+	Tags          []string      `json:"tags,omitempty"`
+	Reversible    bool          `json:"reversible,omitempty"`     // also creates the answer -> question card
+	EnrichGrammar bool          `json:"enrich_grammar,omitempty"` // fills in grammar of the Greek side with the LLM
+	PreviewID     string        `json:"preview_id,omitempty"`     // a translation preview whose candidate fills in the empty side
+	Candidate     int           `json:"candidate,omitempty"`      // index of the chosen candidate of the preview
+	Grammar       *Grammar      `json:"-"`                        // set by the service when enriching
+	AIProvenance  *AIProvenance `json:"-"`                        // set by the service when translating or enriching
+	Status        string        `json:"-"`                        // set by the service, active when empty
+	Warnings      []string      `json:"-"`                        // set by the service when an optional step fails
 }
 
 type UpdateFlashcardRequest struct {
-	Question *string `json:"question,omitempty"`
-	Answer   *string `json:"answer,omitempty"`
+	Question     *string   `json:"question,omitempty"`
+	Answer       *string   `json:"answer,omitempty"`
+	QuestionLang *string   `json:"question_lang,omitempty"` // an empty string clears the language
+	AnswerLang   *string   `json:"answer_lang,omitempty"`
+	DeckID       *int      `json:"deck_id,omitempty"` // moves the card to another deck; 0 removes it from its deck
+	Tags         *[]string `json:"tags,omitempty"`    // replaces all tags of the card
+}
+
+// TagFilter selects flashcards by tag. A card matches when, for every group in AnyOf, it has at least
+// one of the group's tags, and it has none of the tags in NoneOf.
+type TagFilter struct {
+	AnyOf  [][]string
+	NoneOf []string
+}
+
+// FlashcardFilter narrows down the flashcards returned by list and random queries. A nil or empty
+// field does not filter.
+type FlashcardFilter struct {
+	Tags *TagFilter
+	// Language filters match a canonical BCP 47 code and its more specific forms, so "en" also
+	// matches "en-US".
+	QuestionLang string
+	AnswerLang   string
+	// AIGenerated selects cards with (true) or without (false) machine-generated fields.
+	AIGenerated *bool
+}
+
+// Orderings supported when listing flashcards.
+const (
+	SortByCreatedAt = "created_at"
+	SortByUpdatedAt = "updated_at"
+	SortByQuestion  = "question"
+	SortByDue       = "due"
+)
+
+// Sort directions.
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// FlashcardListOptions selects one page of flashcards.
+type FlashcardListOptions struct {
+	Filter *FlashcardFilter
+	Sort   string // one of the SortBy constants
+	Order  string // OrderAsc or OrderDesc
+	Limit  int
+	After  *FlashcardCursor // position of the last card of the previous page, nil for the first page
+}
+
+// FlashcardCursor is a position in a sorted flashcard list: the sort key of a card in its text form
+// and the card's ID, which breaks ties between equal keys.
+type FlashcardCursor struct {
+	SortKey string
+	ID      int
+}
+
+// FlashcardPage is one page of a flashcard list.
+type FlashcardPage struct {
+	Flashcards []*Flashcard `json:"flashcards"`
+	NextCursor string       `json:"next_cursor,omitempty"` // empty on the last page
+}
+
+// Search modes.
+const (
+	SearchModeFullText = "fulltext"
+	SearchModeFuzzy    = "fuzzy"
+)
+
+// SearchRequest describes a flashcard search.
+type SearchRequest struct {
+	Query     string
+	Mode      string   // one of the SearchMode constants, full-text if empty
+	Threshold *float64 // minimum similarity of fuzzy matches, from 0 to 1; DefaultFuzzyThreshold if nil
+	Limit     int
+}
+
+// SearchResult is a flashcard matching a search. Full-text matches carry their relevance and the
+// matched text highlighted; fuzzy matches carry their similarity score.
+type SearchResult struct {
+	Flashcard *Flashcard `json:"flashcard"`
+	Rank      float64    `json:"rank"`
+	Score     *float64   `json:"score,omitempty"`   // trigram similarity of fuzzy matches, from 0 to 1
+	Snippet   string     `json:"snippet,omitempty"` // HTML-escaped, matched words are wrapped in <mark></mark>
 }
 
 // RandomFlashcardResponse represents the payload returned by the random flashcard endpoint.
@@ -34,4 +142,5 @@ type CreateFlashcardResponse struct {
 	Flashcard         *Flashcard `json:"flashcard"`
 	AITranslationUsed bool       `json:"ai_translation_used"`
 	TranslatedField   string     `json:"translated_field,omitempty"` // "question" or "answer"
+	Warnings          []string   `json:"warnings,omitempty"`         // optional steps that failed, e.g. grammar enrichment
 }
@@ -0,0 +1,69 @@
+package models
+
+// Parts of speech recorded in Grammar.
+const (
+	PartOfSpeechNoun  = "noun"
+	PartOfSpeechVerb  = "verb"
+	PartOfSpeechOther = "other"
+)
+
+// Grammatical genders of nouns.
+const (
+	GenderMasculine = "masculine"
+	GenderFeminine  = "feminine"
+	GenderNeuter    = "neuter"
+)
+
+// Tenses of a verb conjugation table.
+const (
+	TensePresent   = "present"
+	TenseImperfect = "imperfect"
+	TenseAorist    = "aorist"
+	TenseFuture    = "future"
+)
+
+// GrammarTenses lists the tenses every verb conjugation table has.
+var GrammarTenses = []string{TensePresent, TenseImperfect, TenseAorist, TenseFuture}
+
+// Grammar is structured grammar data for the Greek word of a flashcard. Noun is set for nouns and
+// Verb for verbs; other words only record their part of speech and dictionary form.
+type Grammar struct {
+	PartOfSpeech string       `json:"part_of_speech"`
+	Lemma        string       `json:"lemma"` // dictionary form of the word
+	Noun         *NounGrammar `json:"noun,omitempty"`
+	Verb         *VerbGrammar `json:"verb,omitempty"`
+}
+
+// NounGrammar is the gender and principal case forms of a noun.
+type NounGrammar struct {
+	Article          string `json:"article"` // nominative definite article: ο, η, το, or οι, τα for plural-only nouns
+	Gender           string `json:"gender"`
+	GenitiveSingular string `json:"genitive_singular,omitempty"` // empty for plural-only nouns
+	NominativePlural string `json:"nominative_plural,omitempty"` // empty for nouns without a plural
+	GenitivePlural   string `json:"genitive_plural,omitempty"`
+}
+
+// VerbGrammar is the principal parts of a verb and its active conjugation in the main tenses.
+type VerbGrammar struct {
+	Present           string               `json:"present"`                      // e.g. γράφω
+	Aorist            string               `json:"aorist"`                       // e.g. έγραψα
+	Future            string               `json:"future"`                       // e.g. θα γράψω
+	PassiveAorist     string               `json:"passive_aorist,omitempty"`     // e.g. γράφτηκα
+	PassiveParticiple string               `json:"passive_participle,omitempty"` // e.g. γραμμένος
+	Conjugation       map[string]VerbForms `json:"conjugation"`                  // keyed by tense
+}
+
+// VerbForms are the forms of a verb in one tense, by person and number.
+type VerbForms struct {
+	FirstSingular  string `json:"1sg"`
+	SecondSingular string `json:"2sg"`
+	ThirdSingular  string `json:"3sg"`
+	FirstPlural    string `json:"1pl"`
+	SecondPlural   string `json:"2pl"`
+	ThirdPlural    string `json:"3pl"`
+}
+
+// All returns the forms in the order first singular to third plural.
+func (f VerbForms) All() []string {
+	return []string{f.FirstSingular, f.SecondSingular, f.ThirdSingular, f.FirstPlural, f.SecondPlural, f.ThirdPlural}
+}
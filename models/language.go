@@ -0,0 +1,19 @@
+package models
+
+// Text directions.
+const (
+	DirectionLTR = "ltr"
+	DirectionRTL = "rtl"
+)
+
+// Language describes a language flashcards can be written in.
+type Language struct {
+	Code       string `json:"code"`        // BCP 47 code, e.g. "el"
+	Name       string `json:"name"`        // English name, e.g. "Greek"
+	NativeName string `json:"native_name"` // e.g. "Ελληνικά"
+	Script     string `json:"script"`      // ISO 15924 code, e.g. "Grek"
+	Direction  string `json:"direction"`   // DirectionLTR or DirectionRTL
+	// DiacriticsSignificant is set for languages where accents change meaning, such as the Greek tonos
+	// in πότε ("when") and ποτέ ("never").
+	DiacriticsSignificant bool `json:"diacritics_significant"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// NoteType defines the named fields of a note and the text/template templates rendering the front
+// (question) and back (answer) of its card. Templates refer to fields as {{.name}}.
+type NoteType struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name"`
+	Fields        []string  `json:"fields"`
+	FrontTemplate string    `json:"front_template"`
+	BackTemplate  string    `json:"back_template"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type CreateNoteTypeRequest struct {
+	Name          string   `json:"name"`
+	Fields        []string `json:"fields"`
+	FrontTemplate string   `json:"front_template"`
+	BackTemplate  string   `json:"back_template"`
+}
+
+// UpdateNoteTypeRequest changes the name or templates of a note type. Its fields cannot be changed,
+// since existing notes were validated against them.
+type UpdateNoteTypeRequest struct {
+	Name          *string `json:"name,omitempty"`
+	FrontTemplate *string `json:"front_template,omitempty"`
+	BackTemplate  *string `json:"back_template,omitempty"`
+}
+
+// Note holds the field values of a note and the card rendered from them.
+type Note struct {
+	ID         int               `json:"id"`
+	NoteTypeID int               `json:"note_type_id"`
+	Fields     map[string]string `json:"fields"`
+	Card       *Flashcard        `json:"card"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+type CreateNoteRequest struct {
+	NoteTypeID   int               `json:"note_type_id"`
+	Fields       map[string]string `json:"fields"`
+	QuestionLang string            `json:"question_lang,omitempty"` // BCP 47 code, e.g. "en" or "el-GR"
+	AnswerLang   string            `json:"answer_lang,omitempty"`
+	DeckID       *int              `json:"deck_id,omitempty"`
+	Tags         []string          `json:"tags,omitempty"`
+}
+
+type UpdateNoteRequest struct {
+	Fields map[string]string `json:"fields,omitempty"` // replaces all field values
+	Tags   *[]string         `json:"tags,omitempty"`   // replaces all tags of the card
+}
+
+// NoteContent is what a note's card is rendered from: the note's field values and its type's
+// templates.
+type NoteContent struct {
+	Fields        map[string]string `json:"fields"`
+	FrontTemplate string            `json:"front_template"`
+	BackTemplate  string            `json:"back_template"`
+}
+
+// RenderedNote is the card text of a note rendered with its type's templates.
+type RenderedNote struct {
+	NoteID   int
+	Question string
+	Answer   string
+}
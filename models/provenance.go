@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Fields of a flashcard that a language model can generate.
+const (
+	AIFieldQuestion = "question"
+	AIFieldAnswer   = "answer"
+	AIFieldGrammar  = "grammar"
+)
+
+// AIProvenance records which fields of a flashcard were generated by a language model, so that
+// machine output can be audited. A field that is edited afterwards is no longer listed.
+type AIProvenance struct {
+	Fields        []string  `json:"fields"`         // AIField constants
+	Model         string    `json:"model"`          // name of the model, empty if unknown
+	PromptVersion string    `json:"prompt_version"` // version of the prompts the fields were generated with
+	GeneratedAt   time.Time `json:"generated_at"`
+}
+
+// Review states of a flashcard. Cards with machine-generated fields start pending review and are kept
+// out of study until they are approved. Rejected cards are kept for the record but never studied.
+const (
+	FlashcardStatusActive        = "active"
+	FlashcardStatusPendingReview = "pending_review"
+	FlashcardStatusRejected      = "rejected"
+)
+
+// ApproveFlashcardRequest approves a flashcard waiting for review, optionally correcting it first.
+type ApproveFlashcardRequest struct {
+	ApprovedBy string                  `json:"approved_by"`
+	Edit       *UpdateFlashcardRequest `json:"edit,omitempty"` // changes applied before approving
+}
+
+// RejectFlashcardRequest rejects a flashcard waiting for review.
+type RejectFlashcardRequest struct {
+	RejectedBy string `json:"rejected_by"`
+}
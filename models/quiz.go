@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// MultipleChoiceQuiz is a flashcard question with answer choices, as shown to the learner. Which
+// choice is correct is only revealed once the quiz has been answered.
+type MultipleChoiceQuiz struct {
+	ID           string    `json:"id"`
+	FlashcardID  int       `json:"flashcard_id"`
+	Question     string    `json:"question"`
+	QuestionLang string    `json:"question_lang,omitempty"`
+	AnswerLang   string    `json:"answer_lang,omitempty"`
+	Choices      []string  `json:"choices"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Quiz is a stored multiple-choice quiz together with its correct choice and the learner's answer.
+type Quiz struct {
+	MultipleChoiceQuiz
+	CorrectIndex int
+	Choice       *int
+	AnsweredAt   *time.Time
+}
+
+type QuizAnswerRequest struct {
+	Choice *int `json:"choice"`
+}
+
+type QuizAnswerResult struct {
+	Correct       bool   `json:"correct"`
+	Choice        int    `json:"choice"`
+	CorrectIndex  int    `json:"correct_index"`
+	CorrectAnswer string `json:"correct_answer"`
+}
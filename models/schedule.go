@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// CardSchedule holds the spaced-repetition state of a single flashcard. Which of the state fields are
+// meaningful depends on the scheduler that produced it: SM-2 uses the ease factor, FSRS uses stability,
+// difficulty and retrievability.
+type CardSchedule struct {
+	FlashcardID    int        `json:"flashcard_id"`
+	Scheduler      string     `json:"scheduler"`
+	EaseFactor     float64    `json:"ease_factor,omitempty"`
+	Stability      float64    `json:"stability,omitempty"`
+	Difficulty     float64    `json:"difficulty,omitempty"`
+	Retrievability *float64   `json:"retrievability,omitempty"` // recall probability at the last review
+	IntervalDays   int        `json:"interval_days"`
+	Repetitions    int        `json:"repetitions"`
+	DueAt          time.Time  `json:"due_at"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty"`
+}
+
+// ReviewRequest is the payload for grading a flashcard. Grade uses the SM-2 scale from 0 (complete
+// blackout) to 5 (perfect response); it is a pointer so that a missing grade can be told apart from 0.
+type ReviewRequest struct {
+	Grade      *int `json:"grade"`
+	DurationMS *int `json:"duration_ms,omitempty"` // time the learner took to answer
+}
+
+// DefaultUserID attributes reviews and fitted FSRS weights. Cards have a single schedule that every
+// review updates, so they all belong to this one user.
+const DefaultUserID = "default"
+
+// ReviewLog records a single review together with the schedule before and after it.
+type ReviewLog struct {
+	ID          int64         `json:"id"`
+	FlashcardID int           `json:"flashcard_id"`
+	UserID      string        `json:"user_id"`
+	Grade       int           `json:"grade"`
+	DurationMS  *int          `json:"duration_ms,omitempty"`
+	ElapsedDays *float64      `json:"elapsed_days,omitempty"` // days since the previous review
+	Scheduler   string        `json:"scheduler"`
+	StateBefore *CardSchedule `json:"state_before"`
+	StateAfter  *CardSchedule `json:"state_after"`
+	ReviewedAt  time.Time     `json:"reviewed_at"`
+}
+
+// ReviewLogPage is one page of a card's review history, newest first. NextCursor is empty on the last page.
+type ReviewLogPage struct {
+	Reviews    []*ReviewLog `json:"reviews"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+// ScheduledFlashcard pairs a flashcard with its schedule. Schedule is nil for cards that have never
+// been reviewed.
+type ScheduledFlashcard struct {
+	Flashcard *Flashcard    `json:"flashcard"`
+	Schedule  *CardSchedule `json:"schedule"`
+}
+
+// FSRSParameters are FSRS weights fitted from a user's review history.
+type FSRSParameters struct {
+	UserID      string    `json:"user_id"`
+	Weights     []float64 `json:"weights"`
+	LogLoss     float64   `json:"log_loss"`
+	ReviewCount int       `json:"review_count"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
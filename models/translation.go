@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Parts of speech a Translation can report.
+var TranslationPartsOfSpeech = []string{
+	"noun", "verb", "adjective", "adverb", "pronoun", "preposition", "conjunction", "interjection", "numeral", "phrase",
+}
+
+// Translation is the structured result of translating a text with the language model.
+type Translation struct {
+	Translation  string   `json:"translation"`
+	Alternatives []string `json:"alternatives,omitempty"`   // other valid translations, best first
+	PartOfSpeech string   `json:"part_of_speech,omitempty"` // one of TranslationPartsOfSpeech, empty if unknown
+	Confidence   float64  `json:"confidence"`               // from 0 to 1
+}
+
+// Registers a TranslationCandidate can be written in.
+var TranslationRegisters = []string{"neutral", "formal", "informal", "colloquial", "literary", "technical", "slang"}
+
+// TranslationCandidate is one possible translation of a text, with notes on when to use it.
+type TranslationCandidate struct {
+	Translation  string `json:"translation"`
+	PartOfSpeech string `json:"part_of_speech,omitempty"` // one of TranslationPartsOfSpeech
+	Register     string `json:"register,omitempty"`       // one of TranslationRegisters
+	Usage        string `json:"usage,omitempty"`          // the meaning or context the translation is for
+}
+
+// TranslatePreviewRequest asks for candidate translations of the side of a new flashcard that is
+// filled in, into the language of the empty side.
+type TranslatePreviewRequest struct {
+	Question     string `json:"question"`
+	Answer       string `json:"answer"`
+	QuestionLang string `json:"question_lang"`
+	AnswerLang   string `json:"answer_lang"`
+	N            int    `json:"n,omitempty"` // number of candidates, defaults to 3
+}
+
+// TranslatePreview lists candidate translations, best first. The preview is kept on the server so
+// that a CreateFlashcardRequest can choose one of them by its preview ID and candidate index.
+type TranslatePreview struct {
+	ID              string                 `json:"id"`
+	Source          string                 `json:"source"`
+	TranslatedField string                 `json:"translated_field"` // "question" or "answer"
+	Candidates      []TranslationCandidate `json:"candidates"`
+	CreatedAt       time.Time              `json:"created_at"`
+}
+
+// StoredTranslationPreview is a stored translation preview together with the languages it translates
+// between and the model and prompt version that generated its candidates.
+type StoredTranslationPreview struct {
+	TranslatePreview
+	QuestionLang  string
+	AnswerLang    string
+	Model         string
+	PromptVersion string
+}
+
+// TranslationCacheKey identifies a cached translation. Text is normalised, so that inputs differing
+// only in whitespace share an entry, and a new prompt version or model invalidates all entries.
+type TranslationCacheKey struct {
+	Text          string
+	SourceLang    string
+	TargetLang    string
+	PromptVersion string
+	Provider      string
+	Model         string
+}
+
+// TranslationCachePurge reports how many cached translations a purge removed.
+type TranslationCachePurge struct {
+	Deleted int64 `json:"deleted"`
+}
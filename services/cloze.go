@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// MaxClozeIndex is the highest cloze index accepted, which bounds the number of cards per note.
+const MaxClozeIndex = 50
+
+// clozeBlank replaces a hidden deletion that has no hint.
+const clozeBlank = "[...]"
+
+var (
+	// clozePattern matches a deletion {{cN::text}} or {{cN::text::hint}}.
+	clozePattern = regexp.MustCompile(`(?s)\{\{c(\d+)::(.*?)(?:::(.*?))?\}\}`)
+	// clozeOpening matches the start of a deletion, to find ones that were never closed.
+	clozeOpening = regexp.MustCompile(`\{\{c\d+::`)
+)
+
+// ErrInvalidCloze is returned for cloze note texts without deletions or with malformed ones.
+var ErrInvalidCloze = errors.New("invalid cloze text")
+
+// ClozeDeletion is one {{cN::text::hint}} marker of a cloze note.
+type ClozeDeletion struct {
+	Index int
+	Text  string
+	Hint  string
+}
+
+// ParseCloze returns the deletions of a cloze note text in order of appearance. The same index may
+// be used by several deletions, which are then hidden together.
+func ParseCloze(text string) ([]ClozeDeletion, error) {
+	if clozeOpening.MatchString(clozePattern.ReplaceAllString(text, "")) {
+		return nil, fmt.Errorf("%w: unclosed cloze deletion", ErrInvalidCloze)
+	}
+
+	var deletions []ClozeDeletion
+	for _, match := range clozePattern.FindAllStringSubmatch(text, -1) {
+		index, err := strconv.Atoi(match[1])
+		if err != nil || index < 1 || index > MaxClozeIndex {
+			return nil, fmt.Errorf("%w: cloze index c%s must be between c1 and c%d", ErrInvalidCloze, match[1], MaxClozeIndex)
+		}
+		deletion := ClozeDeletion{Index: index, Text: strings.TrimSpace(match[2]), Hint: strings.TrimSpace(match[3])}
+		if deletion.Text == "" {
+			return nil, fmt.Errorf("%w: cloze deletion c%d is empty", ErrInvalidCloze, index)
+		}
+		deletions = append(deletions, deletion)
+	}
+
+	if len(deletions) == 0 {
+		return nil, fmt.Errorf("%w: text must contain a cloze deletion such as {{c1::word}}", ErrInvalidCloze)
+	}
+	return deletions, nil
+}
+
+// RenderCloze renders one card per distinct cloze index of a text, in index order. A card's question
+// is the text with that index's deletions replaced by their hint in brackets, or "[...]", and every
+// other deletion shown as plain text. Its answer is the hidden text, separated by ", " when the index
+// hides several deletions.
+func RenderCloze(text string) ([]models.ClozeCard, error) {
+	deletions, err := ParseCloze(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []int
+	for _, deletion := range deletions {
+		if !slices.Contains(indexes, deletion.Index) {
+			indexes = append(indexes, deletion.Index)
+		}
+	}
+	slices.Sort(indexes)
+
+	cards := make([]models.ClozeCard, 0, len(indexes))
+	for _, index := range indexes {
+		var answers []string
+		question := clozePattern.ReplaceAllStringFunc(text, func(marker string) string {
+			match := clozePattern.FindStringSubmatch(marker)
+			if n, _ := strconv.Atoi(match[1]); n != index {
+				return strings.TrimSpace(match[2])
+			}
+			answers = append(answers, strings.TrimSpace(match[2]))
+			if hint := strings.TrimSpace(match[3]); hint != "" {
+				return "[" + hint + "]"
+			}
+			return clozeBlank
+		})
+		cards = append(cards, models.ClozeCard{Index: index, Question: question, Answer: strings.Join(answers, ", ")})
+	}
+	return cards, nil
+}
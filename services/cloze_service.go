@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// ErrClozeCardEdit is returned when the question or answer of a card generated from a cloze note is
+// edited directly instead of through the note.
+var ErrClozeCardEdit = errors.New("cloze cards are edited through their cloze note")
+
+// ClozeNoteServiceInterface defines the cloze note operations the handlers depend on.
+type ClozeNoteServiceInterface interface {
+	CreateClozeNote(req *models.CreateClozeNoteRequest) (*models.ClozeNote, error)
+	GetClozeNote(id int) (*models.ClozeNote, error)
+	UpdateClozeNote(id int, req *models.UpdateClozeNoteRequest) (*models.ClozeNote, error)
+	DeleteClozeNote(id int) error
+}
+
+type ClozeNoteService struct {
+	repo db.ClozeNoteRepository
+}
+
+func NewClozeNoteService(repo db.ClozeNoteRepository) *ClozeNoteService {
+	if repo == nil {
+		panic("repository cannot be nil")
+	}
+	return &ClozeNoteService{repo: repo}
+}
+
+// CreateClozeNote stores a cloze note and materialises one flashcard per cloze index of its text.
+func (s *ClozeNoteService) CreateClozeNote(req *models.CreateClozeNoteRequest) (*models.ClozeNote, error) {
+	req.Text = strings.TrimSpace(req.Text)
+	cards, err := RenderCloze(req.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := canonicalizeLanguages(&req.Lang); err != nil {
+		return nil, err
+	}
+
+	req.Tags, err = NormalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(req, cards)
+}
+
+func (s *ClozeNoteService) GetClozeNote(id int) (*models.ClozeNote, error) {
+	return s.repo.GetByID(id)
+}
+
+// UpdateClozeNote changes the text or tags of a cloze note. A new text re-renders the note's cards;
+// cards whose index is still present keep their schedule.
+func (s *ClozeNoteService) UpdateClozeNote(id int, req *models.UpdateClozeNoteRequest) (*models.ClozeNote, error) {
+	if req.Text == nil && req.Tags == nil {
+		return nil, errors.New("at least one field must be provided for update")
+	}
+
+	var cards []models.ClozeCard
+	if req.Text != nil {
+		text := strings.TrimSpace(*req.Text)
+		var err error
+		if cards, err = RenderCloze(text); err != nil {
+			return nil, err
+		}
+		req.Text = &text
+	}
+
+	if req.Tags != nil {
+		tags, err := NormalizeTags(*req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		req.Tags = &tags
+	}
+
+	return s.repo.Update(id, req, cards)
+}
+
+func (s *ClozeNoteService) DeleteClozeNote(id int) error {
+	return s.repo.Delete(id)
+}
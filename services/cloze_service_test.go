@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// mockClozeNoteRepo records the cards passed to it for tests.
+type mockClozeNoteRepo struct {
+	cards []models.ClozeCard
+}
+
+func (m *mockClozeNoteRepo) Create(req *models.CreateClozeNoteRequest, cards []models.ClozeCard) (*models.ClozeNote, error) {
+	m.cards = cards
+	note := &models.ClozeNote{ID: 1, Text: req.Text, Lang: req.Lang, DeckID: req.DeckID}
+	for i, card := range cards {
+		index := card.Index
+		note.Cards = append(note.Cards, &models.Flashcard{ID: i + 1, Question: card.Question, Answer: card.Answer, Tags: req.Tags, ClozeNoteID: &note.ID, ClozeIndex: &index})
+	}
+	return note, nil
+}
+
+func (m *mockClozeNoteRepo) GetByID(id int) (*models.ClozeNote, error) {
+	if id != 1 {
+		return nil, fmt.Errorf("cloze note with id %d not found", id)
+	}
+	return &models.ClozeNote{ID: 1}, nil
+}
+
+func (m *mockClozeNoteRepo) Update(id int, req *models.UpdateClozeNoteRequest, cards []models.ClozeCard) (*models.ClozeNote, error) {
+	m.cards = cards
+	return m.GetByID(id)
+}
+
+func (m *mockClozeNoteRepo) Delete(id int) error {
+	_, err := m.GetByID(id)
+	return err
+}
+
+func TestCreateClozeNoteMaterialisesSiblings(t *testing.T) {
+	repo := &mockClozeNoteRepo{}
+	svc := NewClozeNoteService(repo)
+
+	note, err := svc.CreateClozeNote(&models.CreateClozeNoteRequest{
+		Text: " {{c1::Καλημέρα}}, {{c2::τι κάνεις}}; ",
+		Lang: "EL",
+		Tags: []string{"greeting", " greeting"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(note.Cards) != 2 || note.Lang != "el" {
+		t.Fatalf("expected two cards in el, got %d cards in %q", len(note.Cards), note.Lang)
+	}
+	if note.Cards[1].Question != "Καλημέρα, [...];" || note.Cards[1].Answer != "τι κάνεις" {
+		t.Fatalf("unexpected second card: %+v", note.Cards[1])
+	}
+	if len(note.Cards[0].Tags) != 1 {
+		t.Fatalf("expected normalized tags, got %v", note.Cards[0].Tags)
+	}
+}
+
+func TestCreateClozeNoteValidation(t *testing.T) {
+	svc := NewClozeNoteService(&mockClozeNoteRepo{})
+
+	if _, err := svc.CreateClozeNote(&models.CreateClozeNoteRequest{Text: "plain text"}); !errors.Is(err, ErrInvalidCloze) {
+		t.Fatalf("expected ErrInvalidCloze, got %v", err)
+	}
+	if _, err := svc.CreateClozeNote(&models.CreateClozeNoteRequest{Text: "{{c1::a}}", Lang: "e_"}); !errors.Is(err, ErrInvalidLanguageTag) {
+		t.Fatalf("expected ErrInvalidLanguageTag, got %v", err)
+	}
+}
+
+func TestUpdateClozeNote(t *testing.T) {
+	repo := &mockClozeNoteRepo{}
+	svc := NewClozeNoteService(repo)
+
+	if _, err := svc.UpdateClozeNote(1, &models.UpdateClozeNoteRequest{}); err == nil {
+		t.Fatal("expected an error for an empty update")
+	}
+
+	tags := []string{"A1"}
+	if _, err := svc.UpdateClozeNote(1, &models.UpdateClozeNoteRequest{Tags: &tags}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.cards != nil {
+		t.Fatalf("expected the cards to be kept when only tags change, got %+v", repo.cards)
+	}
+
+	text := "{{c1::ένα}} {{c2::δύο}} {{c3::τρία}}"
+	if _, err := svc.UpdateClozeNote(1, &models.UpdateClozeNoteRequest{Text: &text}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.cards) != 3 {
+		t.Fatalf("expected three re-rendered cards, got %+v", repo.cards)
+	}
+}
+
+func TestUpdateFlashcardRejectsClozeCardText(t *testing.T) {
+	noteID := 1
+	repo := &clozeCardRepo{mockRepo: &mockRepo{}, noteID: &noteID}
+	svc := NewFlashcardService(repo, nil, nil, nil)
+
+	question := "edited"
+	if _, err := svc.UpdateFlashcard(1, &models.UpdateFlashcardRequest{Question: &question}); !errors.Is(err, ErrClozeCardEdit) {
+		t.Fatalf("expected ErrClozeCardEdit, got %v", err)
+	}
+
+	tags := []string{"A1"}
+	if _, err := svc.UpdateFlashcard(1, &models.UpdateFlashcardRequest{Tags: &tags}); err != nil {
+		t.Fatalf("expected tags of a cloze card to be editable, got %v", err)
+	}
+}
+
+// clozeCardRepo is a mockRepo whose flashcards belong to a cloze note.
+type clozeCardRepo struct {
+	*mockRepo
+	noteID *int
+}
+
+func (r *clozeCardRepo) GetByID(id int) (*models.Flashcard, error) {
+	flashcard, err := r.mockRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	flashcard.ClozeNoteID = r.noteID
+	return flashcard, nil
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+func TestRenderCloze(t *testing.T) {
+	cards, err := RenderCloze("Η {{c1::λέξη}} είναι {{c2::ελληνική::language}} και {{c1::παλιά}}.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []models.ClozeCard{
+		{Index: 1, Question: "Η [...] είναι ελληνική και [...].", Answer: "λέξη, παλιά"},
+		{Index: 2, Question: "Η λέξη είναι [language] και παλιά.", Answer: "ελληνική"},
+	}
+	if !reflect.DeepEqual(cards, want) {
+		t.Fatalf("expected %+v, got %+v", want, cards)
+	}
+}
+
+func TestRenderClozeOrdersIndexes(t *testing.T) {
+	cards, err := RenderCloze("{{c3::τρία}} {{c1::ένα}}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cards) != 2 || cards[0].Index != 1 || cards[1].Index != 3 {
+		t.Fatalf("expected cards for indexes 1 and 3, got %+v", cards)
+	}
+}
+
+func TestParseClozeInvalid(t *testing.T) {
+	for _, text := range []string{
+		"no deletions here",
+		"{{c0::μηδέν}}",
+		"{{c51::πολλά}}",
+		"{{c1::  }}",
+		"{{c1::ανοιχτό",
+		"{{c1::ένα}} {{c2::δύο",
+	} {
+		if _, err := ParseCloze(text); !errors.Is(err, ErrInvalidCloze) {
+			t.Errorf("%q: expected ErrInvalidCloze, got %v", text, err)
+		}
+	}
+}
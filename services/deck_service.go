@@ -0,0 +1,106 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// DeckServiceInterface defines the deck operations the handlers depend on.
+type DeckServiceInterface interface {
+	CreateDeck(req *models.CreateDeckRequest) (*models.Deck, error)
+	GetAllDecks() ([]*models.Deck, error)
+	GetDeckByID(id int) (*models.Deck, error)
+	UpdateDeck(id int, req *models.UpdateDeckRequest) (*models.Deck, error)
+	DeleteDeck(id int) error
+	MoveDeck(id int, req *models.MoveDeckRequest) (*models.Deck, error)
+	GetDeckTree(id int) (*models.DeckTree, error)
+}
+
+type DeckService struct {
+	repo db.DeckRepository
+}
+
+func NewDeckService(repo db.DeckRepository) *DeckService {
+	if repo == nil {
+		panic("repository cannot be nil")
+	}
+	return &DeckService{repo: repo}
+}
+
+func (s *DeckService) CreateDeck(req *models.CreateDeckRequest) (*models.Deck, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, errors.New("deck name cannot be empty")
+	}
+
+	return s.repo.Create(req)
+}
+
+func (s *DeckService) GetAllDecks() ([]*models.Deck, error) {
+	return s.repo.GetAll()
+}
+
+func (s *DeckService) GetDeckByID(id int) (*models.Deck, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *DeckService) UpdateDeck(id int, req *models.UpdateDeckRequest) (*models.Deck, error) {
+	if req.Name == nil && req.Description == nil {
+		return nil, errors.New("at least one field must be provided for update")
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("deck name cannot be empty")
+		}
+		req.Name = &name
+	}
+
+	return s.repo.Update(id, req)
+}
+
+func (s *DeckService) DeleteDeck(id int) error {
+	return s.repo.Delete(id)
+}
+
+// MoveDeck re-parents a deck together with its whole subtree.
+func (s *DeckService) MoveDeck(id int, req *models.MoveDeckRequest) (*models.Deck, error) {
+	if req.ParentID != nil && *req.ParentID == id {
+		return nil, db.ErrDeckCycle
+	}
+
+	return s.repo.Move(id, req.ParentID)
+}
+
+// GetDeckTree returns a deck with all of its descendants nested below it.
+func (s *DeckService) GetDeckTree(id int) (*models.DeckTree, error) {
+	decks, err := s.repo.GetSubtree(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildDeckTree(id, decks), nil
+}
+
+// buildDeckTree nests a flat list of decks under the deck with the given root ID. Children keep the
+// order in which they appear in decks.
+func buildDeckTree(rootID int, decks []*models.Deck) *models.DeckTree {
+	nodes := make(map[int]*models.DeckTree, len(decks))
+	for _, deck := range decks {
+		nodes[deck.ID] = &models.DeckTree{Deck: *deck, Children: []*models.DeckTree{}}
+	}
+
+	for _, deck := range decks {
+		if deck.ID == rootID || deck.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*deck.ParentID]; ok {
+			parent.Children = append(parent.Children, nodes[deck.ID])
+		}
+	}
+
+	return nodes[rootID]
+}
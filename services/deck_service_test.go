@@ -0,0 +1,140 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// mockDeckRepo is a small in-memory implementation of db.DeckRepository for tests.
+type mockDeckRepo struct{}
+
+func (m *mockDeckRepo) Create(req *models.CreateDeckRequest) (*models.Deck, error) {
+	now := time.Now()
+	return &models.Deck{ID: 1, Name: req.Name, Description: req.Description, CreatedAt: now, UpdatedAt: now}, nil
+}
+
+func (m *mockDeckRepo) GetAll() ([]*models.Deck, error) {
+	now := time.Now()
+	return []*models.Deck{{ID: 1, Name: "Greek", CreatedAt: now, UpdatedAt: now}}, nil
+}
+
+func (m *mockDeckRepo) GetByID(id int) (*models.Deck, error) {
+	if id != 1 {
+		return nil, fmt.Errorf("deck with id %d not found", id)
+	}
+	now := time.Now()
+	return &models.Deck{ID: 1, Name: "Greek", CreatedAt: now, UpdatedAt: now}, nil
+}
+
+func (m *mockDeckRepo) Update(id int, req *models.UpdateDeckRequest) (*models.Deck, error) {
+	deck, err := m.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		deck.Name = *req.Name
+	}
+	if req.Description != nil {
+		deck.Description = *req.Description
+	}
+	return deck, nil
+}
+
+func (m *mockDeckRepo) Delete(id int) error {
+	_, err := m.GetByID(id)
+	return err
+}
+
+func (m *mockDeckRepo) GetSubtree(id int) ([]*models.Deck, error) {
+	if id != 1 {
+		return nil, fmt.Errorf("deck with id %d not found", id)
+	}
+	return []*models.Deck{
+		{ID: 1, Name: "Greek"},
+		{ID: 2, ParentID: intPtr(1), Name: "Unit 1"},
+		{ID: 4, ParentID: intPtr(2), Name: "Lesson 1"},
+		{ID: 3, ParentID: intPtr(1), Name: "Unit 2"},
+	}, nil
+}
+
+func (m *mockDeckRepo) Move(id int, parentID *int) (*models.Deck, error) {
+	deck, err := m.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	deck.ParentID = parentID
+	return deck, nil
+}
+
+func TestCreateDeckTrimsName(t *testing.T) {
+	svc := NewDeckService(&mockDeckRepo{})
+
+	deck, err := svc.CreateDeck(&models.CreateDeckRequest{Name: "  Greek A2  "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deck.Name != "Greek A2" {
+		t.Fatalf("expected trimmed name 'Greek A2', got '%s'", deck.Name)
+	}
+}
+
+func TestCreateDeckRequiresName(t *testing.T) {
+	svc := NewDeckService(&mockDeckRepo{})
+
+	if _, err := svc.CreateDeck(&models.CreateDeckRequest{Name: "   "}); err == nil {
+		t.Fatalf("expected error for blank deck name")
+	}
+}
+
+func TestUpdateDeckValidation(t *testing.T) {
+	svc := NewDeckService(&mockDeckRepo{})
+
+	if _, err := svc.UpdateDeck(1, &models.UpdateDeckRequest{}); err == nil {
+		t.Fatalf("expected error when update request has no fields")
+	}
+
+	blank := " "
+	if _, err := svc.UpdateDeck(1, &models.UpdateDeckRequest{Name: &blank}); err == nil {
+		t.Fatalf("expected error for blank deck name")
+	}
+}
+
+func TestGetDeckTreeNestsDescendants(t *testing.T) {
+	svc := NewDeckService(&mockDeckRepo{})
+
+	tree, err := svc.GetDeckTree(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tree.Name != "Greek" || len(tree.Children) != 2 {
+		t.Fatalf("expected root 'Greek' with 2 children, got '%s' with %d", tree.Name, len(tree.Children))
+	}
+	unit1 := tree.Children[0]
+	if unit1.Name != "Unit 1" || len(unit1.Children) != 1 || unit1.Children[0].Name != "Lesson 1" {
+		t.Fatalf("expected 'Unit 1' to contain 'Lesson 1', got %+v", unit1)
+	}
+	if len(tree.Children[1].Children) != 0 {
+		t.Fatalf("expected 'Unit 2' to have no children")
+	}
+}
+
+func TestMoveDeckUnderItselfFails(t *testing.T) {
+	svc := NewDeckService(&mockDeckRepo{})
+
+	if _, err := svc.MoveDeck(1, &models.MoveDeckRequest{ParentID: intPtr(1)}); !errors.Is(err, db.ErrDeckCycle) {
+		t.Fatalf("expected ErrDeckCycle, got %v", err)
+	}
+
+	deck, err := svc.MoveDeck(1, &models.MoveDeckRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deck.ParentID != nil {
+		t.Fatalf("expected a nil parent to make the deck top-level")
+	}
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// flashcardCursor is the content of an encoded flashcard list cursor. It records the ordering it was
+// issued for, so that a cursor cannot be replayed against a list sorted differently.
+type flashcardCursor struct {
+	Sort    string `json:"s"`
+	Order   string `json:"o"`
+	SortKey string `json:"k"`
+	ID      int    `json:"id"`
+}
+
+// encodeFlashcardCursor turns a list position into an opaque, URL-safe cursor.
+func encodeFlashcardCursor(sort, order string, position *models.FlashcardCursor) string {
+	data, err := json.Marshal(flashcardCursor{Sort: sort, Order: order, SortKey: position.SortKey, ID: position.ID})
+	if err != nil {
+		// Marshalling a struct of strings and ints cannot fail.
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeFlashcardCursor parses a cursor issued by encodeFlashcardCursor for the same ordering.
+func decodeFlashcardCursor(cursor, sort, order string) (*models.FlashcardCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var decoded flashcardCursor
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if decoded.Sort != sort || decoded.Order != order || decoded.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &models.FlashcardCursor{SortKey: decoded.SortKey, ID: decoded.ID}, nil
+}
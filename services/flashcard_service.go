@@ -3,7 +3,10 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 
 	"github.com/akolybelnikov/flashcards/db"
 	"github.com/akolybelnikov/flashcards/models"
@@ -13,38 +16,136 @@ import (
 // provide a mock service implementation without depending on the concrete type.
 type FlashcardServiceInterface interface {
 	CreateFlashcard(req *models.CreateFlashcardRequest) (*models.Flashcard, bool, string, error)
-	GetAllFlashcards() ([]*models.Flashcard, error)
+	PreviewTranslation(req *models.TranslatePreviewRequest) (*models.TranslatePreview, error)
+	GetAllFlashcards(opts *models.FlashcardListOptions, cursor string) (*models.FlashcardPage, error)
+	GetFlashcardsByDeck(deckID int) ([]*models.Flashcard, error)
 	GetFlashcardByID(id int) (*models.Flashcard, error)
 	UpdateFlashcard(id int, req *models.UpdateFlashcardRequest) (*models.Flashcard, error)
 	DeleteFlashcard(id int) error
-	GetRandomFlashcard() (*models.Flashcard, error)
+	GetRandomFlashcard(filter *models.FlashcardFilter) (*models.Flashcard, error)
+	GetRandomFlashcardByDeck(deckID int) (*models.Flashcard, error)
 	GenerateAIHint(flashcard *models.Flashcard, lang string) *string
+	ReviewFlashcard(id int, req *models.ReviewRequest) (*models.ScheduledFlashcard, error)
+	GetDueFlashcards(limit int) ([]*models.ScheduledFlashcard, error)
+	GetDueFlashcardsByDeck(deckID int, limit int) ([]*models.ScheduledFlashcard, error)
+	GetReviewLogs(id int, limit int, cursor string) (*models.ReviewLogPage, error)
+	SearchFlashcards(req *models.SearchRequest) ([]*models.SearchResult, error)
+	AnswerFlashcard(id int, req *models.AnswerRequest) (*models.AnswerResult, error)
+	GetExamples(id int) (*models.CardExamples, error)
+	GenerateExamples(id int, req *models.GenerateExamplesRequest) (*models.CardExamples, error)
+	GetReviewQueue(limit int) ([]*models.Flashcard, error)
+	ApproveFlashcard(id int, req *models.ApproveFlashcardRequest) (*models.Flashcard, error)
+	RejectFlashcard(id int, req *models.RejectFlashcardRequest) (*models.Flashcard, error)
 }
 
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrEmptyAnswer is returned when a typed answer has no content.
+var ErrEmptyAnswer = errors.New("answer must not be empty")
+
+// ErrAIUnavailable is returned when a feature needs a language model but none is configured.
+var ErrAIUnavailable = errors.New("AI generation not available: no language model configured")
+
+// ErrInvalidExampleCount is returned when too few or too many example sentences are requested.
+var ErrInvalidExampleCount = fmt.Errorf("n must be between 1 and %d", maxExampleCount)
+
+// ErrExampleLanguages is returned when example sentences are requested for a flashcard whose sides
+// have no languages.
+var ErrExampleLanguages = errors.New("question_lang and answer_lang are required to generate examples")
+
+// ErrInvalidSort is returned for an unknown flashcard ordering or sort direction.
+var ErrInvalidSort = errors.New("invalid sort")
+
+// defaultSortOrders lists the supported flashcard orderings with the direction used when none is
+// requested: newest first for timestamps, alphabetical for questions and most overdue first for due
+// dates.
+var defaultSortOrders = map[string]string{
+	models.SortByCreatedAt: models.OrderDesc,
+	models.SortByUpdatedAt: models.OrderDesc,
+	models.SortByQuestion:  models.OrderAsc,
+	models.SortByDue:       models.OrderAsc,
+}
+
+const (
+	defaultDueLimit = 20
+	maxDueLimit     = 100
+
+	defaultReviewLogLimit = 20
+	maxReviewLogLimit     = 100
+
+	defaultFlashcardLimit = 50
+	maxFlashcardLimit     = 200
+
+	defaultExampleCount = 3
+	maxExampleCount     = 10
+
+	// maxReviewAttempts is how many times a review is scheduled when concurrent reviews of the same
+	// card keep changing its schedule.
+	maxReviewAttempts = 3
+)
+
 type FlashcardService struct {
 	repo      db.FlashcardRepository
 	llmClient LLMClient
+	scheduler Scheduler
+	grader    *AnswerGrader
+	clock     Clock
 }
 
-func NewFlashcardService(repo db.FlashcardRepository, llmClient LLMClient) *FlashcardService {
+// NewFlashcardService creates the flashcard service. A nil scheduler falls back to SM-2. The language
+// registry tells the answer grader which languages treat diacritics as significant; with a nil
+// registry all of them do.
+func NewFlashcardService(repo db.FlashcardRepository, llmClient LLMClient, scheduler Scheduler, languages *LanguageRegistry) *FlashcardService {
 	if repo == nil {
 		panic("repository cannot be nil")
 	}
+	if scheduler == nil {
+		scheduler = NewSM2Scheduler()
+	}
 	return &FlashcardService{
 		repo:      repo,
 		llmClient: llmClient,
+		scheduler: scheduler,
+		grader:    NewAnswerGrader(languages),
+		clock:     systemClock{},
 	}
 }
 
 func (s *FlashcardService) CreateFlashcard(req *models.CreateFlashcardRequest) (*models.Flashcard, bool, string, error) {
+	tags, err := NormalizeTags(req.Tags)
+	if err != nil {
+		return nil, false, "", err
+	}
+	req.Tags = tags
+
+	if err := canonicalizeLanguages(&req.QuestionLang, &req.AnswerLang); err != nil {
+		return nil, false, "", err
+	}
+
+	// A candidate chosen from a translation preview fills in the empty side without translating it
+	// again, and is recorded as generated by the model and prompt version of the preview.
+	if req.PreviewID != "" {
+		preview, err := s.applyTranslationPreview(req)
+		if err != nil {
+			return nil, false, "", err
+		}
+		fc, err := s.storeFlashcard(req, &models.AIProvenance{
+			Fields:        []string{preview.TranslatedField},
+			Model:         preview.Model,
+			PromptVersion: preview.PromptVersion,
+		})
+		return fc, true, preview.TranslatedField, err
+	}
+
 	// Case 1: Both question and answer provided - no translation needed
 	if req.Question != "" && req.Answer != "" {
-		fc, err := s.repo.Create(req)
+		fc, err := s.storeFlashcard(req, nil)
 		return fc, false, "", err
 	}
 
 	if s.llmClient == nil {
-		return nil, false, "", errors.New("AI translation not available: API key not configured")
+		return nil, false, "", ErrAIUnavailable
 	}
 
 	translatedField := ""
@@ -53,10 +154,10 @@ func (s *FlashcardService) CreateFlashcard(req *models.CreateFlashcardRequest) (
 	if req.Question != "" && req.Answer == "" {
 		translation, err := s.llmClient.Translate(context.Background(), req.Question, req.QuestionLang, req.AnswerLang)
 		if err != nil {
-			return nil, false, "", errors.New("failed to translate question to answer: " + err.Error())
+			return nil, false, "", fmt.Errorf("%w question to answer: %v", ErrTranslationFailed, err)
 		}
 
-		req.Answer = translation
+		req.Answer = translation.Translation
 		translatedField = "answer"
 	}
 
@@ -64,43 +165,438 @@ func (s *FlashcardService) CreateFlashcard(req *models.CreateFlashcardRequest) (
 	if req.Answer != "" && req.Question == "" {
 		translation, err := s.llmClient.Translate(context.Background(), req.Answer, req.AnswerLang, req.QuestionLang)
 		if err != nil {
-			return nil, false, "", errors.New("failed to translate answer to question: " + err.Error())
+			return nil, false, "", fmt.Errorf("%w answer to question: %v", ErrTranslationFailed, err)
 		}
 
-		req.Question = translation
+		req.Question = translation.Translation
 		translatedField = "question"
 	}
 
-	flashcard, err := s.repo.Create(req)
+	flashcard, err := s.storeFlashcard(req, s.generatedBy(translatedField))
 	return flashcard, true, translatedField, err
 }
 
-func (s *FlashcardService) GetAllFlashcards() ([]*models.Flashcard, error) {
-	return s.repo.GetAll()
+// storeFlashcard enriches the grammar of a new card if requested and stores it, recording which of
+// its fields the language model generated. Such cards wait for review before they are studied.
+// translated describes the side that was translated, if any.
+func (s *FlashcardService) storeFlashcard(req *models.CreateFlashcardRequest, translated *models.AIProvenance) (*models.Flashcard, error) {
+	if err := s.enrichGrammar(req); err != nil {
+		return nil, err
+	}
+
+	provenance := translated
+	if req.Grammar != nil {
+		if provenance == nil {
+			provenance = s.generatedBy()
+		}
+		provenance.Fields = append(provenance.Fields, models.AIFieldGrammar)
+	}
+
+	req.AIProvenance = nil
+	req.Status = models.FlashcardStatusActive
+	if provenance != nil {
+		provenance.GeneratedAt = s.clock.Now()
+		req.Status = models.FlashcardStatusPendingReview
+		req.AIProvenance = provenance
+	}
+
+	return s.repo.Create(req)
+}
+
+// generatedBy returns the provenance of fields generated now by the configured language model.
+func (s *FlashcardService) generatedBy(fields ...string) *models.AIProvenance {
+	return &models.AIProvenance{Fields: fields, Model: s.llmClient.Model(), PromptVersion: promptVersion}
+}
+
+// PreviewTranslation lists candidate translations of the filled-in side of a new card into the
+// language of its empty side, so that an ambiguous word can be translated deliberately. The preview
+// is stored for TranslationPreviewTTL, and CreateFlashcard fills in the empty side with the candidate
+// chosen by its preview ID and index.
+func (s *FlashcardService) PreviewTranslation(req *models.TranslatePreviewRequest) (*models.TranslatePreview, error) {
+	n := req.N
+	if n == 0 {
+		n = defaultTranslationCandidates
+	}
+	if n < 1 || n > maxTranslationCandidates {
+		return nil, ErrInvalidCandidateCount
+	}
+
+	if err := canonicalizeLanguages(&req.QuestionLang, &req.AnswerLang); err != nil {
+		return nil, err
+	}
+
+	preview := &models.TranslatePreview{}
+	var sourceLang, targetLang string
+	switch {
+	case req.Question != "" && req.Answer == "":
+		preview.Source, preview.TranslatedField = req.Question, "answer"
+		sourceLang, targetLang = req.QuestionLang, req.AnswerLang
+	case req.Answer != "" && req.Question == "":
+		preview.Source, preview.TranslatedField = req.Answer, "question"
+		sourceLang, targetLang = req.AnswerLang, req.QuestionLang
+	default:
+		return nil, ErrTranslationSide
+	}
+
+	if s.llmClient == nil {
+		return nil, ErrAIUnavailable
+	}
+
+	candidates, err := s.llmClient.TranslateCandidates(context.Background(), preview.Source, sourceLang, targetLang, n)
+	if err != nil {
+		return nil, err
+	}
+	preview.Candidates = candidates
+
+	id, err := newRandomID()
+	if err != nil {
+		return nil, err
+	}
+	preview.ID = id
+
+	stored := &models.StoredTranslationPreview{
+		TranslatePreview: *preview,
+		QuestionLang:     req.QuestionLang,
+		AnswerLang:       req.AnswerLang,
+		Model:            s.llmClient.Model(),
+		PromptVersion:    promptVersion,
+	}
+	if err := s.repo.CreateTranslationPreview(stored); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.DeleteTranslationPreviewsCreatedBefore(s.clock.Now().Add(-TranslationPreviewTTL)); err != nil {
+		log.Printf("Failed to delete expired translation previews: %v", err)
+	}
+
+	return &stored.TranslatePreview, nil
+}
+
+// enrichGrammar fills in the grammar of the Greek side of a new card when the request asks for it.
+// Enrichment is optional: when the language model is unavailable, fails or gets the grammar wrong, the
+// card is stored without grammar and a warning is added to the request.
+func (s *FlashcardService) enrichGrammar(req *models.CreateFlashcardRequest) error {
+	req.Grammar = nil
+	if !req.EnrichGrammar {
+		return nil
+	}
+
+	word, lang, ok := greekSide(req)
+	if !ok {
+		return ErrGrammarLanguage
+	}
+	if s.llmClient == nil {
+		req.Warnings = append(req.Warnings, "grammar not enriched: "+ErrAIUnavailable.Error())
+		return nil
+	}
+
+	grammar, err := s.llmClient.ExtractGrammar(context.Background(), word, lang)
+	if err == nil {
+		err = validateGrammar(grammar)
+	}
+	if err != nil {
+		log.Printf("Grammar enrichment of %q failed: %v", word, err)
+		req.Warnings = append(req.Warnings, "grammar not enriched: the language model's grammar could not be used")
+		return nil
+	}
+
+	req.Grammar = grammar
+	return nil
+}
+
+// GetAllFlashcards returns one page of flashcards. Sort defaults to creation time and Order to the
+// ordering's natural direction. cursor is the NextCursor of the previous page, or empty for the first
+// page; it is only valid with the sort and order it was issued for.
+func (s *FlashcardService) GetAllFlashcards(opts *models.FlashcardListOptions, cursor string) (*models.FlashcardPage, error) {
+	listOpts := models.FlashcardListOptions{}
+	if opts != nil {
+		listOpts = *opts
+	}
+
+	if listOpts.Sort == "" {
+		listOpts.Sort = models.SortByCreatedAt
+	}
+	defaultOrder, ok := defaultSortOrders[listOpts.Sort]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown sort %q", ErrInvalidSort, listOpts.Sort)
+	}
+	if listOpts.Order == "" {
+		listOpts.Order = defaultOrder
+	}
+	if listOpts.Order != models.OrderAsc && listOpts.Order != models.OrderDesc {
+		return nil, fmt.Errorf("%w: order must be %q or %q", ErrInvalidSort, models.OrderAsc, models.OrderDesc)
+	}
+
+	listOpts.Limit = clampLimit(listOpts.Limit, defaultFlashcardLimit, maxFlashcardLimit)
+	listOpts.After = nil
+	if cursor != "" {
+		after, err := decodeFlashcardCursor(cursor, listOpts.Sort, listOpts.Order)
+		if err != nil {
+			return nil, err
+		}
+		listOpts.After = after
+	}
+
+	flashcards, next, err := s.repo.GetAll(&listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.FlashcardPage{Flashcards: flashcards}
+	if next != nil {
+		page.NextCursor = encodeFlashcardCursor(listOpts.Sort, listOpts.Order, next)
+	}
+	if page.Flashcards == nil {
+		page.Flashcards = []*models.Flashcard{}
+	}
+
+	return page, nil
+}
+
+func (s *FlashcardService) GetFlashcardsByDeck(deckID int) ([]*models.Flashcard, error) {
+	return s.repo.GetAllByDeck(deckID)
 }
 
 func (s *FlashcardService) GetFlashcardByID(id int) (*models.Flashcard, error) {
-	return s.repo.GetByID(id)
+	flashcard, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	renderNote(flashcard)
+	return flashcard, nil
 }
 
 func (s *FlashcardService) UpdateFlashcard(id int, req *models.UpdateFlashcardRequest) (*models.Flashcard, error) {
-	if req.Question == nil && req.Answer == nil {
-		return nil, errors.New("at least one field must be provided for update")
+	if err := s.prepareUpdate(id, req); err != nil {
+		return nil, err
 	}
-
 	return s.repo.Update(id, req)
 }
 
+// prepareUpdate validates an update of a flashcard and normalizes its languages and tags.
+func (s *FlashcardService) prepareUpdate(id int, req *models.UpdateFlashcardRequest) error {
+	if req.Question == nil && req.Answer == nil && req.QuestionLang == nil && req.AnswerLang == nil && req.DeckID == nil && req.Tags == nil {
+		return errors.New("at least one field must be provided for update")
+	}
+	if req.DeckID != nil && *req.DeckID < 0 {
+		return errors.New("deck_id must not be negative")
+	}
+
+	for _, lang := range []*string{req.QuestionLang, req.AnswerLang} {
+		if lang != nil {
+			if err := canonicalizeLanguages(lang); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The text of cloze and note cards is rendered from their note, so it is edited there
+	if req.Question != nil || req.Answer != nil {
+		flashcard, err := s.repo.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if flashcard.ClozeNoteID != nil {
+			return ErrClozeCardEdit
+		}
+		if flashcard.NoteID != nil {
+			return ErrNoteCardEdit
+		}
+	}
+
+	if req.Tags != nil {
+		tags, err := NormalizeTags(*req.Tags)
+		if err != nil {
+			return err
+		}
+		req.Tags = &tags
+	}
+
+	return nil
+}
+
 func (s *FlashcardService) DeleteFlashcard(id int) error {
 	return s.repo.Delete(id)
 }
 
-func (s *FlashcardService) GetRandomFlashcard() (*models.Flashcard, error) {
-	return s.repo.GetRandom()
+func (s *FlashcardService) GetRandomFlashcard(filter *models.FlashcardFilter) (*models.Flashcard, error) {
+	flashcard, err := s.repo.GetRandom(filter)
+	if err != nil {
+		return nil, err
+	}
+	renderNote(flashcard)
+	return flashcard, nil
+}
+
+func (s *FlashcardService) GetRandomFlashcardByDeck(deckID int) (*models.Flashcard, error) {
+	flashcard, err := s.repo.GetRandomByDeck(deckID)
+	if err != nil {
+		return nil, err
+	}
+	renderNote(flashcard)
+	return flashcard, nil
+}
+
+// ReviewFlashcard grades a flashcard on the 0-5 scale, stores the next schedule computed by the
+// configured scheduler and records the review in the card's log. A review that races another review
+// of the same card is scheduled again from the schedule the other one left.
+func (s *FlashcardService) ReviewFlashcard(id int, req *models.ReviewRequest) (*models.ScheduledFlashcard, error) {
+	if req.Grade == nil {
+		return nil, errors.New("grade is required")
+	}
+	grade := *req.Grade
+	if err := validateGrade(grade); err != nil {
+		return nil, err
+	}
+	if req.DurationMS != nil && *req.DurationMS < 0 {
+		return nil, errors.New("duration_ms cannot be negative")
+	}
+
+	flashcard, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if flashcard.Status != models.FlashcardStatusActive {
+		return nil, ErrFlashcardNotActive
+	}
+	renderNote(flashcard)
+
+	now := s.clock.Now()
+	for attempt := 1; ; attempt++ {
+		prev, err := s.repo.GetSchedule(id)
+		if err != nil {
+			return nil, err
+		}
+
+		next, err := s.scheduler.Schedule(id, prev, grade, now)
+		if err != nil {
+			return nil, err
+		}
+
+		reviewLog := &models.ReviewLog{
+			FlashcardID: id,
+			UserID:      models.DefaultUserID,
+			Grade:       grade,
+			DurationMS:  req.DurationMS,
+			Scheduler:   s.scheduler.Name(),
+			StateBefore: prev,
+			StateAfter:  next,
+			ReviewedAt:  now,
+		}
+		if prev != nil && prev.LastReviewedAt != nil {
+			elapsedDays := now.Sub(*prev.LastReviewedAt).Hours() / 24
+			reviewLog.ElapsedDays = &elapsedDays
+		}
+
+		// A concurrent review of the card was saved after prev was read, so schedule this review
+		// again from the schedule that one left
+		err = s.repo.SaveReview(next, reviewLog)
+		if errors.Is(err, db.ErrScheduleChanged) && attempt < maxReviewAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.ScheduledFlashcard{Flashcard: flashcard, Schedule: next}, nil
+	}
+}
+
+// AnswerFlashcard grades an answer typed for a flashcard and reviews the card with the resulting
+// grade: 4 for a correct answer, 3 for an almost correct one and 1 otherwise.
+func (s *FlashcardService) AnswerFlashcard(id int, req *models.AnswerRequest) (*models.AnswerResult, error) {
+	if strings.TrimSpace(req.Answer) == "" {
+		return nil, ErrEmptyAnswer
+	}
+
+	flashcard, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if flashcard.Status != models.FlashcardStatusActive {
+		return nil, ErrFlashcardNotActive
+	}
+	renderNote(flashcard)
+
+	graded := s.grader.Grade(flashcard.Answer, req.Answer, flashcard.AnswerLang)
+	scheduled, err := s.ReviewFlashcard(id, &models.ReviewRequest{
+		Grade:      &graded.Grade,
+		DurationMS: req.DurationMS,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AnswerResult{
+		Verdict:      graded.Verdict,
+		Expected:     flashcard.Answer,
+		Diff:         graded.Diff,
+		Explanations: graded.Explanations,
+		Grade:        graded.Grade,
+		Schedule:     scheduled.Schedule,
+	}, nil
+}
+
+// GetDueFlashcards returns up to limit flashcards whose due date has passed, most overdue first.
+func (s *FlashcardService) GetDueFlashcards(limit int) ([]*models.ScheduledFlashcard, error) {
+	return renderScheduledNotes(s.repo.GetDue(s.clock.Now(), clampLimit(limit, defaultDueLimit, maxDueLimit)))
+}
+
+// GetDueFlashcardsByDeck is GetDueFlashcards restricted to the flashcards of a deck.
+func (s *FlashcardService) GetDueFlashcardsByDeck(deckID int, limit int) ([]*models.ScheduledFlashcard, error) {
+	return renderScheduledNotes(s.repo.GetDueByDeck(deckID, s.clock.Now(), clampLimit(limit, defaultDueLimit, maxDueLimit)))
+}
+
+// renderScheduledNotes renders the cards created from notes among due flashcards.
+func renderScheduledNotes(scheduled []*models.ScheduledFlashcard, err error) ([]*models.ScheduledFlashcard, error) {
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range scheduled {
+		renderNote(s.Flashcard)
+	}
+	return scheduled, nil
 }
 
-// GenerateAIHint attempts to generate a short hint using OpenAI. It returns nil if the generation fails
-// or if the OpenAI client was not initialized.
+// GetReviewLogs returns one page of a flashcard's review history, newest first. cursor is the
+// NextCursor of the previous page, or empty for the first page.
+func (s *FlashcardService) GetReviewLogs(id int, limit int, cursor string) (*models.ReviewLogPage, error) {
+	limit = clampLimit(limit, defaultReviewLogLimit, maxReviewLogLimit)
+
+	var beforeID int64
+	if cursor != "" {
+		parsed, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || parsed <= 0 {
+			return nil, ErrInvalidCursor
+		}
+		beforeID = parsed
+	}
+
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra row to find out whether another page follows.
+	logs, err := s.repo.GetReviewLogs(id, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.ReviewLogPage{Reviews: logs}
+	if len(logs) > limit {
+		page.Reviews = logs[:limit]
+		page.NextCursor = strconv.FormatInt(logs[limit-1].ID, 10)
+	}
+	if page.Reviews == nil {
+		page.Reviews = []*models.ReviewLog{}
+	}
+
+	return page, nil
+}
+
+// GenerateAIHint attempts to generate a short hint by translating the question from the card's
+// question language into lang, or into its answer language when lang is empty. It returns nil if
+// the languages are unknown, the generation fails or the LLM client was not initialized.
 func (s *FlashcardService) GenerateAIHint(flashcard *models.Flashcard, lang string) *string {
 	if s == nil || s.llmClient == nil {
 		log.Printf("AI hint generation not available: llm not initialized")
@@ -111,11 +607,13 @@ func (s *FlashcardService) GenerateAIHint(flashcard *models.Flashcard, lang stri
 	// In the future this could be expanded to generate more sophisticated hints
 	ctx := context.Background()
 
-	// Determine source and target language based on lang parameter
-	sourceLang := "en"
+	sourceLang := flashcard.QuestionLang
 	targetLang := lang
 	if targetLang == "" {
-		targetLang = "el" // default to Greek
+		targetLang = flashcard.AnswerLang
+	}
+	if sourceLang == "" || targetLang == "" {
+		return nil
 	}
 
 	hint, err := s.llmClient.Translate(ctx, flashcard.Question, sourceLang, targetLang)
@@ -124,10 +622,105 @@ func (s *FlashcardService) GenerateAIHint(flashcard *models.Flashcard, lang stri
 		return nil
 	}
 
-	return &hint
+	return &hint.Translation
+}
+
+// GetExamples returns the example sentences stored for a flashcard.
+func (s *FlashcardService) GetExamples(id int) (*models.CardExamples, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	examples, err := s.repo.GetExamples(id)
+	if err != nil {
+		return nil, err
+	}
+	if examples == nil {
+		examples = []*models.CardExample{}
+	}
+
+	return &models.CardExamples{FlashcardID: id, Examples: examples}, nil
+}
+
+// GenerateExamples asks the language model for sentences that use the answer of a flashcard, with
+// translations into the language of its question, and stores them with the card. A card that already
+// has examples keeps them, so they are only generated once, unless regeneration is requested.
+func (s *FlashcardService) GenerateExamples(id int, req *models.GenerateExamplesRequest) (*models.CardExamples, error) {
+	n := req.N
+	if n == 0 {
+		n = defaultExampleCount
+	}
+	if n < 1 || n > maxExampleCount {
+		return nil, ErrInvalidExampleCount
+	}
+
+	flashcard, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	renderNote(flashcard)
+
+	if !req.Regenerate {
+		stored, err := s.repo.GetExamples(id)
+		if err != nil {
+			return nil, err
+		}
+		if len(stored) > 0 {
+			return &models.CardExamples{FlashcardID: id, Examples: stored}, nil
+		}
+	}
+
+	if s.llmClient == nil {
+		return nil, ErrAIUnavailable
+	}
+	if flashcard.QuestionLang == "" || flashcard.AnswerLang == "" {
+		return nil, ErrExampleLanguages
+	}
+
+	sentences, err := s.llmClient.GenerateExamples(context.Background(), flashcard.Answer, flashcard.AnswerLang, flashcard.QuestionLang, n)
+	if err != nil {
+		return nil, err
+	}
+
+	examples, err := s.repo.ReplaceExamples(id, sentences)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CardExamples{FlashcardID: id, Examples: examples, Generated: true}, nil
+}
+
+// canonicalizeLanguages rewrites non-empty language codes in their canonical BCP 47 form.
+func canonicalizeLanguages(langs ...*string) error {
+	for _, lang := range langs {
+		if *lang == "" {
+			continue
+		}
+		canonical, err := CanonicalLanguageTag(*lang)
+		if err != nil {
+			return err
+		}
+		*lang = canonical
+	}
+	return nil
+}
+
+// clampLimit applies the default to a non-positive limit and caps it at max.
+func clampLimit(limit, defaultLimit, max int) int {
+	if limit <= 0 {
+		return defaultLimit
+	}
+	if limit > max {
+		return max
+	}
+	return limit
 }
 
 func (s *FlashcardService) getTranslation(term, sourceLang, targetLang string) (string, error) {
 	ctx := context.Background()
-	return s.llmClient.Translate(ctx, term, sourceLang, targetLang)
+	translation, err := s.llmClient.Translate(ctx, term, sourceLang, targetLang)
+	if err != nil {
+		return "", err
+	}
+	return translation.Translation, nil
 }
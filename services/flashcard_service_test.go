@@ -1,30 +1,74 @@
 package services
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/akolybelnikov/flashcards/db"
 	"github.com/akolybelnikov/flashcards/models"
 )
 
+// fakeClock is a Clock that always returns the same time.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c fakeClock) Now() time.Time {
+	return c.now
+}
+
 // mockRepo is a small in-memory implementation of db.FlashcardRepository for tests.
-type mockRepo struct{}
+type mockRepo struct {
+	created        *models.CreateFlashcardRequest
+	savedSchedule  *models.CardSchedule
+	reviewLogs     []*models.ReviewLog
+	listOptions    *models.FlashcardListOptions
+	searchLang     string
+	searchResults  []*models.SearchResult
+	fuzzyThreshold float64
+	examples       []*models.CardExample
+	pendingLimit   int
+	approvedBy     string
+	approvedAt     time.Time
+	approvedEdit   *models.UpdateFlashcardRequest
+	previews       map[string]*models.StoredTranslationPreview
+}
 
 func (m *mockRepo) Create(req *models.CreateFlashcardRequest) (*models.Flashcard, error) {
+	m.created = req
 	now := time.Now()
-	return &models.Flashcard{ID: 1, Question: req.Question, Answer: req.Answer, CreatedAt: now, UpdatedAt: now}, nil
+	var reverseID *int
+	if req.Reversible {
+		reverseID = intPtr(2)
+	}
+	return &models.Flashcard{
+		ID:           1,
+		Question:     req.Question,
+		Answer:       req.Answer,
+		QuestionLang: req.QuestionLang,
+		AnswerLang:   req.AnswerLang,
+		Tags:         req.Tags,
+		ReverseID:    reverseID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
 }
 
-func (m *mockRepo) GetAll() ([]*models.Flashcard, error) {
+func (m *mockRepo) GetAll(opts *models.FlashcardListOptions) ([]*models.Flashcard, *models.FlashcardCursor, error) {
+	m.listOptions = opts
 	now := time.Now()
-	return []*models.Flashcard{{ID: 1, Question: "q", Answer: "a", CreatedAt: now, UpdatedAt: now}}, nil
+	return []*models.Flashcard{{ID: 1, Question: "q", Answer: "a", CreatedAt: now, UpdatedAt: now}}, &models.FlashcardCursor{SortKey: "q", ID: 1}, nil
 }
 
 func (m *mockRepo) GetByID(id int) (*models.Flashcard, error) {
 	if id == 1 {
 		now := time.Now()
-		return &models.Flashcard{ID: 1, Question: "q", Answer: "a", CreatedAt: now, UpdatedAt: now}, nil
+		return &models.Flashcard{ID: 1, Question: "q", Answer: "a", Status: models.FlashcardStatusActive, CreatedAt: now, UpdatedAt: now}, nil
 	}
 	return nil, sql.ErrNoRows
 }
@@ -52,14 +96,152 @@ func (m *mockRepo) Delete(id int) error {
 	return nil
 }
 
-func (m *mockRepo) GetRandom() (*models.Flashcard, error) {
+func (m *mockRepo) GetRandom(_ *models.FlashcardFilter) (*models.Flashcard, error) {
 	now := time.Now()
 	return &models.Flashcard{ID: 1, Question: "hello", Answer: "γεια σασ", CreatedAt: now, UpdatedAt: now}, nil
 }
 
+func (m *mockRepo) GetAllByDeck(deckID int) ([]*models.Flashcard, error) {
+	if deckID != 1 {
+		return nil, fmt.Errorf("deck with id %d not found", deckID)
+	}
+	now := time.Now()
+	return []*models.Flashcard{{ID: 1, DeckID: &deckID, Question: "q", Answer: "a", CreatedAt: now, UpdatedAt: now}}, nil
+}
+
+func (m *mockRepo) GetRandomByDeck(deckID int) (*models.Flashcard, error) {
+	if deckID != 1 {
+		return nil, fmt.Errorf("deck with id %d not found", deckID)
+	}
+	now := time.Now()
+	return &models.Flashcard{ID: 1, DeckID: &deckID, Question: "hello", Answer: "γεια σας", CreatedAt: now, UpdatedAt: now}, nil
+}
+
+func (m *mockRepo) GetDueByDeck(deckID int, now time.Time, limit int) ([]*models.ScheduledFlashcard, error) {
+	if deckID != 1 {
+		return nil, fmt.Errorf("deck with id %d not found", deckID)
+	}
+	return m.GetDue(now, limit)
+}
+
+func (m *mockRepo) Search(_ string, lang string, _ int) ([]*models.SearchResult, error) {
+	m.searchLang = lang
+	return m.searchResults, nil
+}
+
+func (m *mockRepo) FuzzySearch(_ string, threshold float64, _ int) ([]*models.SearchResult, error) {
+	m.fuzzyThreshold = threshold
+	return nil, nil
+}
+
+func (m *mockRepo) GetSchedule(_ int) (*models.CardSchedule, error) {
+	return m.savedSchedule, nil
+}
+
+func (m *mockRepo) SaveReview(schedule *models.CardSchedule, log *models.ReviewLog) error {
+	if log.StateBefore != m.savedSchedule {
+		return db.ErrScheduleChanged
+	}
+	m.savedSchedule = schedule
+	log.ID = int64(len(m.reviewLogs) + 1)
+	m.reviewLogs = append(m.reviewLogs, log)
+	return nil
+}
+
+func (m *mockRepo) GetReviewLogs(_ int, beforeID int64, limit int) ([]*models.ReviewLog, error) {
+	var logs []*models.ReviewLog
+	for i := len(m.reviewLogs) - 1; i >= 0 && len(logs) < limit; i-- {
+		if beforeID <= 0 || m.reviewLogs[i].ID < beforeID {
+			logs = append(logs, m.reviewLogs[i])
+		}
+	}
+	return logs, nil
+}
+
+func (m *mockRepo) GetDue(_ time.Time, limit int) ([]*models.ScheduledFlashcard, error) {
+	now := time.Now()
+	due := []*models.ScheduledFlashcard{
+		{Flashcard: &models.Flashcard{ID: 1, Question: "q", Answer: "a", CreatedAt: now, UpdatedAt: now}},
+		{Flashcard: &models.Flashcard{ID: 2, Question: "q2", Answer: "a2", CreatedAt: now, UpdatedAt: now}},
+	}
+	if limit < len(due) {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (m *mockRepo) GetExamples(_ int) ([]*models.CardExample, error) {
+	return m.examples, nil
+}
+
+func (m *mockRepo) ReplaceExamples(flashcardID int, sentences []models.ExampleSentence) ([]*models.CardExample, error) {
+	m.examples = nil
+	for i, sentence := range sentences {
+		m.examples = append(m.examples, &models.CardExample{ID: i + 1, FlashcardID: flashcardID, ExampleSentence: sentence})
+	}
+	return m.examples, nil
+}
+
+func (m *mockRepo) GetPendingReview(limit int) ([]*models.Flashcard, error) {
+	m.pendingLimit = limit
+	now := time.Now()
+	return []*models.Flashcard{{ID: 1, Question: "q", Answer: "a", Status: models.FlashcardStatusPendingReview, CreatedAt: now, UpdatedAt: now}}, nil
+}
+
+func (m *mockRepo) Approve(id int, approvedBy string, approvedAt time.Time, edit *models.UpdateFlashcardRequest) (*models.Flashcard, error) {
+	if id != 1 {
+		return nil, fmt.Errorf("flashcard with id %d not found", id)
+	}
+	m.approvedBy, m.approvedAt, m.approvedEdit = approvedBy, approvedAt, edit
+	return &models.Flashcard{ID: id, Question: "q", Answer: "a", Status: models.FlashcardStatusActive, ApprovedBy: approvedBy, ApprovedAt: &approvedAt}, nil
+}
+
+func (m *mockRepo) Reject(id int, rejectedBy string, rejectedAt time.Time) (*models.Flashcard, error) {
+	if id != 1 {
+		return nil, fmt.Errorf("flashcard with id %d not found", id)
+	}
+	return &models.Flashcard{ID: id, Question: "q", Answer: "a", Status: models.FlashcardStatusRejected, RejectedBy: rejectedBy, RejectedAt: &rejectedAt}, nil
+}
+
+func (m *mockRepo) CreateTranslationPreview(preview *models.StoredTranslationPreview) error {
+	if m.previews == nil {
+		m.previews = map[string]*models.StoredTranslationPreview{}
+	}
+	preview.CreatedAt = time.Now()
+	m.previews[preview.ID] = preview
+	return nil
+}
+
+func (m *mockRepo) GetTranslationPreview(id string) (*models.StoredTranslationPreview, error) {
+	preview, ok := m.previews[id]
+	if !ok {
+		return nil, fmt.Errorf("translation preview with id %s not found", id)
+	}
+	return preview, nil
+}
+
+func (m *mockRepo) DeleteTranslationPreviewsCreatedBefore(_ time.Time) (int64, error) {
+	return 0, nil
+}
+
+// languageCardRepo is a mockRepo whose flashcards translate English questions into Greek answers.
+type languageCardRepo struct {
+	*mockRepo
+}
+
+func (r *languageCardRepo) GetByID(id int) (*models.Flashcard, error) {
+	flashcard, err := r.mockRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	flashcard.Question, flashcard.QuestionLang = "house", "en"
+	flashcard.Answer, flashcard.AnswerLang = "σπίτι", "el"
+	return flashcard, nil
+}
+
 func TestCreateFlashcardValidation(t *testing.T) {
 	mockLLM := &MockLLMClient{}
-	svc := NewFlashcardService(&mockRepo{}, mockLLM)
+	svc := NewFlashcardService(&mockRepo{}, mockLLM, nil, nil)
 
 	// Both fields present - no translation needed
 	fc, aiUsed, field, err := svc.CreateFlashcard(&models.CreateFlashcardRequest{
@@ -84,7 +266,7 @@ func TestCreateFlashcardValidation(t *testing.T) {
 
 func TestCreateFlashcardWithTranslation(t *testing.T) {
 	mockLLM := &MockLLMClient{}
-	svc := NewFlashcardService(&mockRepo{}, mockLLM)
+	svc := NewFlashcardService(&mockRepo{}, mockLLM, nil, nil)
 
 	// Only the question provided - should translate to answer
 	fc, aiUsed, field, err := svc.CreateFlashcard(&models.CreateFlashcardRequest{
@@ -127,8 +309,29 @@ func TestCreateFlashcardWithTranslation(t *testing.T) {
 	}
 }
 
+func TestCreateReversibleFlashcardAfterTranslation(t *testing.T) {
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, &MockLLMClient{}, nil, nil)
+
+	fc, _, _, err := svc.CreateFlashcard(&models.CreateFlashcardRequest{
+		Question:     "hello",
+		QuestionLang: "en",
+		AnswerLang:   "el",
+		Reversible:   true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !repo.created.Reversible || repo.created.Answer != "γεια σας" {
+		t.Fatalf("expected the translated card to be created reversible, got %+v", repo.created)
+	}
+	if fc.ReverseID == nil {
+		t.Fatalf("expected the flashcard to link to its reverse card")
+	}
+}
+
 func TestCreateFlashcardWithoutLLMClient(t *testing.T) {
-	svc := NewFlashcardService(&mockRepo{}, nil)
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
 
 	// Should fail when translation is needed but no LLM client
 	_, _, _, err := svc.CreateFlashcard(&models.CreateFlashcardRequest{
@@ -143,19 +346,126 @@ func TestCreateFlashcardWithoutLLMClient(t *testing.T) {
 }
 
 func TestUpdateFlashcardValidation(t *testing.T) {
-	svc := NewFlashcardService(&mockRepo{}, nil)
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
 
 	// Both fields nil
 	_, err := svc.UpdateFlashcard(1, &models.UpdateFlashcardRequest{})
 	if err == nil {
 		t.Fatalf("expected error when update request has no fields")
 	}
+
+	if _, err := svc.UpdateFlashcard(1, &models.UpdateFlashcardRequest{DeckID: intPtr(-1)}); err == nil {
+		t.Fatalf("expected error for a negative deck_id")
+	}
+	if _, err := svc.UpdateFlashcard(1, &models.UpdateFlashcardRequest{DeckID: intPtr(0)}); err != nil {
+		t.Fatalf("unexpected error removing a flashcard from its deck: %v", err)
+	}
+}
+
+func TestCreateFlashcardNormalizesTags(t *testing.T) {
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
+
+	fc, _, _, err := svc.CreateFlashcard(&models.CreateFlashcardRequest{
+		Question: "to eat",
+		Answer:   "τρώω",
+		Tags:     []string{" verb ", "A2", "verb"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fc.Tags) != 2 || fc.Tags[0] != "verb" || fc.Tags[1] != "A2" {
+		t.Fatalf("expected tags [verb A2], got %v", fc.Tags)
+	}
+
+	_, _, _, err = svc.CreateFlashcard(&models.CreateFlashcardRequest{Question: "q", Answer: "a", Tags: []string{"!food"}})
+	if !errors.Is(err, ErrInvalidTag) {
+		t.Fatalf("expected ErrInvalidTag, got %v", err)
+	}
+}
+
+func TestGetAllFlashcardsDefaultsAndCursorRoundTrip(t *testing.T) {
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, nil, nil, nil)
+
+	page, err := svc.GetAllFlashcards(&models.FlashcardListOptions{Sort: models.SortByQuestion, Limit: 1000}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.listOptions.Order != models.OrderAsc || repo.listOptions.Limit != maxFlashcardLimit || repo.listOptions.After != nil {
+		t.Fatalf("unexpected repository options: %+v", repo.listOptions)
+	}
+	if page.NextCursor == "" {
+		t.Fatalf("expected a next cursor")
+	}
+
+	if _, err := svc.GetAllFlashcards(&models.FlashcardListOptions{Sort: models.SortByQuestion}, page.NextCursor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if after := repo.listOptions.After; after == nil || after.SortKey != "q" || after.ID != 1 {
+		t.Fatalf("expected cursor to decode to the last position, got %+v", after)
+	}
+
+	// A cursor is only valid for the ordering it was issued for.
+	_, err = svc.GetAllFlashcards(&models.FlashcardListOptions{Sort: models.SortByQuestion, Order: models.OrderDesc}, page.NextCursor)
+	if !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor for a different order, got %v", err)
+	}
+}
+
+func TestGetAllFlashcardsInvalidOptions(t *testing.T) {
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
+
+	if _, err := svc.GetAllFlashcards(&models.FlashcardListOptions{Sort: "answer"}, ""); !errors.Is(err, ErrInvalidSort) {
+		t.Fatalf("expected ErrInvalidSort for unknown sort, got %v", err)
+	}
+	if _, err := svc.GetAllFlashcards(&models.FlashcardListOptions{Order: "up"}, ""); !errors.Is(err, ErrInvalidSort) {
+		t.Fatalf("expected ErrInvalidSort for unknown order, got %v", err)
+	}
+	if _, err := svc.GetAllFlashcards(nil, "not a cursor"); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestCreateFlashcardCanonicalizesLanguages(t *testing.T) {
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
+
+	fc, _, _, err := svc.CreateFlashcard(&models.CreateFlashcardRequest{
+		Question:     "colour",
+		Answer:       "χρώμα",
+		QuestionLang: "EN-gb",
+		AnswerLang:   "el",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fc.QuestionLang != "en-GB" || fc.AnswerLang != "el" {
+		t.Fatalf("expected languages en-GB and el, got %q and %q", fc.QuestionLang, fc.AnswerLang)
+	}
+
+	_, _, _, err = svc.CreateFlashcard(&models.CreateFlashcardRequest{Question: "q", Answer: "a", QuestionLang: "english_uk"})
+	if !errors.Is(err, ErrInvalidLanguageTag) {
+		t.Fatalf("expected ErrInvalidLanguageTag, got %v", err)
+	}
+}
+
+func TestUpdateFlashcardValidatesLanguage(t *testing.T) {
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
+
+	lang := "de_DE"
+	if _, err := svc.UpdateFlashcard(1, &models.UpdateFlashcardRequest{AnswerLang: &lang}); !errors.Is(err, ErrInvalidLanguageTag) {
+		t.Fatalf("expected ErrInvalidLanguageTag, got %v", err)
+	}
+
+	cleared := ""
+	if _, err := svc.UpdateFlashcard(1, &models.UpdateFlashcardRequest{AnswerLang: &cleared}); err != nil {
+		t.Fatalf("unexpected error clearing a language: %v", err)
+	}
 }
 
 func TestGetRandomFlashcardReturnsFlashcard(t *testing.T) {
-	svc := NewFlashcardService(&mockRepo{}, nil)
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
 
-	fc, err := svc.GetRandomFlashcard()
+	fc, err := svc.GetRandomFlashcard(nil)
 	if err != nil {
 		t.Fatalf("unexpected error getting random flashcard: %v", err)
 	}
@@ -169,7 +479,7 @@ func TestGetRandomFlashcardReturnsFlashcard(t *testing.T) {
 
 func TestGenerateAIHintWithoutLLMClientReturnsNil(t *testing.T) {
 	// Service without LLM client
-	svc := NewFlashcardService(&mockRepo{}, nil)
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
 	fc := &models.Flashcard{ID: 1, Question: "hello", Answer: "γεια σας"}
 
 	hint := svc.GenerateAIHint(fc, "el")
@@ -180,15 +490,548 @@ func TestGenerateAIHintWithoutLLMClientReturnsNil(t *testing.T) {
 
 func TestGenerateAIHintWithLLMClient(t *testing.T) {
 	// Service with mock LLM client
-	mockLLM := &MockLLMClient{}
-	svc := NewFlashcardService(&mockRepo{}, mockLLM)
-	fc := &models.Flashcard{ID: 1, Question: "hello", Answer: "γεια σας"}
+	var from, to string
+	mockLLM := &MockLLMClient{TranslateFunc: func(_ context.Context, text, sourceLang, targetLang string) (*models.Translation, error) {
+		from, to = sourceLang, targetLang
+		return &models.Translation{Translation: "hint"}, nil
+	}}
+	svc := NewFlashcardService(&mockRepo{}, mockLLM, nil, nil)
+	fc := &models.Flashcard{ID: 1, Question: "γεια σας", Answer: "hello", QuestionLang: "el", AnswerLang: "en"}
 
-	hint := svc.GenerateAIHint(fc, "el")
-	if hint == nil {
+	hint := svc.GenerateAIHint(fc, "")
+	if hint == nil || *hint == "" {
 		t.Fatalf("expected hint when LLM client is available")
 	}
-	if *hint == "" {
-		t.Fatalf("expected non-empty hint")
+	if from != "el" || to != "en" {
+		t.Fatalf("expected a hint from the question language into the answer language, got %s to %s", from, to)
+	}
+
+	if svc.GenerateAIHint(fc, "de"); to != "de" {
+		t.Fatalf("expected the requested hint language, got %s", to)
+	}
+
+	if hint := svc.GenerateAIHint(&models.Flashcard{ID: 2, Question: "hello", Answer: "γεια σας"}, "el"); hint != nil {
+		t.Fatalf("expected no hint for a card without languages, got %q", *hint)
+	}
+}
+
+func TestReviewFlashcardStoresSchedule(t *testing.T) {
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, nil, nil, nil)
+
+	scheduled, err := svc.ReviewFlashcard(1, &models.ReviewRequest{Grade: intPtr(4)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scheduled.Schedule == nil || scheduled.Schedule.Repetitions != 1 {
+		t.Fatalf("expected a schedule with one repetition, got %+v", scheduled.Schedule)
+	}
+	if repo.savedSchedule != scheduled.Schedule {
+		t.Fatalf("expected the new schedule to be saved")
+	}
+
+	scheduled, err = svc.ReviewFlashcard(1, &models.ReviewRequest{Grade: intPtr(4)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scheduled.Schedule.IntervalDays != 6 {
+		t.Fatalf("expected the second review to build on the saved schedule, got interval %d", scheduled.Schedule.IntervalDays)
+	}
+}
+
+// racingRepo is a mockRepo where another review of the card is saved while the first few reviews
+// through it are being scheduled.
+type racingRepo struct {
+	*mockRepo
+	races int
+}
+
+func (r *racingRepo) SaveReview(schedule *models.CardSchedule, log *models.ReviewLog) error {
+	if r.races > 0 {
+		r.races--
+		due := time.Date(2025, 11, 3, 10, 0, 0, 0, time.UTC)
+		r.savedSchedule = &models.CardSchedule{FlashcardID: 1, Scheduler: SM2SchedulerName, EaseFactor: 2.5, IntervalDays: 1, Repetitions: 1, DueAt: due, LastReviewedAt: &due}
+	}
+	return r.mockRepo.SaveReview(schedule, log)
+}
+
+func TestReviewFlashcardRetriesConcurrentReview(t *testing.T) {
+	repo := &racingRepo{mockRepo: &mockRepo{}, races: 1}
+	svc := NewFlashcardService(repo, nil, nil, nil)
+
+	scheduled, err := svc.ReviewFlashcard(1, &models.ReviewRequest{Grade: intPtr(4)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scheduled.Schedule.Repetitions != 2 || len(repo.reviewLogs) != 1 || repo.reviewLogs[0].StateBefore.Repetitions != 1 {
+		t.Fatalf("expected the review to build on the concurrent one, got %+v", scheduled.Schedule)
+	}
+
+	repo.races = maxReviewAttempts
+	if _, err := svc.ReviewFlashcard(1, &models.ReviewRequest{Grade: intPtr(4)}); !errors.Is(err, db.ErrScheduleChanged) {
+		t.Fatalf("expected ErrScheduleChanged after %d attempts, got %v", maxReviewAttempts, err)
+	}
+}
+
+func TestReviewFlashcardRecordsLog(t *testing.T) {
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, nil, nil, nil)
+	now := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+	svc.clock = fakeClock{now: now}
+
+	duration := 3200
+	if _, err := svc.ReviewFlashcard(1, &models.ReviewRequest{Grade: intPtr(4), DurationMS: &duration}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc.clock = fakeClock{now: now.AddDate(0, 0, 2)}
+	if _, err := svc.ReviewFlashcard(1, &models.ReviewRequest{Grade: intPtr(2)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.reviewLogs) != 2 {
+		t.Fatalf("expected 2 review logs, got %d", len(repo.reviewLogs))
+	}
+	first, second := repo.reviewLogs[0], repo.reviewLogs[1]
+	if first.StateBefore != nil || first.ElapsedDays != nil || *first.DurationMS != duration {
+		t.Fatalf("unexpected first log: %+v", first)
+	}
+	if second.StateBefore != first.StateAfter {
+		t.Fatalf("expected the second log to start from the first log's state")
+	}
+	if second.ElapsedDays == nil || *second.ElapsedDays != 2 {
+		t.Fatalf("expected 2 elapsed days, got %v", second.ElapsedDays)
+	}
+}
+
+func TestAnswerFlashcardGradesAndReviews(t *testing.T) {
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, nil, nil, nil)
+
+	result, err := svc.AnswerFlashcard(1, &models.AnswerRequest{Answer: " A! "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Verdict != models.VerdictCorrect || result.Expected != "a" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if len(repo.reviewLogs) != 1 || repo.reviewLogs[0].Grade != correctAnswerGrade {
+		t.Fatalf("expected a review graded %d, got %+v", correctAnswerGrade, repo.reviewLogs)
+	}
+	if result.Schedule == nil || repo.savedSchedule != result.Schedule {
+		t.Fatalf("expected the new schedule to be saved and returned")
+	}
+
+	if _, err := svc.AnswerFlashcard(1, &models.AnswerRequest{Answer: "  "}); !errors.Is(err, ErrEmptyAnswer) {
+		t.Fatalf("expected ErrEmptyAnswer, got %v", err)
+	}
+	if _, err := svc.AnswerFlashcard(2, &models.AnswerRequest{Answer: "a"}); err == nil {
+		t.Fatal("expected an error for a missing flashcard")
+	}
+}
+
+func TestGetReviewLogsPaging(t *testing.T) {
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, nil, nil, nil)
+	for i := 0; i < 3; i++ {
+		if _, err := svc.ReviewFlashcard(1, &models.ReviewRequest{Grade: intPtr(4)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	page, err := svc.GetReviewLogs(1, 2, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Reviews) != 2 || page.Reviews[0].ID != 3 || page.NextCursor == "" {
+		t.Fatalf("unexpected first page: %d reviews, cursor %q", len(page.Reviews), page.NextCursor)
+	}
+
+	page, err = svc.GetReviewLogs(1, 2, page.NextCursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Reviews) != 1 || page.Reviews[0].ID != 1 || page.NextCursor != "" {
+		t.Fatalf("unexpected last page: %d reviews, cursor %q", len(page.Reviews), page.NextCursor)
+	}
+
+	if _, err := svc.GetReviewLogs(1, 2, "not-a-cursor"); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestReviewFlashcardInvalidGrade(t *testing.T) {
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
+
+	if _, err := svc.ReviewFlashcard(1, &models.ReviewRequest{Grade: intPtr(7)}); err == nil {
+		t.Fatalf("expected error for out-of-range grade")
+	}
+}
+
+func TestGetDueFlashcardsAppliesLimit(t *testing.T) {
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
+
+	due, err := svc.GetDueFlashcards(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(due) != 1 {
+		t.Fatalf("expected 1 due flashcard, got %d", len(due))
+	}
+
+	due, err = svc.GetDueFlashcards(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(due) != 2 {
+		t.Fatalf("expected the default limit to return all due flashcards, got %d", len(due))
+	}
+}
+
+func TestReviewFlashcardWithFSRSUsesClock(t *testing.T) {
+	scheduler, err := NewFSRSScheduler(nil, DefaultDesiredRetention)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, nil, scheduler, nil)
+	now := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+	svc.clock = fakeClock{now: now}
+
+	scheduled, err := svc.ReviewFlashcard(1, &models.ReviewRequest{Grade: intPtr(4)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scheduled.Schedule.Scheduler != FSRSSchedulerName {
+		t.Fatalf("expected schedule from %q, got %q", FSRSSchedulerName, scheduled.Schedule.Scheduler)
+	}
+	if !scheduled.Schedule.LastReviewedAt.Equal(now) {
+		t.Fatalf("expected review time %v, got %v", now, scheduled.Schedule.LastReviewedAt)
+	}
+	if !scheduled.Schedule.DueAt.Equal(now.AddDate(0, 0, scheduled.Schedule.IntervalDays)) {
+		t.Fatalf("expected due date to follow the interval from the fake clock, got %v", scheduled.Schedule.DueAt)
+	}
+}
+
+func TestReviewFlashcardUsesStoredWeights(t *testing.T) {
+	fitted := append([]float64(nil), DefaultFSRSWeights...)
+	fitted[2] = 7
+	scheduler, err := NewScheduler(FSRSSchedulerName, &mockFSRSParameterRepo{params: map[string]*models.FSRSParameters{
+		models.DefaultUserID: {UserID: models.DefaultUserID, Weights: fitted},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, nil, scheduler, nil)
+	scheduled, err := svc.ReviewFlashcard(1, &models.ReviewRequest{Grade: intPtr(4)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scheduled.Schedule.Stability != 7 {
+		t.Fatalf("expected initial stability 7 from the stored weights, got %v", scheduled.Schedule.Stability)
+	}
+	if log := repo.reviewLogs[0]; log.UserID != models.DefaultUserID || log.Scheduler != FSRSSchedulerName {
+		t.Fatalf("expected the review to be logged for the default user with FSRS, got %+v", log)
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestGetDueFlashcardsByDeckAppliesLimit(t *testing.T) {
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
+
+	due, err := svc.GetDueFlashcardsByDeck(1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(due) != 1 {
+		t.Fatalf("expected 1 due flashcard, got %d", len(due))
+	}
+
+	if _, err := svc.GetDueFlashcardsByDeck(2, 1); err == nil {
+		t.Fatalf("expected error for unknown deck")
+	}
+}
+
+func TestGenerateExamplesStoresAndReusesSentences(t *testing.T) {
+	calls := 0
+	mockLLM := &MockLLMClient{
+		GenerateExamplesFunc: func(_ context.Context, word, lang, translationLang string, n int) ([]models.ExampleSentence, error) {
+			calls++
+			if word != "σπίτι" || lang != "el" || translationLang != "en" || n != 2 {
+				t.Fatalf("unexpected request: %q %q %q %d", word, lang, translationLang, n)
+			}
+			return []models.ExampleSentence{
+				{Sentence: "Το σπίτι είναι μεγάλο.", Translation: "The house is big.", Level: "A1"},
+				{Sentence: "Μετακομίσαμε σε καινούργιο σπίτι.", Translation: "We moved to a new house.", Level: "A2"},
+			}, nil
+		},
+	}
+	svc := NewFlashcardService(&languageCardRepo{mockRepo: &mockRepo{}}, mockLLM, nil, nil)
+
+	generated, err := svc.GenerateExamples(1, &models.GenerateExamplesRequest{N: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !generated.Generated || len(generated.Examples) != 2 || generated.Examples[0].FlashcardID != 1 {
+		t.Fatalf("expected 2 generated examples of card 1, got %+v", generated)
+	}
+
+	stored, err := svc.GenerateExamples(1, &models.GenerateExamplesRequest{N: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.Generated || len(stored.Examples) != 2 || calls != 1 {
+		t.Fatalf("expected the stored examples without another generation, got %+v after %d calls", stored, calls)
+	}
+
+	if _, err := svc.GenerateExamples(1, &models.GenerateExamplesRequest{N: 2, Regenerate: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected regenerate to generate again, got %d calls", calls)
+	}
+}
+
+func TestGenerateExamplesValidation(t *testing.T) {
+	withLanguages := NewFlashcardService(&languageCardRepo{mockRepo: &mockRepo{}}, &MockLLMClient{}, nil, nil)
+	if _, err := withLanguages.GenerateExamples(1, &models.GenerateExamplesRequest{N: maxExampleCount + 1}); !errors.Is(err, ErrInvalidExampleCount) {
+		t.Fatalf("expected ErrInvalidExampleCount, got %v", err)
+	}
+	if _, err := withLanguages.GenerateExamples(2, &models.GenerateExamplesRequest{}); err == nil {
+		t.Fatalf("expected error for a missing flashcard")
+	}
+
+	withoutLanguages := NewFlashcardService(&mockRepo{}, &MockLLMClient{}, nil, nil)
+	if _, err := withoutLanguages.GenerateExamples(1, &models.GenerateExamplesRequest{}); !errors.Is(err, ErrExampleLanguages) {
+		t.Fatalf("expected ErrExampleLanguages, got %v", err)
+	}
+
+	withoutLLM := NewFlashcardService(&languageCardRepo{mockRepo: &mockRepo{}}, nil, nil, nil)
+	if _, err := withoutLLM.GenerateExamples(1, &models.GenerateExamplesRequest{}); !errors.Is(err, ErrAIUnavailable) {
+		t.Fatalf("expected ErrAIUnavailable, got %v", err)
+	}
+}
+
+func TestCreateFlashcardEnrichesGrammar(t *testing.T) {
+	var asked string
+	mockLLM := &MockLLMClient{
+		ExtractGrammarFunc: func(_ context.Context, word, lang string) (*models.Grammar, error) {
+			asked = word + "/" + lang
+			return testNounGrammar(), nil
+		},
+	}
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, mockLLM, nil, nil)
+
+	fc, _, _, err := svc.CreateFlashcard(&models.CreateFlashcardRequest{
+		Question:      "street",
+		Answer:        "δρόμος",
+		QuestionLang:  "en",
+		AnswerLang:    "el-GR",
+		EnrichGrammar: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if asked != "δρόμος/el-GR" {
+		t.Fatalf("expected grammar of the Greek answer, got %q", asked)
+	}
+	if fc == nil || repo.created.Grammar == nil || repo.created.Grammar.Noun.Article != "ο" {
+		t.Fatalf("expected grammar to be stored with the card, got %+v", repo.created)
+	}
+}
+
+func TestCreateFlashcardEnrichGrammarErrors(t *testing.T) {
+	invalid := &MockLLMClient{
+		ExtractGrammarFunc: func(context.Context, string, string) (*models.Grammar, error) {
+			g := testNounGrammar()
+			g.Noun.Article = "το"
+			return g, nil
+		},
+	}
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, invalid, nil, nil)
+
+	// Enrichment is optional, so the card is stored without the invalid grammar
+	req := &models.CreateFlashcardRequest{Question: "street", Answer: "δρόμος", QuestionLang: "en", AnswerLang: "el", EnrichGrammar: true}
+	if _, _, _, err := svc.CreateFlashcard(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.created == nil || repo.created.Grammar != nil || len(req.Warnings) != 1 {
+		t.Fatalf("expected the card to be stored without grammar and with a warning, got %+v", repo.created)
+	}
+	if repo.created.Status != models.FlashcardStatusActive {
+		t.Fatalf("expected a card without generated fields to be active, got %q", repo.created.Status)
+	}
+
+	failing := &MockLLMClient{
+		ExtractGrammarFunc: func(context.Context, string, string) (*models.Grammar, error) {
+			return nil, errors.New("upstream timeout")
+		},
+	}
+	for _, client := range []LLMClient{failing, nil} {
+		repo = &mockRepo{}
+		svc = NewFlashcardService(repo, client, nil, nil)
+		req = &models.CreateFlashcardRequest{Question: "street", Answer: "δρόμος", QuestionLang: "en", AnswerLang: "el", EnrichGrammar: true}
+		if _, _, _, err := svc.CreateFlashcard(req); err != nil || repo.created == nil || len(req.Warnings) != 1 {
+			t.Fatalf("expected the card to be stored with a warning, got %v, %v", err, req.Warnings)
+		}
+	}
+
+	req = &models.CreateFlashcardRequest{Question: "street", Answer: "calle", QuestionLang: "en", AnswerLang: "es", EnrichGrammar: true}
+	if _, _, _, err := svc.CreateFlashcard(req); !errors.Is(err, ErrGrammarLanguage) {
+		t.Fatalf("expected ErrGrammarLanguage, got %v", err)
+	}
+}
+
+func TestPreviewTranslationListsCandidates(t *testing.T) {
+	mockLLM := &MockLLMClient{
+		TranslateCandidatesFunc: func(_ context.Context, text, sourceLang, targetLang string, n int) ([]models.TranslationCandidate, error) {
+			if text != "book" || sourceLang != "en" || targetLang != "el" || n != 2 {
+				t.Fatalf("unexpected request: %q %q %q %d", text, sourceLang, targetLang, n)
+			}
+			return []models.TranslationCandidate{
+				{Translation: "βιβλίο", PartOfSpeech: "noun", Usage: "a book to read"},
+				{Translation: "κλείνω", PartOfSpeech: "verb", Usage: "to book a table or room"},
+			}, nil
+		},
+	}
+	svc := NewFlashcardService(&mockRepo{}, mockLLM, nil, nil)
+
+	preview, err := svc.PreviewTranslation(&models.TranslatePreviewRequest{Question: "book", QuestionLang: "EN", AnswerLang: "el", N: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if preview.Source != "book" || preview.TranslatedField != "answer" || len(preview.Candidates) != 2 {
+		t.Fatalf("unexpected preview: %+v", preview)
+	}
+}
+
+func TestPreviewTranslationValidation(t *testing.T) {
+	svc := NewFlashcardService(&mockRepo{}, &MockLLMClient{}, nil, nil)
+
+	if _, err := svc.PreviewTranslation(&models.TranslatePreviewRequest{Question: "book", Answer: "βιβλίο", QuestionLang: "en", AnswerLang: "el"}); !errors.Is(err, ErrTranslationSide) {
+		t.Fatalf("expected ErrTranslationSide, got %v", err)
+	}
+	if _, err := svc.PreviewTranslation(&models.TranslatePreviewRequest{Question: "book", QuestionLang: "en", AnswerLang: "el", N: maxTranslationCandidates + 1}); !errors.Is(err, ErrInvalidCandidateCount) {
+		t.Fatalf("expected ErrInvalidCandidateCount, got %v", err)
+	}
+
+	withoutLLM := NewFlashcardService(&mockRepo{}, nil, nil, nil)
+	if _, err := withoutLLM.PreviewTranslation(&models.TranslatePreviewRequest{Question: "book", QuestionLang: "en", AnswerLang: "el"}); !errors.Is(err, ErrAIUnavailable) {
+		t.Fatalf("expected ErrAIUnavailable, got %v", err)
+	}
+}
+
+func TestCreateFlashcardWithSelectedTranslation(t *testing.T) {
+	now := time.Now()
+	mockLLM := &MockLLMClient{
+		ModelName: "gpt-test",
+		TranslateCandidatesFunc: func(context.Context, string, string, string, int) ([]models.TranslationCandidate, error) {
+			return []models.TranslationCandidate{{Translation: "βιβλίο"}, {Translation: "κλείνω"}}, nil
+		},
+	}
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, mockLLM, nil, nil)
+	svc.clock = fakeClock{now: now}
+
+	preview, err := svc.PreviewTranslation(&models.TranslatePreviewRequest{Question: "book", QuestionLang: "en", AnswerLang: "el"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if preview.ID == "" || repo.previews[preview.ID].Model != "gpt-test" {
+		t.Fatalf("expected the preview to be stored with its model, got %+v", preview)
+	}
+
+	// The candidate is taken from the stored preview, so the card is still created when no model is
+	// configured anymore
+	withoutLLM := NewFlashcardService(repo, nil, nil, nil)
+	withoutLLM.clock = fakeClock{now: now}
+	fc, aiUsed, field, err := withoutLLM.CreateFlashcard(&models.CreateFlashcardRequest{
+		Question:     "book",
+		QuestionLang: "en",
+		AnswerLang:   "el",
+		PreviewID:    preview.ID,
+		Candidate:    1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fc.Answer != "κλείνω" || !aiUsed || field != "answer" {
+		t.Fatalf("expected the chosen candidate as answer, got %q (aiUsed=%v, field=%q)", fc.Answer, aiUsed, field)
+	}
+	provenance := repo.created.AIProvenance
+	if provenance == nil || !reflect.DeepEqual(provenance.Fields, []string{"answer"}) || provenance.Model != "gpt-test" ||
+		provenance.PromptVersion != promptVersion || !provenance.GeneratedAt.Equal(now) {
+		t.Fatalf("expected the answer to be recorded as generated by the preview's model, got %+v", provenance)
+	}
+	if repo.created.Status != models.FlashcardStatusPendingReview {
+		t.Fatalf("expected the card to wait for review, got %q", repo.created.Status)
+	}
+
+	for _, req := range []*models.CreateFlashcardRequest{
+		{Question: "book", Answer: "βιβλίο", QuestionLang: "en", AnswerLang: "el", PreviewID: preview.ID},
+		{Question: "table", QuestionLang: "en", AnswerLang: "el", PreviewID: preview.ID},
+		{Question: "book", QuestionLang: "en", AnswerLang: "es", PreviewID: preview.ID},
+		{Question: "book", QuestionLang: "en", AnswerLang: "el", PreviewID: preview.ID, Candidate: 2},
+	} {
+		if _, _, _, err := svc.CreateFlashcard(req); !errors.Is(err, ErrSelectedTranslation) {
+			t.Fatalf("expected ErrSelectedTranslation for %+v, got %v", req, err)
+		}
+	}
+
+	if _, _, _, err := svc.CreateFlashcard(&models.CreateFlashcardRequest{Question: "book", QuestionLang: "en", AnswerLang: "el", PreviewID: "unknown"}); err == nil {
+		t.Fatal("expected an error for an unknown preview")
+	}
+
+	svc.clock = fakeClock{now: now.Add(TranslationPreviewTTL + time.Minute)}
+	if _, _, _, err := svc.CreateFlashcard(&models.CreateFlashcardRequest{Question: "book", QuestionLang: "en", AnswerLang: "el", PreviewID: preview.ID}); !errors.Is(err, ErrTranslationPreviewExpired) {
+		t.Fatalf("expected ErrTranslationPreviewExpired, got %v", err)
+	}
+}
+
+func TestCreateFlashcardRecordsProvenance(t *testing.T) {
+	now := time.Date(2025, 11, 19, 10, 0, 0, 0, time.UTC)
+	mockLLM := &MockLLMClient{
+		ModelName: "gpt-test",
+		ExtractGrammarFunc: func(context.Context, string, string) (*models.Grammar, error) {
+			return testNounGrammar(), nil
+		},
+	}
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, mockLLM, nil, nil)
+	svc.clock = fakeClock{now: now}
+
+	if _, _, _, err := svc.CreateFlashcard(&models.CreateFlashcardRequest{
+		Question:      "street",
+		QuestionLang:  "en",
+		AnswerLang:    "el",
+		EnrichGrammar: true,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	provenance := repo.created.AIProvenance
+	if provenance == nil || len(provenance.Fields) != 2 || provenance.Fields[0] != models.AIFieldAnswer || provenance.Fields[1] != models.AIFieldGrammar {
+		t.Fatalf("expected the answer and grammar to be machine-generated, got %+v", provenance)
+	}
+	if provenance.Model != "gpt-test" || provenance.PromptVersion != promptVersion || !provenance.GeneratedAt.Equal(now) {
+		t.Fatalf("unexpected provenance: %+v", provenance)
+	}
+	if repo.created.Status != models.FlashcardStatusPendingReview {
+		t.Fatalf("expected a machine-translated card to wait for review, got status %q", repo.created.Status)
+	}
+
+	if _, _, _, err := svc.CreateFlashcard(&models.CreateFlashcardRequest{Question: "street", Answer: "δρόμος"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.created.AIProvenance != nil {
+		t.Fatalf("expected no provenance for a card written by hand, got %+v", repo.created.AIProvenance)
+	}
+	if repo.created.Status != models.FlashcardStatusActive {
+		t.Fatalf("expected a card written by hand to be active, got status %q", repo.created.Status)
 	}
 }
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// MinOptimizerReviews is the number of predictable reviews (reviews that follow an earlier review of
+// the same card on a previous day) needed before weights are fitted.
+const MinOptimizerReviews = 50
+
+// ErrNotEnoughReviews is returned when the review history is too short to fit weights from.
+var ErrNotEnoughReviews = errors.New("not enough reviews to optimise FSRS weights")
+
+// fsrsWeightBounds keeps every weight within the range the FSRS model is defined for.
+var fsrsWeightBounds = [fsrsWeightCount][2]float64{
+	{0.1, 100}, {0.1, 100}, {0.1, 100}, {0.1, 100},
+	{1, 10}, {0.1, 5}, {0.1, 5}, {0, 0.5},
+	{0, 3}, {0.1, 0.8}, {0.01, 2.5}, {0.5, 5},
+	{0.01, 0.2}, {0.01, 0.9}, {0.01, 2}, {0, 1},
+	{1, 6},
+}
+
+// FSRSOptimizerOptions controls the gradient descent.
+type FSRSOptimizerOptions struct {
+	Iterations   int
+	LearningRate float64
+}
+
+// DefaultFSRSOptimizerOptions work well for review histories of a few hundred to a few hundred
+// thousand reviews.
+var DefaultFSRSOptimizerOptions = FSRSOptimizerOptions{
+	Iterations:   300,
+	LearningRate: 0.02,
+}
+
+// FSRSOptimizationResult holds the fitted weights and how well they predict the review history.
+type FSRSOptimizationResult struct {
+	Weights        []float64
+	InitialLogLoss float64
+	LogLoss        float64
+	ReviewCount    int
+}
+
+// fsrsReview is one review of a card in the form the model trains on.
+type fsrsReview struct {
+	rating      int
+	elapsedDays float64
+}
+
+// OptimizeFSRSWeights fits FSRS weights to a review history by minimising the log loss of the
+// predicted recall probabilities, starting from initial (DefaultFSRSWeights if nil). logs must be
+// grouped by flashcard and ordered by review time within each card, as returned by
+// db.FSRSParameterRepository.GetReviewHistory.
+func OptimizeFSRSWeights(logs []*models.ReviewLog, initial []float64, opts FSRSOptimizerOptions) (*FSRSOptimizationResult, error) {
+	if initial == nil {
+		initial = DefaultFSRSWeights
+	}
+	if len(initial) != fsrsWeightCount {
+		return nil, fmt.Errorf("FSRS requires %d weights, got %d", fsrsWeightCount, len(initial))
+	}
+	if opts.Iterations <= 0 || opts.LearningRate <= 0 {
+		return nil, errors.New("iterations and learning rate must be positive")
+	}
+
+	histories := fsrsHistories(logs)
+	reviewCount := countPredictableReviews(histories)
+	if reviewCount < MinOptimizerReviews {
+		return nil, fmt.Errorf("%w: have %d, need %d", ErrNotEnoughReviews, reviewCount, MinOptimizerReviews)
+	}
+
+	weights := clampWeights(append(fsrsWeights(nil), initial...))
+	initialLoss := fsrsLogLoss(weights, histories)
+
+	// Adam keeps the step size sensible for weights that live on very different scales.
+	const (
+		beta1   = 0.9
+		beta2   = 0.999
+		epsilon = 1e-8
+	)
+	m := make([]float64, fsrsWeightCount)
+	v := make([]float64, fsrsWeightCount)
+	best := append(fsrsWeights(nil), weights...)
+	bestLoss := initialLoss
+
+	for t := 1; t <= opts.Iterations; t++ {
+		grad := fsrsGradient(weights, histories)
+		for i := range weights {
+			m[i] = beta1*m[i] + (1-beta1)*grad[i]
+			v[i] = beta2*v[i] + (1-beta2)*grad[i]*grad[i]
+			mHat := m[i] / (1 - math.Pow(beta1, float64(t)))
+			vHat := v[i] / (1 - math.Pow(beta2, float64(t)))
+			weights[i] -= opts.LearningRate * mHat / (math.Sqrt(vHat) + epsilon)
+		}
+		weights = clampWeights(weights)
+
+		if loss := fsrsLogLoss(weights, histories); loss < bestLoss {
+			bestLoss = loss
+			copy(best, weights)
+		}
+	}
+
+	return &FSRSOptimizationResult{
+		Weights:        best,
+		InitialLogLoss: initialLoss,
+		LogLoss:        bestLoss,
+		ReviewCount:    reviewCount,
+	}, nil
+}
+
+// fsrsHistories splits review logs into per-card sequences with the days elapsed between reviews.
+func fsrsHistories(logs []*models.ReviewLog) [][]fsrsReview {
+	var histories [][]fsrsReview
+	var current []fsrsReview
+	for i, log := range logs {
+		if i == 0 || log.FlashcardID != logs[i-1].FlashcardID {
+			if len(current) > 0 {
+				histories = append(histories, current)
+			}
+			current = nil
+		}
+
+		review := fsrsReview{rating: fsrsRating(log.Grade)}
+		if len(current) > 0 {
+			review.elapsedDays = math.Max(0, log.ReviewedAt.Sub(logs[i-1].ReviewedAt).Hours()/24)
+		}
+		current = append(current, review)
+	}
+	if len(current) > 0 {
+		histories = append(histories, current)
+	}
+	return histories
+}
+
+// isPredictable reports whether the model's recall prediction is evaluated for a review. Same-day
+// reviews measure short-term memory, which FSRS does not model.
+func (r fsrsReview) isPredictable(index int) bool {
+	return index > 0 && r.elapsedDays >= 1
+}
+
+func countPredictableReviews(histories [][]fsrsReview) int {
+	count := 0
+	for _, history := range histories {
+		for i, review := range history {
+			if review.isPredictable(i) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// fsrsLogLoss replays every history with the given weights and returns the mean binary cross-entropy
+// between the predicted recall probability and whether the card was actually recalled.
+func fsrsLogLoss(w fsrsWeights, histories [][]fsrsReview) float64 {
+	const epsilon = 1e-7
+	total := 0.0
+	count := 0
+
+	for _, history := range histories {
+		stability := w.initialStability(history[0].rating)
+		difficulty := w.initialDifficulty(history[0].rating)
+
+		for i := 1; i < len(history); i++ {
+			review := history[i]
+			retrievability := FSRSRetrievability(review.elapsedDays, stability)
+
+			if review.isPredictable(i) {
+				p := math.Min(1-epsilon, math.Max(epsilon, retrievability))
+				if review.rating == fsrsAgain {
+					total -= math.Log(1 - p)
+				} else {
+					total -= math.Log(p)
+				}
+				count++
+			}
+
+			if review.rating == fsrsAgain {
+				stability = w.forgetStability(difficulty, stability, retrievability)
+			} else {
+				stability = w.recallStability(difficulty, stability, retrievability, review.rating)
+			}
+			difficulty = w.nextDifficulty(difficulty, review.rating)
+		}
+	}
+
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+// fsrsGradient estimates the gradient of the log loss with central finite differences.
+func fsrsGradient(w fsrsWeights, histories [][]fsrsReview) []float64 {
+	const h = 1e-4
+	grad := make([]float64, len(w))
+	probe := append(fsrsWeights(nil), w...)
+
+	for i := range w {
+		probe[i] = w[i] + h
+		up := fsrsLogLoss(probe, histories)
+		probe[i] = w[i] - h
+		down := fsrsLogLoss(probe, histories)
+		probe[i] = w[i]
+
+		grad[i] = (up - down) / (2 * h)
+	}
+	return grad
+}
+
+func clampWeights(w fsrsWeights) fsrsWeights {
+	for i := range w {
+		w[i] = math.Min(fsrsWeightBounds[i][1], math.Max(fsrsWeightBounds[i][0], w[i]))
+	}
+	return w
+}
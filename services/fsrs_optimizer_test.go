@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// simulateReviews produces a review history for a learner whose memory follows the given weights.
+func simulateReviews(weights fsrsWeights, cards int, seed int64) []*models.ReviewLog {
+	rng := rand.New(rand.NewSource(seed))
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	gaps := []int{1, 3, 7, 14, 30, 60}
+
+	var logs []*models.ReviewLog
+	for card := 1; card <= cards; card++ {
+		reviewedAt := start
+		rating := fsrsGood
+		stability := weights.initialStability(rating)
+		difficulty := weights.initialDifficulty(rating)
+		logs = append(logs, &models.ReviewLog{FlashcardID: card, Grade: 4, ReviewedAt: reviewedAt})
+
+		for _, gap := range gaps {
+			reviewedAt = reviewedAt.AddDate(0, 0, gap)
+			retrievability := FSRSRetrievability(float64(gap), stability)
+
+			grade := 4
+			rating = fsrsGood
+			if rng.Float64() > retrievability {
+				grade = 1
+				rating = fsrsAgain
+				stability = weights.forgetStability(difficulty, stability, retrievability)
+			} else {
+				stability = weights.recallStability(difficulty, stability, retrievability, rating)
+			}
+			difficulty = weights.nextDifficulty(difficulty, rating)
+			logs = append(logs, &models.ReviewLog{FlashcardID: card, Grade: grade, ReviewedAt: reviewedAt})
+		}
+	}
+	return logs
+}
+
+func TestOptimizeFSRSWeightsImprovesFit(t *testing.T) {
+	// A learner who forgets new cards much faster than the defaults assume.
+	forgetful := append(fsrsWeights(nil), DefaultFSRSWeights...)
+	forgetful[2] = 0.8
+	forgetful[8] = 1.0
+	logs := simulateReviews(forgetful, 150, 42)
+
+	result, err := OptimizeFSRSWeights(logs, nil, FSRSOptimizerOptions{Iterations: 60, LearningRate: 0.05})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ReviewCount != 150*6 {
+		t.Fatalf("expected %d predictable reviews, got %d", 150*6, result.ReviewCount)
+	}
+	if result.LogLoss >= result.InitialLogLoss {
+		t.Fatalf("expected log loss to improve on the defaults, got %v -> %v", result.InitialLogLoss, result.LogLoss)
+	}
+	if result.Weights[2] >= DefaultFSRSWeights[2] {
+		t.Fatalf("expected the initial good stability to shrink towards the learner's, got %v", result.Weights[2])
+	}
+	if _, err := NewFSRSScheduler(result.Weights, DefaultDesiredRetention); err != nil {
+		t.Fatalf("expected fitted weights to be usable by the scheduler: %v", err)
+	}
+}
+
+func TestOptimizeFSRSWeightsNeedsEnoughReviews(t *testing.T) {
+	logs := simulateReviews(DefaultFSRSWeights, 2, 1)
+
+	_, err := OptimizeFSRSWeights(logs, nil, DefaultFSRSOptimizerOptions)
+	if !errors.Is(err, ErrNotEnoughReviews) {
+		t.Fatalf("expected ErrNotEnoughReviews, got %v", err)
+	}
+}
+
+func TestFSRSHistoriesSkipsSameDayPredictions(t *testing.T) {
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	logs := []*models.ReviewLog{
+		{FlashcardID: 1, Grade: 1, ReviewedAt: now},
+		{FlashcardID: 1, Grade: 4, ReviewedAt: now.Add(10 * time.Minute)},
+		{FlashcardID: 1, Grade: 4, ReviewedAt: now.AddDate(0, 0, 2)},
+		{FlashcardID: 2, Grade: 4, ReviewedAt: now},
+	}
+
+	histories := fsrsHistories(logs)
+	if len(histories) != 2 || len(histories[0]) != 3 || len(histories[1]) != 1 {
+		t.Fatalf("unexpected histories: %+v", histories)
+	}
+	if count := countPredictableReviews(histories); count != 1 {
+		t.Fatalf("expected 1 predictable review, got %d", count)
+	}
+}
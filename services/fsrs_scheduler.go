@@ -0,0 +1,194 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// FSRSSchedulerName identifies schedules produced by the FSRS scheduler.
+const FSRSSchedulerName = "fsrs"
+
+const (
+	// DefaultDesiredRetention is the recall probability at which FSRS schedules the next review.
+	DefaultDesiredRetention = 0.9
+
+	fsrsDecay           = -0.5
+	fsrsFactor          = 19.0 / 81.0
+	fsrsMinDifficulty   = 1.0
+	fsrsMaxDifficulty   = 10.0
+	fsrsMinStability    = 0.01
+	fsrsMaxIntervalDays = 36500
+	fsrsWeightCount     = 17
+)
+
+// FSRS ratings. Reviews are graded on the 0-5 SM-2 scale and mapped onto these with fsrsRating.
+const (
+	fsrsAgain = 1
+	fsrsHard  = 2
+	fsrsGood  = 3
+	fsrsEasy  = 4
+)
+
+// DefaultFSRSWeights are the FSRS-4.5 parameters fitted on a large public review dataset. They are used
+// until weights have been optimised on the learner's own review history.
+var DefaultFSRSWeights = []float64{
+	0.4872, 1.4003, 3.7145, 13.8206,
+	5.1618, 1.2298, 0.8975, 0.031,
+	1.6474, 0.1367, 1.0461, 2.1072,
+	0.0793, 0.3246, 1.587, 0.2272,
+	2.8755,
+}
+
+// FSRSScheduler implements the Free Spaced Repetition Scheduler (FSRS-4.5), which models each card's
+// memory by its stability (days until recall probability drops to 90%) and difficulty (1-10).
+type FSRSScheduler struct {
+	weights          fsrsWeights
+	desiredRetention float64
+}
+
+// NewFSRSScheduler creates an FSRS scheduler. nil weights select DefaultFSRSWeights.
+func NewFSRSScheduler(weights []float64, desiredRetention float64) (*FSRSScheduler, error) {
+	if weights == nil {
+		weights = DefaultFSRSWeights
+	}
+	if len(weights) != fsrsWeightCount {
+		return nil, fmt.Errorf("FSRS requires %d weights, got %d", fsrsWeightCount, len(weights))
+	}
+	if desiredRetention <= 0 || desiredRetention >= 1 {
+		return nil, fmt.Errorf("desired retention must be between 0 and 1, got %v", desiredRetention)
+	}
+
+	return &FSRSScheduler{
+		weights:          append(fsrsWeights(nil), weights...),
+		desiredRetention: desiredRetention,
+	}, nil
+}
+
+func (s *FSRSScheduler) Name() string {
+	return FSRSSchedulerName
+}
+
+// Schedule implements Scheduler. A schedule produced by another algorithm carries no memory state and
+// is treated as a fresh card.
+func (s *FSRSScheduler) Schedule(flashcardID int, prev *models.CardSchedule, grade int, now time.Time) (*models.CardSchedule, error) {
+	if err := validateGrade(grade); err != nil {
+		return nil, err
+	}
+	rating := fsrsRating(grade)
+
+	next := models.CardSchedule{
+		FlashcardID: flashcardID,
+		Scheduler:   FSRSSchedulerName,
+	}
+
+	if prev == nil || prev.Scheduler != FSRSSchedulerName || prev.Stability <= 0 || prev.LastReviewedAt == nil {
+		next.Stability = s.weights.initialStability(rating)
+		next.Difficulty = s.weights.initialDifficulty(rating)
+	} else {
+		elapsedDays := math.Max(0, now.Sub(*prev.LastReviewedAt).Hours()/24)
+		retrievability := FSRSRetrievability(elapsedDays, prev.Stability)
+
+		next.Repetitions = prev.Repetitions
+		next.Retrievability = &retrievability
+		next.Difficulty = s.weights.nextDifficulty(prev.Difficulty, rating)
+		if rating == fsrsAgain {
+			next.Stability = s.weights.forgetStability(prev.Difficulty, prev.Stability, retrievability)
+		} else {
+			next.Stability = s.weights.recallStability(prev.Difficulty, prev.Stability, retrievability, rating)
+		}
+	}
+
+	if rating == fsrsAgain {
+		next.Repetitions = 0
+	} else {
+		next.Repetitions++
+	}
+
+	next.IntervalDays = fsrsInterval(next.Stability, s.desiredRetention)
+	reviewedAt := now
+	next.LastReviewedAt = &reviewedAt
+	next.DueAt = now.AddDate(0, 0, next.IntervalDays)
+
+	return &next, nil
+}
+
+// FSRSRetrievability is the probability of recalling a card with the given stability after the given
+// number of days.
+func FSRSRetrievability(elapsedDays, stability float64) float64 {
+	return math.Pow(1+fsrsFactor*elapsedDays/stability, fsrsDecay)
+}
+
+// fsrsInterval returns the number of days after which recall probability falls to desiredRetention.
+func fsrsInterval(stability, desiredRetention float64) int {
+	interval := stability / fsrsFactor * (math.Pow(desiredRetention, 1/fsrsDecay) - 1)
+	return int(math.Min(fsrsMaxIntervalDays, math.Max(1, math.Round(interval))))
+}
+
+// fsrsRating maps a 0-5 grade onto the four FSRS ratings: 0-2 are lapses, 3 is hard, 4 good, 5 easy.
+func fsrsRating(grade int) int {
+	switch {
+	case grade < sm2PassingGrade:
+		return fsrsAgain
+	case grade == sm2PassingGrade:
+		return fsrsHard
+	case grade == MaxGrade:
+		return fsrsEasy
+	default:
+		return fsrsGood
+	}
+}
+
+// fsrsWeights holds the 17 FSRS-4.5 parameters and implements the model's update formulas.
+type fsrsWeights []float64
+
+func (w fsrsWeights) initialStability(rating int) float64 {
+	return math.Max(fsrsMinStability, w[rating-1])
+}
+
+func (w fsrsWeights) initialDifficulty(rating int) float64 {
+	return clampDifficulty(w[4] - float64(rating-fsrsGood)*w[5])
+}
+
+func (w fsrsWeights) nextDifficulty(difficulty float64, rating int) float64 {
+	next := difficulty - w[6]*float64(rating-fsrsGood)
+	// Mean reversion towards the difficulty of a card first rated easy keeps difficulty from drifting.
+	reverted := w[7]*w.initialDifficulty(fsrsEasy) + (1-w[7])*next
+	return clampDifficulty(reverted)
+}
+
+func (w fsrsWeights) recallStability(difficulty, stability, retrievability float64, rating int) float64 {
+	hardPenalty := 1.0
+	if rating == fsrsHard {
+		hardPenalty = w[15]
+	}
+	easyBonus := 1.0
+	if rating == fsrsEasy {
+		easyBonus = w[16]
+	}
+
+	growth := math.Exp(w[8]) *
+		(11 - difficulty) *
+		math.Pow(stability, -w[9]) *
+		(math.Exp(w[10]*(1-retrievability)) - 1) *
+		hardPenalty *
+		easyBonus
+
+	return math.Max(fsrsMinStability, stability*(growth+1))
+}
+
+func (w fsrsWeights) forgetStability(difficulty, stability, retrievability float64) float64 {
+	next := w[11] *
+		math.Pow(difficulty, -w[12]) *
+		(math.Pow(stability+1, w[13]) - 1) *
+		math.Exp(w[14]*(1-retrievability))
+
+	// A lapse never makes a card more stable than it was before.
+	return math.Max(fsrsMinStability, math.Min(next, stability))
+}
+
+func clampDifficulty(d float64) float64 {
+	return math.Min(fsrsMaxDifficulty, math.Max(fsrsMinDifficulty, d))
+}
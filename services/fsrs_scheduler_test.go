@@ -0,0 +1,154 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+func TestFSRSSchedulerFirstReview(t *testing.T) {
+	s, err := NewFSRSScheduler(nil, DefaultDesiredRetention)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	good, _ := s.Schedule(1, nil, 4, now)
+	if good.Stability != DefaultFSRSWeights[2] {
+		t.Fatalf("expected initial stability %v, got %v", DefaultFSRSWeights[2], good.Stability)
+	}
+	if good.IntervalDays != 4 {
+		t.Fatalf("expected a 4 day interval at 90%% retention, got %d", good.IntervalDays)
+	}
+
+	again, _ := s.Schedule(1, nil, 1, now)
+	easy, _ := s.Schedule(1, nil, 5, now)
+	if !(again.Difficulty > good.Difficulty && good.Difficulty > easy.Difficulty) {
+		t.Fatalf("expected difficulty to decrease with better ratings, got %v, %v, %v", again.Difficulty, good.Difficulty, easy.Difficulty)
+	}
+	if !(again.IntervalDays <= good.IntervalDays && good.IntervalDays < easy.IntervalDays) {
+		t.Fatalf("expected intervals to grow with better ratings, got %d, %d, %d", again.IntervalDays, good.IntervalDays, easy.IntervalDays)
+	}
+}
+
+func TestFSRSSchedulerIsDeterministic(t *testing.T) {
+	s, _ := NewFSRSScheduler(nil, DefaultDesiredRetention)
+	now := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	first, _ := s.Schedule(1, nil, 4, now)
+	a, _ := s.Schedule(1, first, 4, first.DueAt)
+	b, _ := s.Schedule(1, first, 4, first.DueAt)
+	if *a.Retrievability != *b.Retrievability || a.Stability != b.Stability || !a.DueAt.Equal(b.DueAt) {
+		t.Fatalf("expected identical schedules for identical inputs")
+	}
+}
+
+func TestFSRSSchedulerRecallAndLapse(t *testing.T) {
+	s, _ := NewFSRSScheduler(nil, DefaultDesiredRetention)
+	now := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	first, _ := s.Schedule(1, nil, 4, now)
+	recalled, _ := s.Schedule(1, first, 4, first.DueAt)
+	if recalled.Stability <= first.Stability {
+		t.Fatalf("expected stability to grow after a successful review, got %v -> %v", first.Stability, recalled.Stability)
+	}
+	if recalled.Retrievability == nil || math.Abs(*recalled.Retrievability-DefaultDesiredRetention) > 0.02 {
+		t.Fatalf("expected retrievability near %v when reviewed on the due date, got %v", DefaultDesiredRetention, recalled.Retrievability)
+	}
+	if recalled.Repetitions != 2 {
+		t.Fatalf("expected 2 repetitions, got %d", recalled.Repetitions)
+	}
+
+	lapsed, _ := s.Schedule(1, recalled, 0, recalled.DueAt)
+	if lapsed.Stability >= recalled.Stability {
+		t.Fatalf("expected stability to shrink after a lapse, got %v -> %v", recalled.Stability, lapsed.Stability)
+	}
+	if lapsed.Repetitions != 0 {
+		t.Fatalf("expected a lapse to reset repetitions, got %d", lapsed.Repetitions)
+	}
+}
+
+func TestFSRSSchedulerIgnoresSM2State(t *testing.T) {
+	fsrs, _ := NewFSRSScheduler(nil, DefaultDesiredRetention)
+	now := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	sm2State, _ := NewSM2Scheduler().Schedule(1, nil, 4, now)
+	next, _ := fsrs.Schedule(1, sm2State, 4, sm2State.DueAt)
+	fresh, _ := fsrs.Schedule(1, nil, 4, sm2State.DueAt)
+	if next.Stability != fresh.Stability || next.IntervalDays != fresh.IntervalDays {
+		t.Fatalf("expected an SM-2 schedule to be treated as a new card")
+	}
+}
+
+func TestNewFSRSSchedulerValidation(t *testing.T) {
+	if _, err := NewFSRSScheduler([]float64{1, 2, 3}, DefaultDesiredRetention); err == nil {
+		t.Fatalf("expected error for wrong number of weights")
+	}
+	if _, err := NewFSRSScheduler(nil, 1.5); err == nil {
+		t.Fatalf("expected error for desired retention outside (0, 1)")
+	}
+}
+
+func TestNewScheduler(t *testing.T) {
+	for _, name := range []string{"", SM2SchedulerName, FSRSSchedulerName} {
+		if _, err := NewScheduler(name, nil); err != nil {
+			t.Fatalf("unexpected error for scheduler %q: %v", name, err)
+		}
+	}
+	if s, _ := NewScheduler(FSRSSchedulerName, &mockFSRSParameterRepo{}); s.(*StoredFSRSScheduler) == nil {
+		t.Fatalf("expected an FSRS scheduler with stored weights when weights are stored")
+	}
+	if _, err := NewScheduler("leitner", nil); err == nil {
+		t.Fatalf("expected error for unknown scheduler")
+	}
+}
+
+// mockFSRSParameterRepo stores FSRS weights by user and counts lookups.
+type mockFSRSParameterRepo struct {
+	params map[string]*models.FSRSParameters
+	gets   int
+}
+
+func (m *mockFSRSParameterRepo) Get(userID string) (*models.FSRSParameters, error) {
+	m.gets++
+	return m.params[userID], nil
+}
+
+func (m *mockFSRSParameterRepo) Save(params *models.FSRSParameters) error {
+	m.params[params.UserID] = params
+	return nil
+}
+
+func (m *mockFSRSParameterRepo) GetReviewHistory(string) ([]*models.ReviewLog, error) {
+	return nil, nil
+}
+
+func TestStoredFSRSSchedulerReloadsWeights(t *testing.T) {
+	repo := &mockFSRSParameterRepo{params: map[string]*models.FSRSParameters{}}
+	s, err := NewStoredFSRSScheduler(repo, DefaultDesiredRetention)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+	s.clock = fakeClock{now: now}
+
+	// Default weights are used until some are fitted
+	if next, _ := s.Schedule(1, nil, 4, now); next.Stability != DefaultFSRSWeights[2] {
+		t.Fatalf("expected default initial stability, got %v", next.Stability)
+	}
+
+	fitted := append([]float64(nil), DefaultFSRSWeights...)
+	fitted[2] = 7
+	_ = repo.Save(&models.FSRSParameters{UserID: models.DefaultUserID, Weights: fitted})
+	if next, _ := s.Schedule(1, nil, 4, now); next.Stability != DefaultFSRSWeights[2] || repo.gets != 1 {
+		t.Fatalf("expected cached weights to be reused, got stability %v after %d lookups", next.Stability, repo.gets)
+	}
+
+	// Newly fitted weights are picked up once the cached ones expire
+	s.clock = fakeClock{now: now.Add(fsrsWeightsCacheTTL)}
+	if next, _ := s.Schedule(1, nil, 4, now); next.Stability != 7 {
+		t.Fatalf("expected reloaded weights, got initial stability %v", next.Stability)
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// fsrsWeightsCacheTTL is how long the stored weights are cached, and so how long it takes weights
+// stored by cmd/optimize to be picked up.
+const fsrsWeightsCacheTTL = 10 * time.Minute
+
+// StoredFSRSScheduler schedules reviews with the FSRS weights that cmd/optimize fitted for the
+// deployment, stored under models.DefaultUserID, falling back to DefaultFSRSWeights until some have
+// been fitted. Weights are cached for a few minutes.
+type StoredFSRSScheduler struct {
+	*FSRSScheduler // schedules with DefaultFSRSWeights
+
+	repo  db.FSRSParameterRepository
+	clock Clock
+
+	mu       sync.Mutex
+	cached   *FSRSScheduler
+	loadedAt time.Time
+}
+
+// NewStoredFSRSScheduler creates an FSRS scheduler that loads its weights from repo.
+func NewStoredFSRSScheduler(repo db.FSRSParameterRepository, desiredRetention float64) (*StoredFSRSScheduler, error) {
+	if repo == nil {
+		panic("repository cannot be nil")
+	}
+	defaults, err := NewFSRSScheduler(nil, desiredRetention)
+	if err != nil {
+		return nil, err
+	}
+	return &StoredFSRSScheduler{FSRSScheduler: defaults, repo: repo, clock: systemClock{}}, nil
+}
+
+// Schedule implements Scheduler with the stored weights.
+func (s *StoredFSRSScheduler) Schedule(flashcardID int, prev *models.CardSchedule, grade int, now time.Time) (*models.CardSchedule, error) {
+	scheduler, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return scheduler.Schedule(flashcardID, prev, grade, now)
+}
+
+// load returns a scheduler with the stored weights, reading them again once the cached ones expire.
+func (s *StoredFSRSScheduler) load() (*FSRSScheduler, error) {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	cached, loadedAt := s.cached, s.loadedAt
+	s.mu.Unlock()
+	if cached != nil && now.Sub(loadedAt) < fsrsWeightsCacheTTL {
+		return cached, nil
+	}
+
+	params, err := s.repo.Get(models.DefaultUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduler := s.FSRSScheduler
+	if params != nil {
+		fitted, err := NewFSRSScheduler(params.Weights, s.desiredRetention)
+		if err != nil {
+			log.Printf("Ignoring stored FSRS weights: %v", err)
+		} else {
+			scheduler = fitted
+		}
+	}
+
+	s.mu.Lock()
+	s.cached, s.loadedAt = scheduler, now
+	s.mu.Unlock()
+	return scheduler, nil
+}
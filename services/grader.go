@@ -0,0 +1,304 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/akolybelnikov/flashcards/models"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Review grades given to typed answers on the 0-5 scale.
+const (
+	correctAnswerGrade   = 4
+	almostAnswerGrade    = 3
+	incorrectAnswerGrade = 1
+)
+
+// maxDiffCells bounds the size of the table used to diff two answers.
+const maxDiffCells = 1_000_000
+
+// accentFolds maps precomposed letters to their letter without diacritics. Combining marks are removed
+// separately, so decomposed input folds the same way.
+var accentFolds = map[rune]rune{
+	'ά': 'α', 'έ': 'ε', 'ή': 'η', 'ί': 'ι', 'ό': 'ο', 'ύ': 'υ', 'ώ': 'ω',
+	'ϊ': 'ι', 'ϋ': 'υ', 'ΐ': 'ι', 'ΰ': 'υ',
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a', 'ā': 'a', 'ą': 'a',
+	'ç': 'c', 'ć': 'c', 'č': 'c',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e', 'ē': 'e', 'ę': 'e', 'ě': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i', 'ī': 'i',
+	'ñ': 'n', 'ń': 'n', 'ň': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ø': 'o', 'ō': 'o', 'ő': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u', 'ū': 'u', 'ů': 'u', 'ű': 'u',
+	'ý': 'y', 'ÿ': 'y',
+	'ğ': 'g', 'ş': 's', 'ś': 's', 'š': 's', 'ž': 'z', 'ź': 'z', 'ż': 'z', 'ř': 'r', 'ł': 'l',
+	'ё': 'е', 'й': 'и',
+}
+
+// AnswerGrade is the outcome of comparing a typed answer with the expected one.
+type AnswerGrade struct {
+	Verdict      string
+	Grade        int
+	Diff         []models.DiffSegment
+	Explanations []string
+}
+
+// AnswerGrader grades typed answers. Case, punctuation and extra whitespace never count as mistakes.
+// Missing or misplaced accents make an answer "almost" correct in languages where diacritics change
+// meaning, such as Greek, and are ignored elsewhere. A final sigma written as σ, or ς written inside a
+// word, is always "almost", as is a small typo.
+type AnswerGrader struct {
+	languages *LanguageRegistry
+}
+
+// NewAnswerGrader creates a grader. Languages missing from the registry, or all languages if it is
+// nil, are treated as having significant diacritics.
+func NewAnswerGrader(languages *LanguageRegistry) *AnswerGrader {
+	return &AnswerGrader{languages: languages}
+}
+
+// Grade compares a typed answer with the expected answer, written in the given language.
+func (g *AnswerGrader) Grade(expected, given, lang string) *AnswerGrade {
+	expectedText := normalizeAnswer(expected)
+	givenText := normalizeAnswer(given)
+	result := &AnswerGrade{Diff: diffAnswers(givenText, expectedText)}
+
+	expectedWords := answerWords(expectedText)
+	givenWords := answerWords(givenText)
+	e := strings.Join(expectedWords, " ")
+	a := strings.Join(givenWords, " ")
+
+	// Each fold forgives one kind of mistake: accentsOnly holds when the answers differ at most in
+	// accents, lettersMatch when they differ at most in accents and final sigmas.
+	accentsOnly := foldAccents(a) == foldAccents(e)
+	lettersMatch := foldAccents(foldSigma(a)) == foldAccents(foldSigma(e))
+
+	switch {
+	case a == e, accentsOnly && !g.diacriticsSignificant(lang):
+		result.Verdict = models.VerdictCorrect
+	case lettersMatch, levenshtein(foldAccents(foldSigma(a)), foldAccents(foldSigma(e))) <= typoTolerance(len([]rune(e))):
+		result.Verdict = models.VerdictAlmost
+	default:
+		result.Verdict = models.VerdictIncorrect
+	}
+
+	switch result.Verdict {
+	case models.VerdictCorrect:
+		result.Grade = correctAnswerGrade
+	case models.VerdictAlmost:
+		result.Grade = almostAnswerGrade
+		result.Explanations = g.explain(givenWords, expectedWords, lang)
+	default:
+		result.Grade = incorrectAnswerGrade
+	}
+
+	return result
+}
+
+func (g *AnswerGrader) diacriticsSignificant(lang string) bool {
+	if g.languages == nil {
+		return true
+	}
+	language, ok := g.languages.Lookup(lang)
+	return !ok || language.DiacriticsSignificant
+}
+
+// explain describes the mistakes in an almost correct answer word by word.
+func (g *AnswerGrader) explain(given, expected []string, lang string) []string {
+	if len(given) != len(expected) {
+		return []string{"Almost: check the spelling against the expected answer"}
+	}
+
+	significant := g.diacriticsSignificant(lang)
+	var explanations []string
+	for i, want := range expected {
+		got := given[i]
+		if got == want {
+			continue
+		}
+
+		if explanation := explainSigma(got, want); explanation != "" {
+			explanations = append(explanations, explanation)
+		}
+		if foldAccents(foldSigma(got)) == foldAccents(foldSigma(want)) {
+			if significant {
+				if explanation := explainAccents(foldSigma(got), foldSigma(want)); explanation != "" {
+					explanations = append(explanations, explanation)
+				}
+			}
+			continue
+		}
+		explanations = append(explanations, fmt.Sprintf("Spelling mistake in %q: write %q", got, want))
+	}
+
+	if len(explanations) == 0 {
+		explanations = append(explanations, "Almost: check the spelling against the expected answer")
+	}
+	return explanations
+}
+
+// explainSigma describes a misused final sigma, or returns an empty string if sigmas are used right.
+func explainSigma(got, want string) string {
+	gotRunes, wantRunes := []rune(got), []rune(want)
+	if len(gotRunes) != len(wantRunes) {
+		return ""
+	}
+	for i := range wantRunes {
+		switch {
+		case wantRunes[i] == 'ς' && gotRunes[i] == 'σ':
+			return fmt.Sprintf("Use the final sigma ς at the end of a word: write %q, not %q", want, got)
+		case wantRunes[i] == 'σ' && gotRunes[i] == 'ς':
+			return fmt.Sprintf("The final sigma ς is only written at the end of a word: write %q, not %q", want, got)
+		}
+	}
+	return ""
+}
+
+// explainAccents describes a word whose letters are right but whose accents differ.
+func explainAccents(got, want string) string {
+	mark := "accent"
+	if strings.IndexFunc(want, func(r rune) bool { return unicode.Is(unicode.Greek, r) }) >= 0 {
+		mark = "tonos"
+	}
+
+	gotPlain, wantPlain := foldAccents(got) == got, foldAccents(want) == want
+	switch {
+	case got == want:
+		return ""
+	case gotPlain:
+		return fmt.Sprintf("Missing %s: write %q, not %q", mark, want, got)
+	case wantPlain:
+		return fmt.Sprintf("%q takes no %s: write %q", got, mark, want)
+	default:
+		return fmt.Sprintf("The %s is on the wrong letter: write %q, not %q", mark, want, got)
+	}
+}
+
+// normalizeAnswer composes an answer to NFC, so that accents typed as combining marks match
+// precomposed letters, lowercases it and collapses its whitespace. A capital Σ at the end of a
+// word becomes the final sigma ς, so answers typed in capitals are not marked down for it.
+func normalizeAnswer(s string) string {
+	runes := []rune(norm.NFC.String(s))
+	for i, r := range runes {
+		if r == 'Σ' && (i+1 == len(runes) || !unicode.IsLetter(runes[i+1])) {
+			runes[i] = 'ς'
+		} else {
+			runes[i] = unicode.ToLower(r)
+		}
+	}
+	return strings.Join(strings.Fields(string(runes)), " ")
+}
+
+// answerWords splits a normalized answer into words, dropping punctuation.
+func answerWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+}
+
+// foldSigma replaces the final sigma ς with σ.
+func foldSigma(s string) string {
+	return strings.ReplaceAll(s, "ς", "σ")
+}
+
+// foldAccents removes diacritics from lowercase text.
+func foldAccents(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if folded, ok := accentFolds[r]; ok {
+			r = folded
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// typoTolerance is the number of edits a typed answer of n letters may be off by and still count as
+// almost correct. Short words allow none, since a single letter often makes a different word.
+func typoTolerance(n int) int {
+	if n < 4 {
+		return 0
+	}
+	return 1 + (n-4)/8
+}
+
+// levenshtein returns the edit distance between two strings, counted in runes.
+func levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(br)]
+}
+
+// diffAnswers computes a character-level diff turning given into expected from their longest common
+// subsequence.
+func diffAnswers(given, expected string) []models.DiffSegment {
+	a, b := []rune(given), []rune(expected)
+	if len(a)*len(b) > maxDiffCells {
+		return appendDiff(appendDiff(nil, models.DiffDelete, given), models.DiffInsert, expected)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []models.DiffSegment
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			diff = appendDiff(diff, models.DiffEqual, string(a[i]))
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff = appendDiff(diff, models.DiffDelete, string(a[i]))
+			i++
+		default:
+			diff = appendDiff(diff, models.DiffInsert, string(b[j]))
+			j++
+		}
+	}
+	return diff
+}
+
+// appendDiff adds text to a diff, merging it into the last segment if that has the same operation.
+func appendDiff(diff []models.DiffSegment, op, text string) []models.DiffSegment {
+	if text == "" {
+		return diff
+	}
+	if n := len(diff); n > 0 && diff[n-1].Op == op {
+		diff[n-1].Text += text
+		return diff
+	}
+	return append(diff, models.DiffSegment{Op: op, Text: text})
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+func TestAnswerGraderVerdicts(t *testing.T) {
+	grader := NewAnswerGrader(testLanguages(t))
+
+	tests := []struct {
+		name        string
+		expected    string
+		given       string
+		lang        string
+		verdict     string
+		explanation string
+	}{
+		{"exact", "γεια σας", "γεια σας", "el", models.VerdictCorrect, ""},
+		{"decomposed tonos", "πότε", "πο\u0301τε", "el", models.VerdictCorrect, ""},
+		{"decomposed expected", "πο\u0301τε", "πότε", "el", models.VerdictCorrect, ""},
+		{"case and punctuation", "Γεια σας!", "  γεια   ΣΑΣ ", "el", models.VerdictCorrect, ""},
+		{"missing tonos", "πότε", "ποτε", "el", models.VerdictAlmost, `Missing tonos: write "πότε"`},
+		{"misplaced tonos", "ποτέ", "πότε", "el", models.VerdictAlmost, "wrong letter"},
+		{"unexpected tonos", "γεια", "γειά", "el", models.VerdictAlmost, "takes no tonos"},
+		{"final sigma", "γεια σας", "γεια σασ", "el", models.VerdictAlmost, "Use the final sigma ς"},
+		{"medial final sigma", "σας", "ςας", "el", models.VerdictAlmost, "only written at the end of a word"},
+		{"accents ignored", "café", "cafe", "en", models.VerdictCorrect, ""},
+		{"unknown language keeps accents", "café", "cafe", "fr", models.VerdictAlmost, "Missing accent"},
+		{"typo", "goodbye", "goodbie", "en", models.VerdictAlmost, "Spelling mistake"},
+		{"short word typo", "το", "τα", "el", models.VerdictIncorrect, ""},
+		{"wrong", "hello", "goodbye", "en", models.VerdictIncorrect, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := grader.Grade(tt.expected, tt.given, tt.lang)
+			if result.Verdict != tt.verdict {
+				t.Fatalf("expected verdict %s, got %s (%v)", tt.verdict, result.Verdict, result.Explanations)
+			}
+			if tt.explanation != "" && !strings.Contains(strings.Join(result.Explanations, "\n"), tt.explanation) {
+				t.Fatalf("expected an explanation containing %q, got %v", tt.explanation, result.Explanations)
+			}
+		})
+	}
+}
+
+func TestAnswerGraderGrades(t *testing.T) {
+	grader := NewAnswerGrader(nil)
+
+	for given, want := range map[string]int{"σας": correctAnswerGrade, "σασ": almostAnswerGrade, "εσύ": incorrectAnswerGrade} {
+		if got := grader.Grade("σας", given, "el").Grade; got != want {
+			t.Errorf("%q: expected grade %d, got %d", given, want, got)
+		}
+	}
+}
+
+func TestDiffAnswers(t *testing.T) {
+	got := diffAnswers("γεια σασ", "γεια σας")
+	want := []models.DiffSegment{
+		{Op: models.DiffEqual, Text: "γεια σα"},
+		{Op: models.DiffDelete, Text: "σ"},
+		{Op: models.DiffInsert, Text: "ς"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	// Decomposed accents are composed before diffing, so they are not reported as changes
+	result := NewAnswerGrader(nil).Grade("ά", "α\u0301", "el")
+	if want := []models.DiffSegment{{Op: models.DiffEqual, Text: "ά"}}; !reflect.DeepEqual(result.Diff, want) {
+		t.Fatalf("expected %+v, got %+v", want, result.Diff)
+	}
+
+	got = diffAnswers("", "abc")
+	if want := []models.DiffSegment{{Op: models.DiffInsert, Text: "abc"}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"kitten", "sitting", 3},
+		{"γεια", "γειά", 1},
+		{"σας", "", 3},
+	} {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// ErrInvalidGrammar is returned when grammar data does not match the grammar schema.
+var ErrInvalidGrammar = errors.New("invalid grammar")
+
+// ErrGrammarLanguage is returned when grammar enrichment is requested for a card without a Greek side.
+var ErrGrammarLanguage = errors.New("grammar enrichment needs a Greek question or answer")
+
+// articleGenders maps each Greek definite article to the genders it can mark. The plural articles
+// are used by nouns that only exist in the plural.
+var articleGenders = map[string][]string{
+	"ο":  {models.GenderMasculine},
+	"η":  {models.GenderFeminine},
+	"το": {models.GenderNeuter},
+	"οι": {models.GenderMasculine, models.GenderFeminine},
+	"τα": {models.GenderNeuter},
+}
+
+// isGreek reports whether a canonical language code is Greek or one of its regional forms.
+func isGreek(lang string) bool {
+	return lang == "el" || strings.HasPrefix(lang, "el-")
+}
+
+// greekSide returns the Greek text of a new card and its language, preferring the answer.
+func greekSide(req *models.CreateFlashcardRequest) (string, string, bool) {
+	switch {
+	case isGreek(req.AnswerLang):
+		return req.Answer, req.AnswerLang, true
+	case isGreek(req.QuestionLang):
+		return req.Question, req.QuestionLang, true
+	default:
+		return "", "", false
+	}
+}
+
+// validateGrammar checks grammar data against the grammar schema: nouns have an article matching
+// their gender and their case forms, verbs their principal parts and a full conjugation table, and
+// every form is written in Greek.
+func validateGrammar(grammar *models.Grammar) error {
+	if grammar == nil {
+		return fmt.Errorf("%w: no grammar data", ErrInvalidGrammar)
+	}
+	if err := requireGreek("lemma", grammar.Lemma); err != nil {
+		return err
+	}
+
+	switch grammar.PartOfSpeech {
+	case models.PartOfSpeechNoun:
+		if grammar.Noun == nil || grammar.Verb != nil {
+			return fmt.Errorf("%w: a noun must have noun and no verb data", ErrInvalidGrammar)
+		}
+		return validateNounGrammar(grammar.Noun)
+	case models.PartOfSpeechVerb:
+		if grammar.Verb == nil || grammar.Noun != nil {
+			return fmt.Errorf("%w: a verb must have verb and no noun data", ErrInvalidGrammar)
+		}
+		return validateVerbGrammar(grammar.Verb)
+	case models.PartOfSpeechOther:
+		if grammar.Noun != nil || grammar.Verb != nil {
+			return fmt.Errorf("%w: only nouns and verbs have grammar data", ErrInvalidGrammar)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown part of speech %q", ErrInvalidGrammar, grammar.PartOfSpeech)
+	}
+}
+
+func validateNounGrammar(noun *models.NounGrammar) error {
+	genders, ok := articleGenders[noun.Article]
+	if !ok {
+		return fmt.Errorf("%w: unknown article %q", ErrInvalidGrammar, noun.Article)
+	}
+	if !slices.Contains(genders, noun.Gender) {
+		return fmt.Errorf("%w: article %q does not match gender %q", ErrInvalidGrammar, noun.Article, noun.Gender)
+	}
+
+	pluralOnly := noun.Article == "οι" || noun.Article == "τα"
+	if !pluralOnly {
+		if err := requireGreek("genitive_singular", noun.GenitiveSingular); err != nil {
+			return err
+		}
+	}
+	if noun.NominativePlural == "" && noun.GenitivePlural == "" && !pluralOnly {
+		return nil
+	}
+	if err := requireGreek("nominative_plural", noun.NominativePlural); err != nil {
+		return err
+	}
+	return requireGreek("genitive_plural", noun.GenitivePlural)
+}
+
+func validateVerbGrammar(verb *models.VerbGrammar) error {
+	parts := []struct {
+		name, form string
+		optional   bool
+	}{
+		{"present", verb.Present, false},
+		{"aorist", verb.Aorist, false},
+		{"future", verb.Future, false},
+		{"passive_aorist", verb.PassiveAorist, true},
+		{"passive_participle", verb.PassiveParticiple, true},
+	}
+	for _, part := range parts {
+		if part.optional && part.form == "" {
+			continue
+		}
+		if err := requireGreek(part.name, part.form); err != nil {
+			return err
+		}
+	}
+
+	for tense := range verb.Conjugation {
+		if !slices.Contains(models.GrammarTenses, tense) {
+			return fmt.Errorf("%w: unknown tense %q", ErrInvalidGrammar, tense)
+		}
+	}
+	for _, tense := range models.GrammarTenses {
+		forms, ok := verb.Conjugation[tense]
+		if !ok {
+			return fmt.Errorf("%w: conjugation is missing the %s tense", ErrInvalidGrammar, tense)
+		}
+		for _, form := range forms.All() {
+			if err := requireGreek("conjugation."+tense, form); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// requireGreek checks that a grammar field is set and written in the Greek alphabet.
+func requireGreek(field, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("%w: %s is required", ErrInvalidGrammar, field)
+	}
+	if strings.IndexFunc(value, func(r rune) bool { return unicode.Is(unicode.Greek, r) }) < 0 {
+		return fmt.Errorf("%w: %s %q is not written in Greek", ErrInvalidGrammar, field, value)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+func testNounGrammar() *models.Grammar {
+	return &models.Grammar{
+		PartOfSpeech: models.PartOfSpeechNoun,
+		Lemma:        "δρόμος",
+		Noun: &models.NounGrammar{
+			Article:          "ο",
+			Gender:           models.GenderMasculine,
+			GenitiveSingular: "δρόμου",
+			NominativePlural: "δρόμοι",
+			GenitivePlural:   "δρόμων",
+		},
+	}
+}
+
+func testVerbGrammar() *models.Grammar {
+	forms := models.VerbForms{
+		FirstSingular:  "γράφω",
+		SecondSingular: "γράφεις",
+		ThirdSingular:  "γράφει",
+		FirstPlural:    "γράφουμε",
+		SecondPlural:   "γράφετε",
+		ThirdPlural:    "γράφουν",
+	}
+	return &models.Grammar{
+		PartOfSpeech: models.PartOfSpeechVerb,
+		Lemma:        "γράφω",
+		Verb: &models.VerbGrammar{
+			Present: "γράφω",
+			Aorist:  "έγραψα",
+			Future:  "θα γράψω",
+			Conjugation: map[string]models.VerbForms{
+				models.TensePresent:   forms,
+				models.TenseImperfect: forms,
+				models.TenseAorist:    forms,
+				models.TenseFuture:    forms,
+			},
+		},
+	}
+}
+
+func TestValidateGrammar(t *testing.T) {
+	tests := []struct {
+		name    string
+		grammar func() *models.Grammar
+		valid   bool
+	}{
+		{"noun", testNounGrammar, true},
+		{"verb", testVerbGrammar, true},
+		{"other", func() *models.Grammar {
+			return &models.Grammar{PartOfSpeech: models.PartOfSpeechOther, Lemma: "και"}
+		}, true},
+		{"uncountable noun", func() *models.Grammar {
+			g := testNounGrammar()
+			g.Noun.NominativePlural, g.Noun.GenitivePlural = "", ""
+			return g
+		}, true},
+		{"plural-only noun", func() *models.Grammar {
+			return &models.Grammar{PartOfSpeech: models.PartOfSpeechNoun, Lemma: "διακοπές", Noun: &models.NounGrammar{
+				Article: "οι", Gender: models.GenderFeminine, NominativePlural: "διακοπές", GenitivePlural: "διακοπών",
+			}}
+		}, true},
+		{"article does not match gender", func() *models.Grammar {
+			g := testNounGrammar()
+			g.Noun.Article = "η"
+			return g
+		}, false},
+		{"unknown gender", func() *models.Grammar {
+			g := testNounGrammar()
+			g.Noun.Gender = "common"
+			return g
+		}, false},
+		{"missing genitive", func() *models.Grammar {
+			g := testNounGrammar()
+			g.Noun.GenitiveSingular = ""
+			return g
+		}, false},
+		{"only one plural form", func() *models.Grammar {
+			g := testNounGrammar()
+			g.Noun.GenitivePlural = ""
+			return g
+		}, false},
+		{"transliterated form", func() *models.Grammar {
+			g := testNounGrammar()
+			g.Noun.GenitiveSingular = "dromou"
+			return g
+		}, false},
+		{"noun without noun data", func() *models.Grammar {
+			return &models.Grammar{PartOfSpeech: models.PartOfSpeechNoun, Lemma: "δρόμος"}
+		}, false},
+		{"noun with verb data", func() *models.Grammar {
+			g := testNounGrammar()
+			g.Verb = testVerbGrammar().Verb
+			return g
+		}, false},
+		{"missing tense", func() *models.Grammar {
+			g := testVerbGrammar()
+			delete(g.Verb.Conjugation, models.TenseImperfect)
+			return g
+		}, false},
+		{"unknown tense", func() *models.Grammar {
+			g := testVerbGrammar()
+			g.Verb.Conjugation["pluperfect"] = g.Verb.Conjugation[models.TensePresent]
+			return g
+		}, false},
+		{"missing person", func() *models.Grammar {
+			g := testVerbGrammar()
+			forms := g.Verb.Conjugation[models.TenseAorist]
+			forms.SecondPlural = ""
+			g.Verb.Conjugation[models.TenseAorist] = forms
+			return g
+		}, false},
+		{"missing aorist", func() *models.Grammar {
+			g := testVerbGrammar()
+			g.Verb.Aorist = ""
+			return g
+		}, false},
+		{"unknown part of speech", func() *models.Grammar {
+			return &models.Grammar{PartOfSpeech: "adjective", Lemma: "καλός"}
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateGrammar(tt.grammar())
+			if tt.valid && err != nil {
+				t.Fatalf("expected valid grammar, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidGrammar) {
+				t.Fatalf("expected ErrInvalidGrammar, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// LanguageRegistry holds the languages flashcards can be written in and translated between.
+type LanguageRegistry struct {
+	languages []models.Language
+	byCode    map[string]*models.Language
+}
+
+// NewLanguageRegistry creates a registry from a list of languages. Codes must be distinct, well-formed
+// BCP 47 tags; they are stored in canonical form.
+func NewLanguageRegistry(languages []models.Language) (*LanguageRegistry, error) {
+	if len(languages) == 0 {
+		return nil, fmt.Errorf("language registry is empty")
+	}
+
+	registry := &LanguageRegistry{
+		languages: make([]models.Language, len(languages)),
+		byCode:    make(map[string]*models.Language, len(languages)),
+	}
+	for i, language := range languages {
+		code, err := CanonicalLanguageTag(language.Code)
+		if err != nil {
+			return nil, err
+		}
+		if language.Name == "" {
+			return nil, fmt.Errorf("language %q has no name", code)
+		}
+		if language.Direction == "" {
+			language.Direction = models.DirectionLTR
+		}
+		if language.Direction != models.DirectionLTR && language.Direction != models.DirectionRTL {
+			return nil, fmt.Errorf("language %q has invalid direction %q", code, language.Direction)
+		}
+		if _, ok := registry.byCode[code]; ok {
+			return nil, fmt.Errorf("language %q is listed more than once", code)
+		}
+
+		language.Code = code
+		registry.languages[i] = language
+		registry.byCode[code] = &registry.languages[i]
+	}
+
+	return registry, nil
+}
+
+// LoadLanguageRegistry creates a registry from a JSON array of languages, as found in
+// config/languages.json.
+func LoadLanguageRegistry(data []byte) (*LanguageRegistry, error) {
+	var languages []models.Language
+	if err := json.Unmarshal(data, &languages); err != nil {
+		return nil, fmt.Errorf("failed to parse language registry: %w", err)
+	}
+	return NewLanguageRegistry(languages)
+}
+
+// All returns the registered languages in registry order.
+func (r *LanguageRegistry) All() []models.Language {
+	return append([]models.Language(nil), r.languages...)
+}
+
+// Lookup finds the language of a BCP 47 code. A code that is not registered falls back to its less
+// specific forms, so "el-GR" finds Greek when only "el" is registered.
+func (r *LanguageRegistry) Lookup(code string) (*models.Language, bool) {
+	canonical, err := CanonicalLanguageTag(code)
+	if err != nil {
+		return nil, false
+	}
+
+	for {
+		if language, ok := r.byCode[canonical]; ok {
+			return language, true
+		}
+		i := strings.LastIndex(canonical, "-")
+		if i < 0 {
+			return nil, false
+		}
+		canonical = canonical[:i]
+	}
+}
+
+// PromptName describes a language for LLM prompts, e.g. "Greek (Ελληνικά)". Unknown codes are
+// returned unchanged.
+func (r *LanguageRegistry) PromptName(code string) string {
+	language, ok := r.Lookup(code)
+	if !ok {
+		return code
+	}
+	if language.NativeName == "" || language.NativeName == language.Name {
+		return language.Name
+	}
+	return fmt.Sprintf("%s (%s)", language.Name, language.NativeName)
+}
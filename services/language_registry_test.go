@@ -0,0 +1,95 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akolybelnikov/flashcards/config"
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// testLanguages returns a registry with English and Greek.
+func testLanguages(t *testing.T) *LanguageRegistry {
+	t.Helper()
+	registry, err := NewLanguageRegistry([]models.Language{
+		{Code: "en", Name: "English", NativeName: "English", Script: "Latn"},
+		{Code: "el", Name: "Greek", NativeName: "Ελληνικά", Script: "Grek", DiacriticsSignificant: true},
+	})
+	if err != nil {
+		t.Fatalf("failed to create language registry: %v", err)
+	}
+	return registry
+}
+
+func TestLanguageRegistryLookup(t *testing.T) {
+	registry := testLanguages(t)
+
+	for code, want := range map[string]string{"el": "Greek", "EL-gr": "Greek", "en-US-x-test": "English"} {
+		language, ok := registry.Lookup(code)
+		if !ok || language.Name != want {
+			t.Errorf("%q: expected %s, got %+v", code, want, language)
+		}
+	}
+	for _, code := range []string{"de", "", "not a code"} {
+		if _, ok := registry.Lookup(code); ok {
+			t.Errorf("%q: expected lookup to fail", code)
+		}
+	}
+}
+
+func TestLanguageRegistryPromptName(t *testing.T) {
+	registry := testLanguages(t)
+
+	if got := registry.PromptName("el"); got != "Greek (Ελληνικά)" {
+		t.Errorf("expected Greek (Ελληνικά), got %q", got)
+	}
+	if got := registry.PromptName("en"); got != "English" {
+		t.Errorf("expected English, got %q", got)
+	}
+	if got := registry.PromptName("sw"); got != "sw" {
+		t.Errorf("expected unknown code to be returned unchanged, got %q", got)
+	}
+}
+
+func TestLoadLanguageRegistryRejectsInvalidEntries(t *testing.T) {
+	for name, data := range map[string]string{
+		"not json":  `{`,
+		"empty":     `[]`,
+		"bad code":  `[{"code": "en_US", "name": "English"}]`,
+		"no name":   `[{"code": "en"}]`,
+		"direction": `[{"code": "en", "name": "English", "direction": "up"}]`,
+		"duplicate": `[{"code": "en", "name": "English"}, {"code": "EN", "name": "English"}]`,
+	} {
+		if _, err := LoadLanguageRegistry([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestBuiltInLanguageRegistryLoads(t *testing.T) {
+	data, err := config.LoadLanguages("")
+	if err != nil {
+		t.Fatalf("failed to read built-in languages: %v", err)
+	}
+	registry, err := LoadLanguageRegistry(data)
+	if err != nil {
+		t.Fatalf("failed to load built-in languages: %v", err)
+	}
+	if greek, ok := registry.Lookup("el"); !ok || !greek.DiacriticsSignificant {
+		t.Fatalf("expected Greek with significant diacritics, got %+v", greek)
+	}
+}
+
+func TestTranslationPromptUsesRegistry(t *testing.T) {
+	prompt := translationPrompt(testLanguages(t), "when", "en", "el-GR")
+
+	if !strings.Contains(prompt, "from English to Greek (Ελληνικά)") {
+		t.Errorf("expected language names in prompt, got %q", prompt)
+	}
+	if !strings.Contains(prompt, "diacritic") {
+		t.Errorf("expected a diacritics instruction for Greek, got %q", prompt)
+	}
+	if !strings.HasSuffix(prompt, "Text: when") {
+		t.Errorf("expected prompt to end with the text, got %q", prompt)
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidLanguageTag is returned for language codes that are not well-formed BCP 47 tags.
+var ErrInvalidLanguageTag = errors.New("invalid language tag")
+
+// CanonicalLanguageTag checks that tag is a well-formed BCP 47 language tag (RFC 5646), such as "en",
+// "el-GR" or "zh-Hant-TW", and returns it in canonical case: a lowercase language, title-case script
+// and uppercase region. Whether the subtags are registered is not checked.
+func CanonicalLanguageTag(tag string) (string, error) {
+	invalid := fmt.Errorf("%w: %q", ErrInvalidLanguageTag, tag)
+
+	subtags := strings.Split(strings.ToLower(tag), "-")
+	for _, subtag := range subtags {
+		if subtag == "" || len(subtag) > 8 || !isAlphanumeric(subtag) {
+			return "", invalid
+		}
+	}
+
+	i := 0
+	if subtags[0] != "x" {
+		language := subtags[0]
+		if len(language) < 2 || !isAlpha(language) {
+			return "", invalid
+		}
+		i++
+
+		// Up to three extended language subtags may follow a two- or three-letter language.
+		if len(language) <= 3 {
+			for n := 0; n < 3 && i < len(subtags) && len(subtags[i]) == 3 && isAlpha(subtags[i]); n++ {
+				i++
+			}
+		}
+
+		if i < len(subtags) && len(subtags[i]) == 4 && isAlpha(subtags[i]) {
+			subtags[i] = strings.ToUpper(subtags[i][:1]) + subtags[i][1:]
+			i++
+		}
+
+		if i < len(subtags) && (len(subtags[i]) == 2 && isAlpha(subtags[i]) || len(subtags[i]) == 3 && isDigits(subtags[i])) {
+			subtags[i] = strings.ToUpper(subtags[i])
+			i++
+		}
+
+		variants := make(map[string]bool)
+		for i < len(subtags) && isVariant(subtags[i]) {
+			if variants[subtags[i]] {
+				return "", invalid
+			}
+			variants[subtags[i]] = true
+			i++
+		}
+
+		singletons := make(map[string]bool)
+		for i < len(subtags) && len(subtags[i]) == 1 && subtags[i] != "x" {
+			if singletons[subtags[i]] {
+				return "", invalid
+			}
+			singletons[subtags[i]] = true
+			i++
+
+			start := i
+			for i < len(subtags) && len(subtags[i]) >= 2 {
+				i++
+			}
+			if i == start {
+				return "", invalid
+			}
+		}
+	}
+
+	if i < len(subtags) && subtags[i] == "x" {
+		i++
+		if i == len(subtags) {
+			return "", invalid
+		}
+		// Private use subtags may be anything from one to eight characters.
+		i = len(subtags)
+	}
+
+	if i != len(subtags) {
+		return "", invalid
+	}
+
+	return strings.Join(subtags, "-"), nil
+}
+
+// isVariant reports whether a subtag is a variant: five to eight characters, or four starting with
+// a digit.
+func isVariant(subtag string) bool {
+	return len(subtag) >= 5 || len(subtag) == 4 && isDigits(subtag[:1])
+}
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
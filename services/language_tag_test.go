@@ -0,0 +1,40 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCanonicalLanguageTag(t *testing.T) {
+	tests := map[string]string{
+		"en":                 "en",
+		"EL":                 "el",
+		"el-gr":              "el-GR",
+		"zh-hant-tw":         "zh-Hant-TW",
+		"es-419":             "es-419",
+		"sl-rozaj-biske":     "sl-rozaj-biske",
+		"de-DE-1996":         "de-DE-1996",
+		"zh-yue-HK":          "zh-yue-HK",
+		"en-US-u-ca-gregory": "en-US-u-ca-gregory",
+		"en-x-custom":        "en-x-custom",
+		"x-whatever":         "x-whatever",
+	}
+	for input, want := range tests {
+		got, err := CanonicalLanguageTag(input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%q: expected %q, got %q", input, want, got)
+		}
+	}
+}
+
+func TestCanonicalLanguageTagInvalid(t *testing.T) {
+	for _, input := range []string{"", "e", "english1", "en_US", "en-", "en--US", "123", "de-DE-1996-1996", "en-a", "en-a-bbb-a-ccc", "en-x", "ελ", "en-US-toolongsubtag"} {
+		if _, err := CanonicalLanguageTag(input); !errors.Is(err, ErrInvalidLanguageTag) {
+			t.Errorf("%q: expected ErrInvalidLanguageTag, got %v", input, err)
+		}
+	}
+}
@@ -4,73 +4,277 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/akolybelnikov/flashcards/models"
+
 	"github.com/tmc/langchaingo/llms"
-	"github.com/tmc/langchaingo/llms/openai"
 )
 
+// promptVersion identifies the prompts in this file in the AI provenance of flashcards. Bump it when
+// a prompt changes.
+const promptVersion = "2025-11-19"
+
 // LLMClient defines the interface for language model operations
 type LLMClient interface {
-	Translate(ctx context.Context, text, sourceLang, targetLang string) (string, error)
+	// Model names the model that generates the client's output.
+	Model() string
+	// Translate translates text from sourceLang to targetLang. The translation is sanitised: it holds
+	// no quotes, labels or explanations around the translated text.
+	Translate(ctx context.Context, text, sourceLang, targetLang string) (*models.Translation, error)
+	// TranslateCandidates returns up to n distinct translations of text, best first, with notes on
+	// the meaning or register each is used for.
+	TranslateCandidates(ctx context.Context, text, sourceLang, targetLang string, n int) ([]models.TranslationCandidate, error)
+	// GenerateExamples writes n sentences using word in lang, translated into translationLang and
+	// graded from easiest to hardest.
+	GenerateExamples(ctx context.Context, word, lang, translationLang string, n int) ([]models.ExampleSentence, error)
+	// ExtractGrammar describes the grammar of a Greek word: noun forms, or verb principal parts and
+	// conjugations.
+	ExtractGrammar(ctx context.Context, word, lang string) (*models.Grammar, error)
 }
 
-// OpenAIClient implements LLMClient using OpenAI
-type OpenAIClient struct {
-	llm *openai.LLM
+// Timeouts of a single request to the model.
+const (
+	translationTimeout = 10 * time.Second
+	// Several sentences or a conjugation table take longer to write than a single translation
+	generationTimeout = 30 * time.Second
+)
+
+// ModelClient implements LLMClient on top of any langchaingo model. Every operation asks the model
+// for JSON and validates it, asking again when the model gets the format wrong.
+type ModelClient struct {
+	llm       llms.Model
+	model     string
+	languages *LanguageRegistry
 }
 
-// NewOpenAIClient creates a new OpenAI client with the provided API key
-func NewOpenAIClient(apiKey string) (*OpenAIClient, error) {
-	if apiKey == "" {
-		return nil, errors.New("OpenAI API key is required")
+// NewModelClient wraps a model, recorded under the given name in the AI provenance of flashcards.
+// The language registry names the languages in prompts. NewLLMClient creates the model from
+// configuration.
+func NewModelClient(llm llms.Model, model string, languages *LanguageRegistry) (*ModelClient, error) {
+	if llm == nil {
+		return nil, errors.New("model is required")
 	}
+	if languages == nil {
+		return nil, errors.New("language registry is required")
+	}
+
+	return &ModelClient{llm: llm, model: model, languages: languages}, nil
+}
 
-	llm, err := openai.New(openai.WithToken(apiKey))
+// Model returns the name of the model.
+func (c *ModelClient) Model() string {
+	return c.model
+}
+
+// Translate translates text from source language to target language
+func (c *ModelClient) Translate(ctx context.Context, text, sourceLang, targetLang string) (*models.Translation, error) {
+	if c.llm == nil {
+		return nil, errors.New("LLM client not initialized")
+	}
+
+	prompt := translationPrompt(c.languages, text, sourceLang, targetLang)
+
+	translation, err := generateJSON(ctx, c.llm, prompt, translationTimeout, func(t *models.Translation) error {
+		return cleanTranslation(t, text)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create OpenAI client: %w", err)
+		return nil, fmt.Errorf("translation failed: %w", err)
 	}
 
-	return &OpenAIClient{llm: llm}, nil
+	return translation, nil
 }
 
-// Translate translates text from source language to target language
-func (c *OpenAIClient) Translate(ctx context.Context, text, sourceLang, targetLang string) (string, error) {
+// translationCandidates is the JSON object a model lists candidate translations in.
+type translationCandidates struct {
+	Candidates []models.TranslationCandidate `json:"candidates"`
+}
+
+// TranslateCandidates asks the model for several translations of an ambiguous text.
+func (c *ModelClient) TranslateCandidates(ctx context.Context, text, sourceLang, targetLang string, n int) ([]models.TranslationCandidate, error) {
 	if c.llm == nil {
-		return "", errors.New("LLM client not initialized")
+		return nil, errors.New("LLM client not initialized")
 	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
+	prompt := candidatesPrompt(c.languages, text, sourceLang, targetLang, n)
 
-	// Map language codes to full names for better prompt clarity
-	langMap := map[string]string{
-		"en": "English",
-		"el": "Greek",
+	generated, err := generateJSON(ctx, c.llm, prompt, generationTimeout, func(t *translationCandidates) error {
+		candidates, err := cleanTranslationCandidates(t.Candidates, text, n)
+		t.Candidates = candidates
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("translation failed: %w", err)
 	}
 
-	sourceLanguage := langMap[sourceLang]
-	if sourceLanguage == "" {
-		sourceLanguage = sourceLang
+	return generated.Candidates, nil
+}
+
+// exampleSentences is the JSON object a model writes example sentences in.
+type exampleSentences struct {
+	Examples []models.ExampleSentence `json:"examples"`
+}
+
+// GenerateExamples asks the model for example sentences and returns the ones it wrote correctly,
+// easiest first.
+func (c *ModelClient) GenerateExamples(ctx context.Context, word, lang, translationLang string, n int) ([]models.ExampleSentence, error) {
+	if c.llm == nil {
+		return nil, errors.New("LLM client not initialized")
 	}
 
-	targetLanguage := langMap[targetLang]
-	if targetLanguage == "" {
-		targetLanguage = targetLang
+	prompt := examplesPrompt(c.languages, word, lang, translationLang, n)
+
+	generated, err := generateJSON(ctx, c.llm, prompt, generationTimeout, func(e *exampleSentences) error {
+		examples, err := cleanExampleSentences(e.Examples, n)
+		e.Examples = examples
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("example generation failed: %w", err)
 	}
 
-	prompt := fmt.Sprintf(
-		"Translate the following text from %s to %s. Provide ONLY the translation, no explanations or additional text.\n\nText: %s",
-		sourceLanguage,
-		targetLanguage,
-		text,
+	return generated.Examples, nil
+}
+
+// examplesPrompt asks for n example sentences in a JSON object, one per CEFR level where possible.
+func examplesPrompt(languages *LanguageRegistry, word, lang, translationLang string, n int) string {
+	instructions := fmt.Sprintf(
+		"Write %d example sentences in %s that use the word or phrase below, each with its translation into %s. "+
+			"Grade them by difficulty from easiest to hardest and label each with its CEFR level (%s). "+
+			"Use natural, everyday language.",
+		n,
+		languages.PromptName(lang),
+		languages.PromptName(translationLang),
+		strings.Join(models.CEFRLevels, ", "),
 	)
+	if language, ok := languages.Lookup(lang); ok && language.DiacriticsSignificant {
+		instructions += " Write every accent and diacritic correctly, as they change the meaning of words."
+	}
+	instructions += ` Respond ONLY with JSON of the form {"examples": [{"sentence": "...", "translation": "...", "level": "A1"}]}.`
+
+	return instructions + "\n\nWord: " + word
+}
+
+// cleanExampleSentences drops incomplete example sentences and unknown levels, and orders the rest
+// from easiest to hardest. At most n are returned.
+func cleanExampleSentences(sentences []models.ExampleSentence, n int) ([]models.ExampleSentence, error) {
+	var examples []models.ExampleSentence
+	for _, example := range sentences {
+		example.Sentence = strings.TrimSpace(example.Sentence)
+		example.Translation = strings.TrimSpace(example.Translation)
+		example.Level = strings.ToUpper(strings.TrimSpace(example.Level))
+		if example.Sentence == "" || example.Translation == "" {
+			continue
+		}
+		if !slices.Contains(models.CEFRLevels, example.Level) {
+			example.Level = ""
+		}
+		examples = append(examples, example)
+	}
+	if len(examples) == 0 {
+		return nil, errors.New("no example sentences")
+	}
 
-	response, err := llms.GenerateFromSinglePrompt(ctx, c.llm, prompt)
+	// Unlabelled sentences keep their place after the labelled ones
+	slices.SortStableFunc(examples, func(a, b models.ExampleSentence) int {
+		return cefrRank(a.Level) - cefrRank(b.Level)
+	})
+	if len(examples) > n {
+		examples = examples[:n]
+	}
+	return examples, nil
+}
+
+// cefrRank orders CEFR levels from easiest to hardest, with unknown levels last.
+func cefrRank(level string) int {
+	if i := slices.Index(models.CEFRLevels, level); i >= 0 {
+		return i
+	}
+	return len(models.CEFRLevels)
+}
+
+// ExtractGrammar asks the model for the grammar of a word. Grammar that does not match the schema is
+// asked for again, so the result is valid.
+func (c *ModelClient) ExtractGrammar(ctx context.Context, word, lang string) (*models.Grammar, error) {
+	if c.llm == nil {
+		return nil, errors.New("LLM client not initialized")
+	}
+
+	prompt := grammarPrompt(c.languages, word, lang)
+
+	grammar, err := generateJSON(ctx, c.llm, prompt, generationTimeout, validateGrammar)
 	if err != nil {
-		return "", fmt.Errorf("translation failed: %w", err)
+		return nil, fmt.Errorf("grammar extraction failed: %w", err)
 	}
 
-	return response, nil
+	return grammar, nil
+}
+
+// grammarPrompt describes the grammar JSON expected for a word, with an example of each part of
+// speech.
+func grammarPrompt(languages *LanguageRegistry, word, lang string) string {
+	return fmt.Sprintf(
+		"Describe the grammar of the following %s word or phrase. Use monotonic spelling with every accent written correctly. "+
+			"Respond ONLY with JSON in one of these forms.\n\n"+
+			`For a noun: {"part_of_speech": "noun", "lemma": "δρόμος", "noun": {"article": "ο", "gender": "masculine", `+
+			`"genitive_singular": "δρόμου", "nominative_plural": "δρόμοι", "genitive_plural": "δρόμων"}}`+"\n"+
+			`For a verb: {"part_of_speech": "verb", "lemma": "γράφω", "verb": {"present": "γράφω", "aorist": "έγραψα", `+
+			`"future": "θα γράψω", "passive_aorist": "γράφτηκα", "passive_participle": "γραμμένος", "conjugation": {`+
+			`"present": {"1sg": "γράφω", "2sg": "γράφεις", "3sg": "γράφει", "1pl": "γράφουμε", "2pl": "γράφετε", "3pl": "γράφουν"}, `+
+			`"imperfect": {...}, "aorist": {...}, "future": {...}}}}`+"\n"+
+			`For any other word: {"part_of_speech": "other", "lemma": "..."}`+"\n\n"+
+			"Gender is masculine, feminine or neuter. Omit plural forms for nouns without a plural and the passive forms for verbs without a passive. "+
+			"Give the conjugation of the active voice in the %s tenses.\n\nWord: %s",
+		languages.PromptName(lang),
+		strings.Join(models.GrammarTenses, ", "),
+		word,
+	)
+}
+
+// translationPrompt asks for a translation with alternatives as JSON, naming the languages in full for
+// better prompt clarity.
+func translationPrompt(languages *LanguageRegistry, text, sourceLang, targetLang string) string {
+	instructions := fmt.Sprintf(
+		"Translate the following text from %s to %s.",
+		languages.PromptName(sourceLang),
+		languages.PromptName(targetLang),
+	)
+	if target, ok := languages.Lookup(targetLang); ok && target.DiacriticsSignificant {
+		instructions += " Write every accent and diacritic correctly, as they change the meaning of words."
+	}
+	instructions += fmt.Sprintf(
+		` Respond ONLY with JSON of the form {"translation": "...", "alternatives": ["..."], "part_of_speech": "...", "confidence": 0.9}. `+
+			`"translation" is the best translation alone, without quotes or explanations, and "alternatives" are other valid translations, best first, or empty. `+
+			`"part_of_speech" is one of %s, using "phrase" for several words, and "confidence" is how sure you are, from 0 to 1.`,
+		strings.Join(models.TranslationPartsOfSpeech, ", "),
+	)
+
+	return instructions + "\n\nText: " + text
+}
+
+// candidatesPrompt asks for up to n translations covering the different meanings and registers of a
+// text.
+func candidatesPrompt(languages *LanguageRegistry, text, sourceLang, targetLang string, n int) string {
+	instructions := fmt.Sprintf(
+		"List up to %d different translations of the following text from %s to %s, best first. "+
+			"When the text has several meanings, such as a noun and a verb, cover each of them. "+
+			"For each translation give a short usage note in %s explaining the meaning or context it is for.",
+		n,
+		languages.PromptName(sourceLang),
+		languages.PromptName(targetLang),
+		languages.PromptName(sourceLang),
+	)
+	if target, ok := languages.Lookup(targetLang); ok && target.DiacriticsSignificant {
+		instructions += " Write every accent and diacritic correctly, as they change the meaning of words."
+	}
+	instructions += fmt.Sprintf(
+		` Respond ONLY with JSON of the form {"candidates": [{"translation": "...", "part_of_speech": "...", "register": "...", "usage": "..."}]}. `+
+			`"translation" is the translation alone, without quotes or explanations, "part_of_speech" is one of %s and "register" is one of %s.`,
+		strings.Join(models.TranslationPartsOfSpeech, ", "),
+		strings.Join(models.TranslationRegisters, ", "),
+	)
+
+	return instructions + "\n\nText: " + text
 }
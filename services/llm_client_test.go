@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akolybelnikov/flashcards/models"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// scriptedModel is an llms.Model that answers with its responses in turn and records the prompts and
+// last messages it was given.
+type scriptedModel struct {
+	responses []string
+	err       error
+	prompts   []string
+	messages  []llms.MessageContent
+}
+
+func (m *scriptedModel) GenerateContent(_ context.Context, messages []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	m.prompts = append(m.prompts, messages[0].Parts[0].(llms.TextContent).Text)
+	m.messages = messages
+	if m.err != nil {
+		return nil, m.err
+	}
+	response := m.responses[0]
+	m.responses = m.responses[1:]
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: response}}}, nil
+}
+
+func (m *scriptedModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func testModelClient(t *testing.T, model llms.Model) *ModelClient {
+	return &ModelClient{llm: model, languages: testLanguages(t)}
+}
+
+func TestTranslateRetriesMalformedOutput(t *testing.T) {
+	model := &scriptedModel{responses: []string{
+		`Sure! The translation is "γεια σας".`,
+		`{"translation": "\"Γεια σας.\"", "alternatives": ["γεια", "Γεια σας", ""], "part_of_speech": "Interjection", "confidence": 0.9}`,
+	}}
+
+	translation, err := testModelClient(t, model).Translate(context.Background(), "hello", "en", "el")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(model.prompts) != 2 || !strings.Contains(model.prompts[1], "Your previous response was rejected: invalid JSON") {
+		t.Fatalf("expected a second prompt explaining the rejection, got %q", model.prompts)
+	}
+	if translation.Translation != "Γεια σας" || translation.PartOfSpeech != "interjection" || translation.Confidence != 0.9 {
+		t.Errorf("expected a sanitised translation, got %+v", translation)
+	}
+	if len(translation.Alternatives) != 1 || translation.Alternatives[0] != "γεια" {
+		t.Errorf("expected only the distinct alternative, got %q", translation.Alternatives)
+	}
+}
+
+func TestTranslateGivesUpAfterMaxAttempts(t *testing.T) {
+	model := &scriptedModel{responses: []string{
+		`{"translation": ""}`,
+		`{"translation": "γεια", "confidence": 7}`,
+		`{"translation": "γεια"} {"translation": "γεια σου"}`,
+	}}
+
+	_, err := testModelClient(t, model).Translate(context.Background(), "hi", "en", "el")
+	if !errors.Is(err, ErrMalformedLLMOutput) {
+		t.Fatalf("expected ErrMalformedLLMOutput, got %v", err)
+	}
+	if len(model.prompts) != maxLLMAttempts {
+		t.Errorf("expected %d attempts, got %d", maxLLMAttempts, len(model.prompts))
+	}
+}
+
+func TestTranslateDoesNotRetryModelErrors(t *testing.T) {
+	model := &scriptedModel{err: errors.New("rate limited")}
+
+	if _, err := testModelClient(t, model).Translate(context.Background(), "hi", "en", "el"); err == nil {
+		t.Fatalf("expected the model error")
+	}
+	if len(model.prompts) != 1 {
+		t.Errorf("expected a single attempt, got %d", len(model.prompts))
+	}
+}
+
+func TestExtractGrammarRetriesInvalidGrammar(t *testing.T) {
+	model := &scriptedModel{responses: []string{
+		`{"part_of_speech": "noun", "lemma": "δρόμος", "declension": "second"}`,
+		`{"part_of_speech": "noun", "lemma": "δρόμος", "noun": {"article": "η", "gender": "masculine", "genitive_singular": "δρόμου"}}`,
+		"```json\n" + `{"part_of_speech": "noun", "lemma": "δρόμος", "noun": {"article": "ο", "gender": "masculine", "genitive_singular": "δρόμου"}}` + "\n```",
+	}}
+
+	grammar, err := testModelClient(t, model).ExtractGrammar(context.Background(), "δρόμος", "el")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if grammar.Noun == nil || grammar.Noun.Article != "ο" {
+		t.Fatalf("unexpected grammar: %+v", grammar)
+	}
+	if !strings.Contains(model.prompts[2], "does not match gender") {
+		t.Errorf("expected the last prompt to explain the invalid grammar, got %q", model.prompts[2])
+	}
+}
+
+func TestExamplesPromptUsesRegistry(t *testing.T) {
+	prompt := examplesPrompt(testLanguages(t), "σπίτι", "el", "en", 3)
+
+	if !strings.Contains(prompt, "3 example sentences in Greek (Ελληνικά)") || !strings.Contains(prompt, "translation into English") {
+		t.Errorf("expected count and language names in prompt, got %q", prompt)
+	}
+	if !strings.Contains(prompt, "diacritic") {
+		t.Errorf("expected a diacritics instruction for Greek, got %q", prompt)
+	}
+	if !strings.HasSuffix(prompt, "Word: σπίτι") {
+		t.Errorf("expected prompt to end with the word, got %q", prompt)
+	}
+}
+
+func TestCleanExampleSentences(t *testing.T) {
+	sentences := []models.ExampleSentence{
+		{Sentence: "Μένω σε ένα μεγάλο σπίτι με κήπο.", Translation: "I live in a big house with a garden.", Level: "b1"},
+		{Sentence: " Το σπίτι μου. ", Translation: "My house.", Level: "A1"},
+		{Sentence: "", Translation: "Missing sentence.", Level: "A2"},
+		{Sentence: "Πάμε σπίτι;", Translation: "Shall we go home?", Level: "beginner"},
+	}
+
+	examples, err := cleanExampleSentences(sentences, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(examples) != 2 {
+		t.Fatalf("expected 2 examples, got %+v", examples)
+	}
+	if examples[0].Sentence != "Το σπίτι μου." || examples[0].Level != "A1" {
+		t.Errorf("expected the trimmed A1 sentence first, got %+v", examples[0])
+	}
+	if examples[1].Level != "B1" {
+		t.Errorf("expected the B1 sentence second, got %+v", examples[1])
+	}
+
+	if _, err := cleanExampleSentences(nil, 3); err == nil {
+		t.Errorf("expected an error when no sentences are returned")
+	}
+}
+
+func TestTranslateCandidatesCleansOutput(t *testing.T) {
+	model := &scriptedModel{responses: []string{
+		`{"candidates": [{"translation": "βιβλίο", "register": "archaic"}]}`,
+		`{"candidates": [
+			{"translation": "«βιβλίο»", "part_of_speech": "Noun", "register": "neutral", "usage": "a book  to read"},
+			{"translation": "Βιβλίο", "part_of_speech": "noun"},
+			{"translation": "κλείνω", "part_of_speech": "verb", "usage": "to book a table"},
+			{"translation": "κρατώ", "part_of_speech": "verb", "usage": "to reserve"}
+		]}`,
+	}}
+
+	candidates, err := testModelClient(t, model).TranslateCandidates(context.Background(), "book", "en", "el", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(model.prompts[1], `unknown register "archaic"`) {
+		t.Errorf("expected the unknown register to be retried, got %q", model.prompts)
+	}
+	if len(candidates) != 2 || candidates[0].Translation != "βιβλίο" || candidates[1].Translation != "κλείνω" {
+		t.Fatalf("expected two distinct sanitised candidates, got %+v", candidates)
+	}
+	if candidates[0].PartOfSpeech != "noun" || candidates[0].Usage != "a book to read" {
+		t.Errorf("expected normalised notes, got %+v", candidates[0])
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// maxLLMAttempts is how many times a model is asked for JSON before its output is given up on.
+const maxLLMAttempts = 3
+
+// ErrMalformedLLMOutput is returned when a model keeps answering with JSON that does not match the
+// requested schema.
+var ErrMalformedLLMOutput = errors.New("language model returned malformed output")
+
+// generateJSON prompts a model for a JSON object, decodes it into T and validates it. The validate
+// function may also clean up the result. Malformed or invalid output is retried up to maxLLMAttempts
+// times, telling the model what was wrong; errors calling the model are returned as they are. Each
+// attempt gets its own timeout.
+func generateJSON[T any](ctx context.Context, model llms.Model, prompt string, timeout time.Duration, validate func(*T) error) (*T, error) {
+	var rejection error
+	for attempt := 1; attempt <= maxLLMAttempts; attempt++ {
+		request := prompt
+		if rejection != nil {
+			request += fmt.Sprintf("\n\nYour previous response was rejected: %v. Respond again with ONLY JSON in the requested form.", rejection)
+		}
+
+		response, err := generateWithTimeout(ctx, model, request, timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := decodeLLMJSON[T](response)
+		if err == nil {
+			err = validate(result)
+		}
+		if err == nil {
+			return result, nil
+		}
+		rejection = err
+	}
+	return nil, fmt.Errorf("%w after %d attempts: %v", ErrMalformedLLMOutput, maxLLMAttempts, rejection)
+}
+
+func generateWithTimeout(ctx context.Context, model llms.Model, prompt string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return llms.GenerateFromSinglePrompt(ctx, model, prompt, llms.WithJSONMode())
+}
+
+// decodeLLMJSON decodes a single JSON object from a model response. Fields outside the schema of T
+// are ignored, since models often add their own; the validate function of generateJSON checks the
+// fields that matter. A Markdown code fence around the object is ignored.
+func decodeLLMJSON[T any](response string) (*T, error) {
+	response = strings.TrimSpace(response)
+	response = strings.TrimPrefix(response, "```json")
+	response = strings.TrimPrefix(response, "```")
+	response = strings.TrimSuffix(response, "```")
+
+	decoder := json.NewDecoder(strings.NewReader(response))
+
+	var result T
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if decoder.More() {
+		return nil, errors.New("invalid JSON: unexpected content after the object")
+	}
+	return &result, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/anthropic"
+	"github.com/tmc/langchaingo/llms/ollama"
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+// Language model providers supported by NewLLMClient.
+const (
+	OpenAIProvider    = "openai"
+	AnthropicProvider = "anthropic"
+	OllamaProvider    = "ollama"
+)
+
+// defaultLLMModels names the model used with each provider when none is configured.
+var defaultLLMModels = map[string]string{
+	OpenAIProvider:    "gpt-3.5-turbo",
+	AnthropicProvider: "claude-3-5-haiku-latest",
+	OllamaProvider:    "llama3.1",
+}
+
+// LLMConfig selects the language model behind the LLM client.
+type LLMConfig struct {
+	Provider string // OpenAIProvider, AnthropicProvider or OllamaProvider
+	Model    string // defaults to the provider's default model
+	// BaseURL replaces the provider's endpoint. With OpenAI it can point at any OpenAI-compatible
+	// server, such as llama.cpp or vLLM; with Ollama it is the server, http://localhost:11434 by
+	// default.
+	BaseURL string
+	APIKey  string // required by the OpenAI and Anthropic APIs, not by local servers
+}
+
+// NewLLMClient creates the LLM client of the configured provider.
+func NewLLMClient(config LLMConfig, languages *LanguageRegistry) (LLMClient, error) {
+	// Checked here as the Ollama client exits on a URL it cannot parse
+	if config.BaseURL != "" {
+		if u, err := url.Parse(config.BaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid LLM base URL %q", config.BaseURL)
+		}
+	}
+
+	model := config.Model
+	if model == "" {
+		model = defaultLLMModels[config.Provider]
+	}
+
+	var llm llms.Model
+	var err error
+	switch config.Provider {
+	case OpenAIProvider:
+		llm, err = newOpenAIModel(config, model)
+	case AnthropicProvider:
+		llm, err = newAnthropicModel(config, model)
+	case OllamaProvider:
+		llm, err = newOllamaModel(config, model)
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q", config.Provider)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s client: %w", config.Provider, err)
+	}
+
+	return NewModelClient(llm, model, languages)
+}
+
+func newOpenAIModel(config LLMConfig, model string) (llms.Model, error) {
+	apiKey := config.APIKey
+	if apiKey == "" {
+		if config.BaseURL == "" {
+			return nil, errors.New("API key is required")
+		}
+		// Compatible servers do not check the key, but the client will not start without one
+		apiKey = "unused"
+	}
+
+	opts := []openai.Option{openai.WithToken(apiKey), openai.WithModel(model)}
+	if config.BaseURL != "" {
+		opts = append(opts, openai.WithBaseURL(config.BaseURL))
+	}
+	return openai.New(opts...)
+}
+
+func newAnthropicModel(config LLMConfig, model string) (llms.Model, error) {
+	if config.APIKey == "" {
+		return nil, errors.New("API key is required")
+	}
+
+	opts := []anthropic.Option{anthropic.WithToken(config.APIKey), anthropic.WithModel(model)}
+	if config.BaseURL != "" {
+		opts = append(opts, anthropic.WithBaseURL(config.BaseURL))
+	}
+	llm, err := anthropic.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return prefilledJSONModel{llm}, nil
+}
+
+// prefilledJSONModel makes up for a model without a JSON mode, such as Anthropic's. When JSON is
+// requested, the model's reply is started with the opening brace of an object, so the model goes on
+// writing the object instead of prose around it.
+type prefilledJSONModel struct {
+	llms.Model
+}
+
+func (m prefilledJSONModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	var opts llms.CallOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	if !opts.JSONMode {
+		return m.Model.GenerateContent(ctx, messages, options...)
+	}
+
+	messages = append(slices.Clip(messages), llms.TextParts(llms.ChatMessageTypeAI, "{"))
+	response, err := m.Model.GenerateContent(ctx, messages, options...)
+	if err != nil {
+		return nil, err
+	}
+	for _, choice := range response.Choices {
+		choice.Content = "{" + choice.Content
+	}
+	return response, nil
+}
+
+func (m prefilledJSONModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func newOllamaModel(config LLMConfig, model string) (llms.Model, error) {
+	opts := []ollama.Option{ollama.WithModel(model)}
+	if config.BaseURL != "" {
+		opts = append(opts, ollama.WithServerURL(config.BaseURL))
+	}
+	return ollama.New(opts...)
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// stubTranslation is the JSON translation every stub server answers with.
+const stubTranslation = `{"translation": "σπίτι", "alternatives": [], "part_of_speech": "noun", "confidence": 0.9}`
+
+// stubProviderServer serves the given response body on path, recording the model named in each
+// request.
+func stubProviderServer(t *testing.T, path, body string) (*httptest.Server, *string) {
+	t.Helper()
+	var model string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != path {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		var request struct {
+			Model string `json:"model"`
+		}
+		if err := json.Unmarshal(data, &request); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		model = request.Model
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &model
+}
+
+// jsonString quotes s as a JSON string.
+func jsonString(t *testing.T, s string) string {
+	t.Helper()
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestNewLLMClientProviders(t *testing.T) {
+	content := jsonString(t, stubTranslation)
+	tests := []struct {
+		provider string
+		path     string
+		body     string
+		apiKey   string
+	}{
+		{
+			provider: OpenAIProvider,
+			path:     "/chat/completions",
+			body: `{"id": "1", "object": "chat.completion", "model": "local-model", "choices": [` +
+				`{"index": 0, "message": {"role": "assistant", "content": ` + content + `}, "finish_reason": "stop"}]}`,
+		},
+		{
+			provider: AnthropicProvider,
+			path:     "/messages",
+			// The reply is prefilled with the opening brace, so the model only writes the rest
+			body: `{"id": "1", "type": "message", "role": "assistant", "model": "local-model", ` +
+				`"content": [{"type": "text", "text": ` + jsonString(t, strings.TrimPrefix(stubTranslation, "{")) + `}], ` +
+				`"stop_reason": "end_turn", "usage": {"input_tokens": 1, "output_tokens": 1}}`,
+			apiKey: "test-key",
+		},
+		{
+			provider: OllamaProvider,
+			path:     "/api/chat",
+			body:     `{"model": "local-model", "message": {"role": "assistant", "content": ` + content + `}, "done": true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			server, model := stubProviderServer(t, tt.path, tt.body)
+
+			client, err := NewLLMClient(LLMConfig{
+				Provider: tt.provider,
+				Model:    "local-model",
+				BaseURL:  server.URL,
+				APIKey:   tt.apiKey,
+			}, testLanguages(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if client.Model() != "local-model" {
+				t.Errorf("expected model local-model, got %q", client.Model())
+			}
+
+			translation, err := client.Translate(context.Background(), "house", "en", "el")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if translation.Translation != "σπίτι" {
+				t.Errorf("expected σπίτι, got %+v", translation)
+			}
+			if *model != "local-model" {
+				t.Errorf("expected the configured model to be requested, got %q", *model)
+			}
+		})
+	}
+}
+
+func TestNewLLMClientDefaultModel(t *testing.T) {
+	client, err := NewLLMClient(LLMConfig{Provider: OllamaProvider}, testLanguages(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.Model() != defaultLLMModels[OllamaProvider] {
+		t.Errorf("expected the default model, got %q", client.Model())
+	}
+}
+
+func TestNewLLMClientErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config LLMConfig
+		want   string
+	}{
+		{"unknown provider", LLMConfig{Provider: "llamafile"}, "unknown LLM provider"},
+		{"OpenAI without key", LLMConfig{Provider: OpenAIProvider}, "API key is required"},
+		{"Anthropic without key", LLMConfig{Provider: AnthropicProvider, BaseURL: "http://localhost:8080"}, "API key is required"},
+		{"invalid base URL", LLMConfig{Provider: OllamaProvider, BaseURL: "localhost:11434"}, "invalid LLM base URL"},
+	}
+	for _, tt := range tests {
+		if _, err := NewLLMClient(tt.config, testLanguages(t)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestPrefilledJSONModel(t *testing.T) {
+	model := &scriptedModel{responses: []string{`"translation": "γεια"}`, "hello"}}
+	llm := prefilledJSONModel{model}
+
+	response, err := llms.GenerateFromSinglePrompt(context.Background(), llm, "translate", llms.WithJSONMode())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response != `{"translation": "γεια"}` {
+		t.Errorf("expected the prefilled brace to be restored, got %q", response)
+	}
+	last := model.messages[len(model.messages)-1]
+	if last.Role != llms.ChatMessageTypeAI || last.Parts[0].(llms.TextContent).Text != "{" {
+		t.Errorf("expected the reply to be prefilled, got %+v", last)
+	}
+
+	// Without JSON mode the prompt is sent as it is
+	if response, err := llms.GenerateFromSinglePrompt(context.Background(), llm, "greet"); err != nil || response != "hello" {
+		t.Fatalf("expected the plain response, got %q, %v", response, err)
+	}
+	if len(model.messages) != 1 {
+		t.Errorf("expected no prefill without JSON mode, got %+v", model.messages)
+	}
+}
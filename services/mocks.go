@@ -1,39 +1,98 @@
 package services
 
-import "context"
+import (
+	"context"
+	"fmt"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
 
 // MockLLMClient is a mock implementation of LLMClient for testing
 type MockLLMClient struct {
-	TranslateFunc func(ctx context.Context, text, sourceLang, targetLang string) (string, error)
+	ModelName               string
+	TranslateFunc           func(ctx context.Context, text, sourceLang, targetLang string) (*models.Translation, error)
+	TranslateCandidatesFunc func(ctx context.Context, text, sourceLang, targetLang string, n int) ([]models.TranslationCandidate, error)
+	GenerateExamplesFunc    func(ctx context.Context, word, lang, translationLang string, n int) ([]models.ExampleSentence, error)
+	ExtractGrammarFunc      func(ctx context.Context, word, lang string) (*models.Grammar, error)
+}
+
+func (m *MockLLMClient) Model() string {
+	if m.ModelName != "" {
+		return m.ModelName
+	}
+	return "mock"
 }
 
-func (m *MockLLMClient) Translate(ctx context.Context, text, sourceLang, targetLang string) (string, error) {
+func (m *MockLLMClient) Translate(ctx context.Context, text, sourceLang, targetLang string) (*models.Translation, error) {
 	if m.TranslateFunc != nil {
 		return m.TranslateFunc(ctx, text, sourceLang, targetLang)
 	}
 
 	// Default mock behavior - simple translations
+	return &models.Translation{Translation: mockTranslation(text, sourceLang, targetLang), Confidence: 1}, nil
+}
+
+func (m *MockLLMClient) TranslateCandidates(ctx context.Context, text, sourceLang, targetLang string, n int) ([]models.TranslationCandidate, error) {
+	if m.TranslateCandidatesFunc != nil {
+		return m.TranslateCandidatesFunc(ctx, text, sourceLang, targetLang, n)
+	}
+
+	// Default mock behavior - the simple translation followed by numbered variants
+	candidates := []models.TranslationCandidate{{Translation: mockTranslation(text, sourceLang, targetLang), Register: "neutral"}}
+	for i := 2; i <= n; i++ {
+		candidates = append(candidates, models.TranslationCandidate{Translation: fmt.Sprintf("%s %d", candidates[0].Translation, i)})
+	}
+	return candidates, nil
+}
+
+func mockTranslation(text, sourceLang, targetLang string) string {
 	if sourceLang == "en" && targetLang == "el" {
 		switch text {
 		case "hello":
-			return "γεια σας", nil
+			return "γεια σας"
 		case "goodbye":
-			return "αντίο", nil
+			return "αντίο"
 		default:
-			return "μετάφραση", nil // "translation" in Greek
+			return "μετάφραση" // "translation" in Greek
 		}
 	}
 
 	if sourceLang == "el" && targetLang == "en" {
 		switch text {
 		case "γεια σας":
-			return "hello", nil
+			return "hello"
 		case "αντίο":
-			return "goodbye", nil
+			return "goodbye"
 		default:
-			return "translation", nil
+			return "translation"
 		}
 	}
 
-	return text + " (translated)", nil
+	return text + " (translated)"
+}
+
+func (m *MockLLMClient) GenerateExamples(ctx context.Context, word, lang, translationLang string, n int) ([]models.ExampleSentence, error) {
+	if m.GenerateExamplesFunc != nil {
+		return m.GenerateExamplesFunc(ctx, word, lang, translationLang, n)
+	}
+
+	// Default mock behavior - numbered sentences at increasing levels
+	examples := make([]models.ExampleSentence, n)
+	for i := range examples {
+		examples[i] = models.ExampleSentence{
+			Sentence:    fmt.Sprintf("%s (%s example %d)", word, lang, i+1),
+			Translation: fmt.Sprintf("%s example %d", translationLang, i+1),
+			Level:       models.CEFRLevels[min(i, len(models.CEFRLevels)-1)],
+		}
+	}
+	return examples, nil
+}
+
+func (m *MockLLMClient) ExtractGrammar(ctx context.Context, word, lang string) (*models.Grammar, error) {
+	if m.ExtractGrammarFunc != nil {
+		return m.ExtractGrammarFunc(ctx, word, lang)
+	}
+
+	// Default mock behavior - a word without grammar data
+	return &models.Grammar{PartOfSpeech: models.PartOfSpeechOther, Lemma: word}, nil
 }
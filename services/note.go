@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// MaxNoteFields is the largest number of fields a note type may define.
+const MaxNoteFields = 20
+
+// noteFieldName matches field names that templates can refer to as {{.name}}.
+var noteFieldName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var (
+	// ErrInvalidNoteType is returned for note types with invalid fields or templates.
+	ErrInvalidNoteType = errors.New("invalid note type")
+	// ErrInvalidNoteFields is returned for field maps that do not match their note type.
+	ErrInvalidNoteFields = errors.New("invalid note fields")
+)
+
+// RenderNote renders the question and answer of a note's card from its field values. Fields the note
+// leaves out render as empty text.
+func RenderNote(content *models.NoteContent) (string, string, error) {
+	question, err := executeNoteTemplate("front", content.FrontTemplate, content.Fields)
+	if err != nil {
+		return "", "", err
+	}
+	answer, err := executeNoteTemplate("back", content.BackTemplate, content.Fields)
+	if err != nil {
+		return "", "", err
+	}
+	return question, answer, nil
+}
+
+// MaxRenderedNoteLength is the largest number of bytes a note template may render.
+const MaxRenderedNoteLength = 64 << 10
+
+// errNoteTooLong is returned when a template renders more than MaxRenderedNoteLength bytes.
+var errNoteTooLong = fmt.Errorf("rendered card is longer than %d bytes", MaxRenderedNoteLength)
+
+// parseNoteTemplate parses a note template and checks that it only uses text, fields and if actions,
+// so that rendering it takes time proportional to its length.
+func parseNoteTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNoteNode(tmpl.Tree.Root, nil); err != nil {
+		return nil, fmt.Errorf("template: %s: %v", name, err)
+	}
+	return tmpl, nil
+}
+
+func executeNoteTemplate(name, text string, fields map[string]string) (string, error) {
+	tmpl, err := parseNoteTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&limitedWriter{w: &b, remaining: MaxRenderedNoteLength}, fields); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// limitedWriter fails writes once more than remaining bytes would have been written.
+type limitedWriter struct {
+	w         io.Writer
+	remaining int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > l.remaining {
+		return 0, errNoteTooLong
+	}
+	l.remaining -= len(p)
+	return l.w.Write(p)
+}
+
+// validateNoteType checks the fields and templates of a note type. Field names must be unique
+// identifiers, and templates may only refer to declared fields.
+func validateNoteType(fields []string, frontTemplate, backTemplate string) error {
+	if len(fields) == 0 || len(fields) > MaxNoteFields {
+		return fmt.Errorf("%w: a note type must have between 1 and %d fields", ErrInvalidNoteType, MaxNoteFields)
+	}
+
+	declared := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if !noteFieldName.MatchString(field) {
+			return fmt.Errorf("%w: field name %q must start with a letter or underscore and contain only letters, digits and underscores", ErrInvalidNoteType, field)
+		}
+		if declared[field] {
+			return fmt.Errorf("%w: field %q is declared twice", ErrInvalidNoteType, field)
+		}
+		declared[field] = true
+	}
+
+	for _, t := range []struct{ name, text string }{{"front", frontTemplate}, {"back", backTemplate}} {
+		if strings.TrimSpace(t.text) == "" {
+			return fmt.Errorf("%w: %s template cannot be empty", ErrInvalidNoteType, t.name)
+		}
+		tmpl, err := parseNoteTemplate(t.name, t.text)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidNoteType, err)
+		}
+		var used []string
+		_ = checkNoteNode(tmpl.Tree.Root, &used)
+		for _, field := range used {
+			if !declared[field] {
+				return fmt.Errorf("%w: %s template refers to undeclared field %q", ErrInvalidNoteType, t.name, field)
+			}
+		}
+	}
+	return nil
+}
+
+// checkNoteNode checks that a template node only contains text, if actions and actions printing a
+// single field such as {{.word}}, and appends the fields it refers to to fields when it is not nil.
+// Loops, variables, functions and nested templates are rejected.
+func checkNoteNode(node parse.Node, fields *[]string) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkNoteNode(child, fields); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.TextNode:
+		return nil
+	case *parse.ActionNode:
+		return checkNoteNode(n.Pipe, fields)
+	case *parse.IfNode:
+		for _, child := range []parse.Node{n.Pipe, n.List, n.ElseList} {
+			if err := checkNoteNode(child, fields); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.PipeNode:
+		if len(n.Decl) > 0 || len(n.Cmds) != 1 || len(n.Cmds[0].Args) != 1 {
+			return fmt.Errorf("%q must refer to a single field such as {{.name}}", n.String())
+		}
+		field, ok := n.Cmds[0].Args[0].(*parse.FieldNode)
+		if !ok || len(field.Ident) != 1 {
+			return fmt.Errorf("%q must refer to a single field such as {{.name}}", n.String())
+		}
+		if fields != nil {
+			*fields = append(*fields, field.Ident[0])
+		}
+		return nil
+	default:
+		return fmt.Errorf("%q is not allowed; templates may only use fields and if actions", node.String())
+	}
+}
+
+// normalizeNoteFields trims field values and checks them against the fields of their note type.
+func normalizeNoteFields(fields map[string]string, noteType *models.NoteType) (map[string]string, error) {
+	declared := make(map[string]bool, len(noteType.Fields))
+	for _, field := range noteType.Fields {
+		declared[field] = true
+	}
+
+	normalized := make(map[string]string, len(fields))
+	empty := true
+	for name, value := range fields {
+		if !declared[name] {
+			return nil, fmt.Errorf("%w: note type %q has no field %q", ErrInvalidNoteFields, noteType.Name, name)
+		}
+		value = strings.TrimSpace(value)
+		if value != "" {
+			empty = false
+		}
+		normalized[name] = value
+	}
+
+	if empty {
+		return nil, fmt.Errorf("%w: at least one field must have a value", ErrInvalidNoteFields)
+	}
+	return normalized, nil
+}
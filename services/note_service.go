@@ -0,0 +1,220 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// ErrNoteCardEdit is returned when the question or answer of a card rendered from a note is edited
+// directly instead of through the note.
+var ErrNoteCardEdit = errors.New("note cards are edited through their note")
+
+// NoteServiceInterface defines the note type and note operations the handlers depend on.
+type NoteServiceInterface interface {
+	CreateNoteType(req *models.CreateNoteTypeRequest) (*models.NoteType, error)
+	GetAllNoteTypes() ([]*models.NoteType, error)
+	GetNoteType(id int) (*models.NoteType, error)
+	UpdateNoteType(id int, req *models.UpdateNoteTypeRequest) (*models.NoteType, error)
+	DeleteNoteType(id int) error
+	CreateNote(req *models.CreateNoteRequest) (*models.Note, error)
+	GetNote(id int) (*models.Note, error)
+	UpdateNote(id int, req *models.UpdateNoteRequest) (*models.Note, error)
+	DeleteNote(id int) error
+}
+
+type NoteService struct {
+	repo db.NoteRepository
+}
+
+func NewNoteService(repo db.NoteRepository) *NoteService {
+	if repo == nil {
+		panic("repository cannot be nil")
+	}
+	return &NoteService{repo: repo}
+}
+
+func (s *NoteService) CreateNoteType(req *models.CreateNoteTypeRequest) (*models.NoteType, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, errors.New("note type name cannot be empty")
+	}
+	if err := validateNoteType(req.Fields, req.FrontTemplate, req.BackTemplate); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateType(req)
+}
+
+func (s *NoteService) GetAllNoteTypes() ([]*models.NoteType, error) {
+	return s.repo.GetAllTypes()
+}
+
+func (s *NoteService) GetNoteType(id int) (*models.NoteType, error) {
+	return s.repo.GetTypeByID(id)
+}
+
+// UpdateNoteType changes the name or templates of a note type. New templates are applied to the
+// cards of all of its notes.
+func (s *NoteService) UpdateNoteType(id int, req *models.UpdateNoteTypeRequest) (*models.NoteType, error) {
+	if req.Name == nil && req.FrontTemplate == nil && req.BackTemplate == nil {
+		return nil, errors.New("at least one field must be provided for update")
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("note type name cannot be empty")
+		}
+		req.Name = &name
+	}
+
+	noteType, err := s.repo.GetTypeByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.FrontTemplate == nil && req.BackTemplate == nil {
+		return s.repo.UpdateType(id, req, nil)
+	}
+
+	content := models.NoteContent{FrontTemplate: noteType.FrontTemplate, BackTemplate: noteType.BackTemplate}
+	if req.FrontTemplate != nil {
+		content.FrontTemplate = *req.FrontTemplate
+	}
+	if req.BackTemplate != nil {
+		content.BackTemplate = *req.BackTemplate
+	}
+	if err := validateNoteType(noteType.Fields, content.FrontTemplate, content.BackTemplate); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.repo.GetByType(id)
+	if err != nil {
+		return nil, err
+	}
+	rendered := make([]models.RenderedNote, 0, len(notes))
+	for _, note := range notes {
+		content.Fields = note.Fields
+		question, answer, err := renderNoteCard(&content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: note %d: %v", ErrInvalidNoteType, note.ID, err)
+		}
+		rendered = append(rendered, models.RenderedNote{NoteID: note.ID, Question: question, Answer: answer})
+	}
+
+	return s.repo.UpdateType(id, req, rendered)
+}
+
+func (s *NoteService) DeleteNoteType(id int) error {
+	return s.repo.DeleteType(id)
+}
+
+// CreateNote validates a note's fields against its type and creates the note with its card.
+func (s *NoteService) CreateNote(req *models.CreateNoteRequest) (*models.Note, error) {
+	noteType, err := s.repo.GetTypeByID(req.NoteTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Fields, err = normalizeNoteFields(req.Fields, noteType); err != nil {
+		return nil, err
+	}
+	if err := canonicalizeLanguages(&req.QuestionLang, &req.AnswerLang); err != nil {
+		return nil, err
+	}
+	if req.Tags, err = NormalizeTags(req.Tags); err != nil {
+		return nil, err
+	}
+
+	question, answer, err := renderNoteCard(&models.NoteContent{
+		Fields:        req.Fields,
+		FrontTemplate: noteType.FrontTemplate,
+		BackTemplate:  noteType.BackTemplate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(req, question, answer)
+}
+
+func (s *NoteService) GetNote(id int) (*models.Note, error) {
+	note, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	renderNote(note.Card)
+	return note, nil
+}
+
+// UpdateNote replaces the field values or card tags of a note and re-renders its card.
+func (s *NoteService) UpdateNote(id int, req *models.UpdateNoteRequest) (*models.Note, error) {
+	if req.Fields == nil && req.Tags == nil {
+		return nil, errors.New("at least one field must be provided for update")
+	}
+
+	note, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	noteType, err := s.repo.GetTypeByID(note.NoteTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := note.Fields
+	if req.Fields != nil {
+		if req.Fields, err = normalizeNoteFields(req.Fields, noteType); err != nil {
+			return nil, err
+		}
+		fields = req.Fields
+	}
+	if req.Tags != nil {
+		tags, err := NormalizeTags(*req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		req.Tags = &tags
+	}
+
+	question, answer, err := renderNoteCard(&models.NoteContent{
+		Fields:        fields,
+		FrontTemplate: noteType.FrontTemplate,
+		BackTemplate:  noteType.BackTemplate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Update(id, req, question, answer)
+}
+
+func (s *NoteService) DeleteNote(id int) error {
+	return s.repo.Delete(id)
+}
+
+// renderNoteCard renders a note's card, which must have both a question and an answer.
+func renderNoteCard(content *models.NoteContent) (string, string, error) {
+	question, answer, err := RenderNote(content)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrInvalidNoteFields, err)
+	}
+	if question == "" || answer == "" {
+		return "", "", fmt.Errorf("%w: the card's front and back must not be empty", ErrInvalidNoteFields)
+	}
+	return question, answer, nil
+}
+
+// renderNote replaces the stored question and answer of a card created from a note with the note
+// rendered by its type's current templates. Templates are validated when they are saved, so a card
+// that fails to render keeps its stored text.
+func renderNote(flashcard *models.Flashcard) {
+	if flashcard == nil || flashcard.Note == nil {
+		return
+	}
+	if question, answer, err := RenderNote(flashcard.Note); err == nil {
+		flashcard.Question, flashcard.Answer = question, answer
+	}
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+var greekNoun = &models.NoteType{
+	ID:            1,
+	Name:          "Greek noun",
+	Fields:        []string{"word", "article", "meaning"},
+	FrontTemplate: "{{.meaning}}",
+	BackTemplate:  "{{.article}} {{.word}}",
+}
+
+// mockNoteRepo is an in-memory implementation of db.NoteRepository for tests.
+type mockNoteRepo struct {
+	notes    map[int]*models.Note
+	rendered []models.RenderedNote
+}
+
+func (m *mockNoteRepo) CreateType(req *models.CreateNoteTypeRequest) (*models.NoteType, error) {
+	return &models.NoteType{ID: 2, Name: req.Name, Fields: req.Fields, FrontTemplate: req.FrontTemplate, BackTemplate: req.BackTemplate}, nil
+}
+
+func (m *mockNoteRepo) GetAllTypes() ([]*models.NoteType, error) {
+	return []*models.NoteType{greekNoun}, nil
+}
+
+func (m *mockNoteRepo) GetTypeByID(id int) (*models.NoteType, error) {
+	if id != greekNoun.ID {
+		return nil, fmt.Errorf("note type with id %d not found", id)
+	}
+	return greekNoun, nil
+}
+
+func (m *mockNoteRepo) UpdateType(id int, req *models.UpdateNoteTypeRequest, notes []models.RenderedNote) (*models.NoteType, error) {
+	m.rendered = notes
+	return m.GetTypeByID(id)
+}
+
+func (m *mockNoteRepo) DeleteType(id int) error {
+	_, err := m.GetTypeByID(id)
+	return err
+}
+
+func (m *mockNoteRepo) Create(req *models.CreateNoteRequest, question, answer string) (*models.Note, error) {
+	note := &models.Note{
+		ID:         len(m.notes) + 1,
+		NoteTypeID: req.NoteTypeID,
+		Fields:     req.Fields,
+		Card:       &models.Flashcard{ID: 10, Question: question, Answer: answer, QuestionLang: req.QuestionLang, Tags: req.Tags},
+	}
+	if m.notes == nil {
+		m.notes = map[int]*models.Note{}
+	}
+	m.notes[note.ID] = note
+	return note, nil
+}
+
+func (m *mockNoteRepo) GetByID(id int) (*models.Note, error) {
+	note, ok := m.notes[id]
+	if !ok {
+		return nil, fmt.Errorf("note with id %d not found", id)
+	}
+	return note, nil
+}
+
+func (m *mockNoteRepo) GetByType(noteTypeID int) ([]*models.Note, error) {
+	var notes []*models.Note
+	for id := 1; id <= len(m.notes); id++ {
+		notes = append(notes, m.notes[id])
+	}
+	return notes, nil
+}
+
+func (m *mockNoteRepo) Update(id int, req *models.UpdateNoteRequest, question, answer string) (*models.Note, error) {
+	note, err := m.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Fields != nil {
+		note.Fields = req.Fields
+	}
+	note.Card.Question, note.Card.Answer = question, answer
+	return note, nil
+}
+
+func (m *mockNoteRepo) Delete(id int) error {
+	_, err := m.GetByID(id)
+	return err
+}
+
+func TestCreateNoteRendersCard(t *testing.T) {
+	svc := NewNoteService(&mockNoteRepo{})
+
+	note, err := svc.CreateNote(&models.CreateNoteRequest{
+		NoteTypeID:   1,
+		Fields:       map[string]string{"word": "σπίτι", "article": "το", "meaning": " house "},
+		QuestionLang: "EN",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if note.Card.Question != "house" || note.Card.Answer != "το σπίτι" || note.Card.QuestionLang != "en" {
+		t.Fatalf("unexpected card: %+v", note.Card)
+	}
+}
+
+func TestCreateNoteValidation(t *testing.T) {
+	svc := NewNoteService(&mockNoteRepo{})
+
+	if _, err := svc.CreateNote(&models.CreateNoteRequest{NoteTypeID: 1, Fields: map[string]string{"plural": "σπίτια"}}); !errors.Is(err, ErrInvalidNoteFields) {
+		t.Fatalf("expected ErrInvalidNoteFields for an unknown field, got %v", err)
+	}
+	if _, err := svc.CreateNote(&models.CreateNoteRequest{NoteTypeID: 1, Fields: map[string]string{"word": "σπίτι"}}); !errors.Is(err, ErrInvalidNoteFields) {
+		t.Fatalf("expected ErrInvalidNoteFields for an empty front, got %v", err)
+	}
+	if _, err := svc.CreateNote(&models.CreateNoteRequest{NoteTypeID: 9, Fields: map[string]string{"word": "σπίτι"}}); err == nil {
+		t.Fatal("expected an error for a missing note type")
+	}
+}
+
+func TestUpdateNoteKeepsFieldsWhenOnlyTagsChange(t *testing.T) {
+	repo := &mockNoteRepo{}
+	svc := NewNoteService(repo)
+	note, err := svc.CreateNote(&models.CreateNoteRequest{NoteTypeID: 1, Fields: map[string]string{"word": "σπίτι", "meaning": "house"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tags := []string{"A1"}
+	note, err = svc.UpdateNote(note.ID, &models.UpdateNoteRequest{Tags: &tags})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if note.Card.Question != "house" || note.Card.Answer != "σπίτι" {
+		t.Fatalf("expected the card to be rendered from the stored fields, got %+v", note.Card)
+	}
+}
+
+func TestUpdateNoteTypeRerendersNotes(t *testing.T) {
+	repo := &mockNoteRepo{}
+	svc := NewNoteService(repo)
+	if _, err := svc.CreateNote(&models.CreateNoteRequest{NoteTypeID: 1, Fields: map[string]string{"word": "σπίτι", "article": "το", "meaning": "house"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	back := "{{.word}} ({{.article}})"
+	if _, err := svc.UpdateNoteType(1, &models.UpdateNoteTypeRequest{BackTemplate: &back}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []models.RenderedNote{{NoteID: 1, Question: "house", Answer: "σπίτι (το)"}}
+	if fmt.Sprint(repo.rendered) != fmt.Sprint(want) {
+		t.Fatalf("expected %+v, got %+v", want, repo.rendered)
+	}
+
+	invalid := "{{.plural}}"
+	if _, err := svc.UpdateNoteType(1, &models.UpdateNoteTypeRequest{BackTemplate: &invalid}); !errors.Is(err, ErrInvalidNoteType) {
+		t.Fatalf("expected ErrInvalidNoteType, got %v", err)
+	}
+}
+
+func TestStudyEndpointsRenderNoteCards(t *testing.T) {
+	repo := &noteCardRepo{mockRepo: &mockRepo{}}
+	svc := NewFlashcardService(repo, nil, nil, nil)
+
+	flashcard, err := svc.GetFlashcardByID(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flashcard.Question != "house" || flashcard.Answer != "το σπίτι" {
+		t.Fatalf("expected the card to be rendered from its note, got %q / %q", flashcard.Question, flashcard.Answer)
+	}
+
+	result, err := svc.AnswerFlashcard(1, &models.AnswerRequest{Answer: "το σπίτι"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Verdict != models.VerdictCorrect {
+		t.Fatalf("expected the answer to be graded against the rendered back, got %+v", result)
+	}
+
+	question := "edited"
+	if _, err := svc.UpdateFlashcard(1, &models.UpdateFlashcardRequest{Question: &question}); !errors.Is(err, ErrNoteCardEdit) {
+		t.Fatalf("expected ErrNoteCardEdit, got %v", err)
+	}
+}
+
+// noteCardRepo is a mockRepo whose flashcards were created from a note.
+type noteCardRepo struct {
+	*mockRepo
+}
+
+func (r *noteCardRepo) GetByID(id int) (*models.Flashcard, error) {
+	flashcard, err := r.mockRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	flashcard.NoteID = intPtr(1)
+	flashcard.Note = &models.NoteContent{
+		Fields:        map[string]string{"word": "σπίτι", "article": "το", "meaning": "house"},
+		FrontTemplate: greekNoun.FrontTemplate,
+		BackTemplate:  greekNoun.BackTemplate,
+	}
+	return flashcard, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+func TestRenderNote(t *testing.T) {
+	question, answer, err := RenderNote(&models.NoteContent{
+		Fields:        map[string]string{"word": "σπίτι", "article": "το", "plural": "σπίτια"},
+		FrontTemplate: "{{.article}} {{.word}}",
+		BackTemplate:  "house{{if .plural}} (pl. {{.plural}}){{end}}{{if .example}}\n{{.example}}{{end}}",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if question != "το σπίτι" || answer != "house (pl. σπίτια)" {
+		t.Fatalf("unexpected card %q / %q", question, answer)
+	}
+}
+
+func TestRenderNoteLimitsLength(t *testing.T) {
+	_, _, err := RenderNote(&models.NoteContent{
+		Fields:        map[string]string{"word": strings.Repeat("x", MaxRenderedNoteLength)},
+		FrontTemplate: "{{.word}}{{.word}}",
+		BackTemplate:  "house",
+	})
+	if !errors.Is(err, errNoteTooLong) {
+		t.Fatalf("expected errNoteTooLong, got %v", err)
+	}
+}
+
+func TestValidateNoteType(t *testing.T) {
+	fields := []string{"word", "article", "example_sentence"}
+	if err := validateNoteType(fields, "{{.article}} {{.word}}", "{{if .example_sentence}}{{.example_sentence}}{{else if .word}}-{{end}}"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		fields      []string
+		front, back string
+	}{
+		{"no fields", nil, "{{.word}}", "{{.word}}"},
+		{"invalid field name", []string{"plural form"}, "x", "y"},
+		{"duplicate field", []string{"word", "word"}, "{{.word}}", "{{.word}}"},
+		{"empty template", fields, " ", "{{.word}}"},
+		{"syntax error", fields, "{{.word", "{{.word}}"},
+		{"undeclared field", fields, "{{.word}}", "{{if .gender}}{{.gender}}{{end}}"},
+		{"range", fields, "{{.word}}", "{{range 1000000000}}x{{end}}"},
+		{"with", fields, "{{.word}}", "{{with .gender}}{{.}}{{end}}"},
+		{"variable", fields, "{{$w := .word}}{{$w}}", "{{.word}}"},
+		{"function", fields, "{{.word}}", `{{printf "%099999999d" 1}}`},
+		{"define", fields, "{{.word}}", `{{define "x"}}{{.gender}}{{end}}{{template "x" .}}`},
+		{"nested field", fields, "{{.word.gender}}", "{{.word}}"},
+	}
+	for _, tt := range tests {
+		if err := validateNoteType(tt.fields, tt.front, tt.back); !errors.Is(err, ErrInvalidNoteType) {
+			t.Errorf("%s: expected ErrInvalidNoteType, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestNormalizeNoteFields(t *testing.T) {
+	noteType := &models.NoteType{Name: "Greek noun", Fields: []string{"word", "article"}}
+
+	fields, err := normalizeNoteFields(map[string]string{"word": " σπίτι ", "article": ""}, noteType)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fields["word"] != "σπίτι" {
+		t.Fatalf("expected trimmed values, got %v", fields)
+	}
+
+	for _, fields := range []map[string]string{
+		{"word": "σπίτι", "gender": "neuter"},
+		{"word": " "},
+		nil,
+	} {
+		if _, err := normalizeNoteFields(fields, noteType); !errors.Is(err, ErrInvalidNoteFields) {
+			t.Errorf("%v: expected ErrInvalidNoteFields, got %v", fields, err)
+		}
+	}
+}
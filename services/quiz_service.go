@@ -0,0 +1,159 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	mathrand "math/rand/v2"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+const (
+	defaultQuizDistractors = 3
+	maxQuizDistractors     = 9
+)
+
+// QuizTTL is how long a quiz can be answered. Older quizzes are deleted when new ones are created.
+const QuizTTL = 24 * time.Hour
+
+var (
+	// ErrInvalidQuizSize is returned when a quiz is requested with too few or too many distractors.
+	ErrInvalidQuizSize = fmt.Errorf("n must be between 1 and %d", maxQuizDistractors)
+	// ErrMissingChoice is returned when a quiz is answered without a choice.
+	ErrMissingChoice = errors.New("choice is required")
+	// ErrInvalidChoice is returned when a quiz is answered with a choice it does not have.
+	ErrInvalidChoice = errors.New("choice is out of range")
+	// ErrQuizExpired is returned when a quiz is answered after QuizTTL.
+	ErrQuizExpired = errors.New("quiz has expired")
+)
+
+// QuizServiceInterface defines the quiz operations the handlers depend on.
+type QuizServiceInterface interface {
+	CreateMultipleChoiceQuiz(filter *models.FlashcardFilter, distractors int) (*models.MultipleChoiceQuiz, error)
+	AnswerQuiz(id string, req *models.QuizAnswerRequest) (*models.QuizAnswerResult, error)
+}
+
+type QuizService struct {
+	repo    db.QuizRepository
+	shuffle func(n int, swap func(i, j int))
+	clock   Clock
+}
+
+func NewQuizService(repo db.QuizRepository) *QuizService {
+	if repo == nil {
+		panic("repository cannot be nil")
+	}
+	return &QuizService{repo: repo, shuffle: mathrand.Shuffle, clock: systemClock{}}
+}
+
+// CreateMultipleChoiceQuiz picks a random flashcard matching the filter and offers its answer among
+// the given number of distractors, 3 if it is 0, drawn from other cards in the same language pair.
+// The quiz is stored so that the answer can be checked without the client knowing which choice is
+// correct.
+func (s *QuizService) CreateMultipleChoiceQuiz(filter *models.FlashcardFilter, distractors int) (*models.MultipleChoiceQuiz, error) {
+	if distractors == 0 {
+		distractors = defaultQuizDistractors
+	}
+	if distractors < 1 || distractors > maxQuizDistractors {
+		return nil, ErrInvalidQuizSize
+	}
+
+	flashcard, err := s.repo.GetQuizFlashcard(filter, distractors)
+	if err != nil {
+		return nil, err
+	}
+
+	wrong, err := s.repo.GetDistractors(flashcard, distractors)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrong) < distractors {
+		return nil, fmt.Errorf("not enough distractors found for flashcard %d", flashcard.ID)
+	}
+
+	choices := append([]string{flashcard.Answer}, wrong...)
+	correct := 0
+	s.shuffle(len(choices), func(i, j int) {
+		choices[i], choices[j] = choices[j], choices[i]
+		switch correct {
+		case i:
+			correct = j
+		case j:
+			correct = i
+		}
+	})
+
+	id, err := newRandomID()
+	if err != nil {
+		return nil, err
+	}
+
+	quiz := &models.Quiz{
+		MultipleChoiceQuiz: models.MultipleChoiceQuiz{
+			ID:           id,
+			FlashcardID:  flashcard.ID,
+			Question:     flashcard.Question,
+			QuestionLang: flashcard.QuestionLang,
+			AnswerLang:   flashcard.AnswerLang,
+			Choices:      choices,
+		},
+		CorrectIndex: correct,
+	}
+	if err := s.repo.Create(quiz); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.DeleteCreatedBefore(s.clock.Now().Add(-QuizTTL)); err != nil {
+		log.Printf("Failed to delete expired quizzes: %v", err)
+	}
+
+	return &quiz.MultipleChoiceQuiz, nil
+}
+
+// AnswerQuiz checks the learner's choice and reveals the correct one. Each quiz can be answered once.
+func (s *QuizService) AnswerQuiz(id string, req *models.QuizAnswerRequest) (*models.QuizAnswerResult, error) {
+	if req.Choice == nil {
+		return nil, ErrMissingChoice
+	}
+
+	quiz, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if quiz.AnsweredAt != nil {
+		return nil, db.ErrQuizAnswered
+	}
+	if s.clock.Now().Sub(quiz.CreatedAt) >= QuizTTL {
+		return nil, ErrQuizExpired
+	}
+
+	choice := *req.Choice
+	if choice < 0 || choice >= len(quiz.Choices) {
+		return nil, ErrInvalidChoice
+	}
+
+	if err := s.repo.SaveAnswer(id, choice); err != nil {
+		return nil, err
+	}
+
+	return &models.QuizAnswerResult{
+		Correct:       choice == quiz.CorrectIndex,
+		Choice:        choice,
+		CorrectIndex:  quiz.CorrectIndex,
+		CorrectAnswer: quiz.Choices[quiz.CorrectIndex],
+	}, nil
+}
+
+// newRandomID returns a random, unguessable ID for a quiz or translation preview.
+func newRandomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// mockQuizRepo is an in-memory implementation of db.QuizRepository for tests.
+type mockQuizRepo struct {
+	distractors   []string
+	quizzes       map[string]*models.Quiz
+	minAnswers    int
+	deletedBefore time.Time
+}
+
+func (m *mockQuizRepo) GetQuizFlashcard(filter *models.FlashcardFilter, distractors int) (*models.Flashcard, error) {
+	m.minAnswers = distractors
+	return &models.Flashcard{ID: 1, Question: "when?", Answer: "πότε", QuestionLang: "en", AnswerLang: "el"}, nil
+}
+
+func (m *mockQuizRepo) GetDistractors(flashcard *models.Flashcard, n int) ([]string, error) {
+	return m.distractors[:min(n, len(m.distractors))], nil
+}
+
+func (m *mockQuizRepo) Create(quiz *models.Quiz) error {
+	if m.quizzes == nil {
+		m.quizzes = map[string]*models.Quiz{}
+	}
+	quiz.CreatedAt = time.Now()
+	m.quizzes[quiz.ID] = quiz
+	return nil
+}
+
+func (m *mockQuizRepo) GetByID(id string) (*models.Quiz, error) {
+	quiz, ok := m.quizzes[id]
+	if !ok {
+		return nil, fmt.Errorf("quiz with id %s not found", id)
+	}
+	return quiz, nil
+}
+
+func (m *mockQuizRepo) SaveAnswer(id string, choice int) error {
+	quiz := m.quizzes[id]
+	if quiz.AnsweredAt != nil {
+		return db.ErrQuizAnswered
+	}
+	now := time.Now()
+	quiz.Choice, quiz.AnsweredAt = &choice, &now
+	return nil
+}
+
+func (m *mockQuizRepo) DeleteCreatedBefore(t time.Time) (int64, error) {
+	m.deletedBefore = t
+	var deleted int64
+	for id, quiz := range m.quizzes {
+		if quiz.CreatedAt.Before(t) {
+			delete(m.quizzes, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// reverse is a deterministic shuffle that reverses the choices.
+func reverse(n int, swap func(i, j int)) {
+	for i := 0; i < n/2; i++ {
+		swap(i, n-1-i)
+	}
+}
+
+func TestCreateMultipleChoiceQuiz(t *testing.T) {
+	repo := &mockQuizRepo{distractors: []string{"ποτέ", "πού", "πώς", "γιατί"}}
+	svc := NewQuizService(repo)
+	svc.shuffle = reverse
+
+	quiz, err := svc.CreateMultipleChoiceQuiz(nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.minAnswers != defaultQuizDistractors {
+		t.Fatalf("expected %d distractors to be requested, got %d", defaultQuizDistractors, repo.minAnswers)
+	}
+	want := []string{"πώς", "πού", "ποτέ", "πότε"}
+	if fmt.Sprint(quiz.Choices) != fmt.Sprint(want) {
+		t.Fatalf("expected choices %v, got %v", want, quiz.Choices)
+	}
+	if len(quiz.ID) != 32 || quiz.Question != "when?" {
+		t.Fatalf("unexpected quiz: %+v", quiz)
+	}
+	if stored := repo.quizzes[quiz.ID]; stored.CorrectIndex != 3 {
+		t.Fatalf("expected the correct index to follow the shuffle to 3, got %d", stored.CorrectIndex)
+	}
+}
+
+func TestCreateMultipleChoiceQuizValidation(t *testing.T) {
+	svc := NewQuizService(&mockQuizRepo{distractors: []string{"πού"}})
+
+	for _, n := range []int{-1, maxQuizDistractors + 1} {
+		if _, err := svc.CreateMultipleChoiceQuiz(nil, n); !errors.Is(err, ErrInvalidQuizSize) {
+			t.Fatalf("n=%d: expected ErrInvalidQuizSize, got %v", n, err)
+		}
+	}
+	if _, err := svc.CreateMultipleChoiceQuiz(nil, 2); err == nil {
+		t.Fatal("expected an error when there are too few distractors")
+	}
+}
+
+func TestAnswerQuiz(t *testing.T) {
+	repo := &mockQuizRepo{distractors: []string{"ποτέ", "πού", "πώς"}}
+	svc := NewQuizService(repo)
+	svc.shuffle = reverse
+
+	quiz, err := svc.CreateMultipleChoiceQuiz(nil, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := svc.AnswerQuiz(quiz.ID, &models.QuizAnswerRequest{}); !errors.Is(err, ErrMissingChoice) {
+		t.Fatalf("expected ErrMissingChoice, got %v", err)
+	}
+	if _, err := svc.AnswerQuiz(quiz.ID, &models.QuizAnswerRequest{Choice: intPtr(4)}); !errors.Is(err, ErrInvalidChoice) {
+		t.Fatalf("expected ErrInvalidChoice, got %v", err)
+	}
+
+	result, err := svc.AnswerQuiz(quiz.ID, &models.QuizAnswerRequest{Choice: intPtr(1)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Correct || result.CorrectIndex != 3 || result.CorrectAnswer != "πότε" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	if _, err := svc.AnswerQuiz(quiz.ID, &models.QuizAnswerRequest{Choice: intPtr(3)}); !errors.Is(err, db.ErrQuizAnswered) {
+		t.Fatalf("expected ErrQuizAnswered, got %v", err)
+	}
+	if _, err := svc.AnswerQuiz("missing", &models.QuizAnswerRequest{Choice: intPtr(0)}); err == nil {
+		t.Fatal("expected an error for a missing quiz")
+	}
+}
+
+func TestQuizzesExpire(t *testing.T) {
+	repo := &mockQuizRepo{distractors: []string{"ποτέ", "πού", "πώς"}}
+	svc := NewQuizService(repo)
+
+	old, err := svc.CreateMultipleChoiceQuiz(nil, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svc.clock = fakeClock{now: time.Now().Add(QuizTTL)}
+	if _, err := svc.AnswerQuiz(old.ID, &models.QuizAnswerRequest{Choice: intPtr(0)}); !errors.Is(err, ErrQuizExpired) {
+		t.Fatalf("expected ErrQuizExpired, got %v", err)
+	}
+
+	quiz, err := svc.CreateMultipleChoiceQuiz(nil, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := repo.quizzes[old.ID]; ok {
+		t.Fatal("expected the expired quiz to be deleted")
+	}
+	if _, ok := repo.quizzes[quiz.ID]; !ok {
+		t.Fatal("expected the new quiz to be kept")
+	}
+}
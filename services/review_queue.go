@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// ErrMissingApprover is returned when a flashcard is approved without saying who approved it.
+var ErrMissingApprover = errors.New("approved_by is required")
+
+// ErrMissingRejecter is returned when a flashcard is rejected without saying who rejected it.
+var ErrMissingRejecter = errors.New("rejected_by is required")
+
+// ErrFlashcardNotActive is returned when a flashcard that is pending review or was rejected is
+// studied.
+var ErrFlashcardNotActive = db.ErrFlashcardNotActive
+
+const (
+	defaultReviewQueueLimit = 20
+	maxReviewQueueLimit     = 100
+)
+
+// GetReviewQueue returns up to limit flashcards with machine-generated fields that are waiting for a
+// person to check them, oldest first.
+func (s *FlashcardService) GetReviewQueue(limit int) ([]*models.Flashcard, error) {
+	return s.repo.GetPendingReview(clampLimit(limit, defaultReviewQueueLimit, maxReviewQueueLimit))
+}
+
+// ApproveFlashcard releases a flashcard waiting for review into study, recording who approved it and
+// when. Corrections in req.Edit are validated like any update and applied first; edited fields are no
+// longer counted as machine-generated.
+func (s *FlashcardService) ApproveFlashcard(id int, req *models.ApproveFlashcardRequest) (*models.Flashcard, error) {
+	approvedBy := strings.TrimSpace(req.ApprovedBy)
+	if approvedBy == "" {
+		return nil, ErrMissingApprover
+	}
+
+	if req.Edit != nil {
+		if err := s.prepareUpdate(id, req.Edit); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.Approve(id, approvedBy, s.clock.Now(), req.Edit)
+}
+
+// RejectFlashcard rejects a flashcard waiting for review, along with its reverse card, recording who
+// rejected it and when. The card is kept but never studied.
+func (s *FlashcardService) RejectFlashcard(id int, req *models.RejectFlashcardRequest) (*models.Flashcard, error) {
+	rejectedBy := strings.TrimSpace(req.RejectedBy)
+	if rejectedBy == "" {
+		return nil, ErrMissingRejecter
+	}
+
+	return s.repo.Reject(id, rejectedBy, s.clock.Now())
+}
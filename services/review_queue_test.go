@@ -0,0 +1,140 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+func TestGetReviewQueueClampsLimit(t *testing.T) {
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, nil, nil, nil)
+
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{0, defaultReviewQueueLimit},
+		{5, 5},
+		{1000, maxReviewQueueLimit},
+	}
+	for _, tt := range tests {
+		if _, err := svc.GetReviewQueue(tt.limit); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repo.pendingLimit != tt.want {
+			t.Errorf("limit %d: expected %d, got %d", tt.limit, tt.want, repo.pendingLimit)
+		}
+	}
+}
+
+func TestApproveFlashcard(t *testing.T) {
+	now := time.Date(2025, 11, 20, 9, 0, 0, 0, time.UTC)
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, nil, nil, nil)
+	svc.clock = fakeClock{now: now}
+
+	flashcard, err := svc.ApproveFlashcard(1, &models.ApproveFlashcardRequest{ApprovedBy: "  maria "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flashcard.Status != models.FlashcardStatusActive {
+		t.Fatalf("expected the card to be active, got %q", flashcard.Status)
+	}
+	if repo.approvedBy != "maria" || !repo.approvedAt.Equal(now) || repo.approvedEdit != nil {
+		t.Fatalf("unexpected approval: by %q at %v with %+v", repo.approvedBy, repo.approvedAt, repo.approvedEdit)
+	}
+}
+
+func TestApproveFlashcardWithEdit(t *testing.T) {
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, nil, nil, nil)
+
+	answer := "το σπίτι"
+	tags := []string{" noun ", "noun"}
+	_, err := svc.ApproveFlashcard(1, &models.ApproveFlashcardRequest{
+		ApprovedBy: "maria",
+		Edit:       &models.UpdateFlashcardRequest{Answer: &answer, Tags: &tags},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.approvedEdit == nil || *repo.approvedEdit.Answer != answer {
+		t.Fatalf("expected the edit to be passed on, got %+v", repo.approvedEdit)
+	}
+	if got := *repo.approvedEdit.Tags; len(got) != 1 || got[0] != "noun" {
+		t.Fatalf("expected the edited tags to be normalized, got %v", got)
+	}
+}
+
+func TestApproveFlashcardErrors(t *testing.T) {
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
+
+	if _, err := svc.ApproveFlashcard(1, &models.ApproveFlashcardRequest{ApprovedBy: " "}); !errors.Is(err, ErrMissingApprover) {
+		t.Fatalf("expected ErrMissingApprover, got %v", err)
+	}
+
+	edit := &models.UpdateFlashcardRequest{}
+	if _, err := svc.ApproveFlashcard(1, &models.ApproveFlashcardRequest{ApprovedBy: "maria", Edit: edit}); err == nil {
+		t.Fatal("expected an empty edit to be rejected")
+	}
+
+	clozeSvc := NewFlashcardService(&clozeCardRepo{mockRepo: &mockRepo{}, noteID: intPtr(1)}, nil, nil, nil)
+	answer := "x"
+	edit = &models.UpdateFlashcardRequest{Answer: &answer}
+	if _, err := clozeSvc.ApproveFlashcard(1, &models.ApproveFlashcardRequest{ApprovedBy: "maria", Edit: edit}); !errors.Is(err, ErrClozeCardEdit) {
+		t.Fatalf("expected ErrClozeCardEdit, got %v", err)
+	}
+}
+
+func TestRejectFlashcard(t *testing.T) {
+	now := time.Date(2025, 11, 20, 9, 0, 0, 0, time.UTC)
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
+	svc.clock = fakeClock{now: now}
+
+	flashcard, err := svc.RejectFlashcard(1, &models.RejectFlashcardRequest{RejectedBy: " maria "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flashcard.Status != models.FlashcardStatusRejected || flashcard.RejectedBy != "maria" || !flashcard.RejectedAt.Equal(now) {
+		t.Fatalf("expected the rejection to be recorded, got %+v", flashcard)
+	}
+
+	if _, err := svc.RejectFlashcard(1, &models.RejectFlashcardRequest{}); !errors.Is(err, ErrMissingRejecter) {
+		t.Fatalf("expected ErrMissingRejecter, got %v", err)
+	}
+}
+
+// statusRepo is a mockRepo whose flashcard has the given review status.
+type statusRepo struct {
+	*mockRepo
+	status string
+}
+
+func (r *statusRepo) GetByID(id int) (*models.Flashcard, error) {
+	flashcard, err := r.mockRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	flashcard.Status = r.status
+	return flashcard, nil
+}
+
+func TestStudyingInactiveFlashcardFails(t *testing.T) {
+	for _, status := range []string{models.FlashcardStatusPendingReview, models.FlashcardStatusRejected} {
+		repo := &statusRepo{mockRepo: &mockRepo{}, status: status}
+		svc := NewFlashcardService(repo, nil, nil, nil)
+
+		if _, err := svc.ReviewFlashcard(1, &models.ReviewRequest{Grade: intPtr(4)}); !errors.Is(err, ErrFlashcardNotActive) {
+			t.Errorf("%s: expected ErrFlashcardNotActive on review, got %v", status, err)
+		}
+		if _, err := svc.AnswerFlashcard(1, &models.AnswerRequest{Answer: "a"}); !errors.Is(err, ErrFlashcardNotActive) {
+			t.Errorf("%s: expected ErrFlashcardNotActive on answer, got %v", status, err)
+		}
+		if repo.savedSchedule != nil {
+			t.Errorf("%s: expected no schedule to be saved", status)
+		}
+	}
+}
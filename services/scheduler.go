@@ -0,0 +1,47 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// Scheduler decides when a flashcard should be reviewed next. Implementations must be deterministic:
+// the same previous schedule, grade and time always yield the same next schedule.
+type Scheduler interface {
+	// Name identifies the algorithm and is stored with every schedule it produces.
+	Name() string
+	// Schedule computes the next schedule of a card graded on the 0-5 scale at the given time. prev is
+	// nil for a card that has never been reviewed.
+	Schedule(flashcardID int, prev *models.CardSchedule, grade int, now time.Time) (*models.CardSchedule, error)
+}
+
+// NewScheduler returns the scheduler registered under the given name. The FSRS scheduler loads the
+// weights fitted by cmd/optimize from fsrsParams, or always uses DefaultFSRSWeights when fsrsParams is
+// nil.
+func NewScheduler(name string, fsrsParams db.FSRSParameterRepository) (Scheduler, error) {
+	switch name {
+	case "", SM2SchedulerName:
+		return NewSM2Scheduler(), nil
+	case FSRSSchedulerName:
+		if fsrsParams == nil {
+			return NewFSRSScheduler(nil, DefaultDesiredRetention)
+		}
+		return NewStoredFSRSScheduler(fsrsParams, DefaultDesiredRetention)
+	default:
+		return nil, fmt.Errorf("unknown scheduler %q", name)
+	}
+}
+
+// Clock supplies the current time so that scheduling can be tested with a fixed clock.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now().UTC()
+}
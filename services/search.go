@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+
+	// DefaultFuzzyThreshold is the minimum similarity of fuzzy matches when none is requested. It is
+	// pg_trgm's default and lets through one or two typos in a short word.
+	DefaultFuzzyThreshold = 0.3
+)
+
+// ErrEmptySearchQuery is returned when a search query has no content.
+var ErrEmptySearchQuery = errors.New("search query must not be empty")
+
+// ErrInvalidSearch is returned for an unknown search mode or an out of range threshold.
+var ErrInvalidSearch = errors.New("invalid search")
+
+// SearchFlashcards finds flashcards whose question or answer matches the query, best matches first.
+// Full-text search matches whole words regardless of accents and inflection; fuzzy search matches
+// similar spellings and tolerates typos.
+func (s *FlashcardService) SearchFlashcards(req *models.SearchRequest) ([]*models.SearchResult, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+	limit := clampLimit(req.Limit, defaultSearchLimit, maxSearchLimit)
+
+	var results []*models.SearchResult
+	var err error
+	switch req.Mode {
+	case "", models.SearchModeFullText:
+		results, err = s.repo.Search(query, searchLanguage(query), limit)
+		for _, result := range results {
+			result.Snippet = highlightSnippet(result.Snippet)
+		}
+	case models.SearchModeFuzzy:
+		threshold := DefaultFuzzyThreshold
+		if req.Threshold != nil {
+			threshold = *req.Threshold
+		}
+		if math.IsNaN(threshold) || threshold < 0 || threshold > 1 {
+			return nil, fmt.Errorf("%w: threshold must be between 0 and 1", ErrInvalidSearch)
+		}
+		results, err = s.repo.FuzzySearch(query, threshold, limit)
+	default:
+		return nil, fmt.Errorf("%w: unknown mode %q", ErrInvalidSearch, req.Mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []*models.SearchResult{}
+	}
+
+	return results, nil
+}
+
+// highlightSnippet HTML-escapes a snippet returned by the repository and wraps its matched words in
+// <mark> tags, so that clients can display it as HTML.
+func highlightSnippet(snippet string) string {
+	return strings.NewReplacer(db.SnippetStartSel, "<mark>", db.SnippetStopSel, "</mark>").Replace(html.EscapeString(snippet))
+}
+
+// searchLanguage picks the language a query is parsed in: Greek if it contains any Greek letter,
+// English otherwise. Cards are indexed in both, so either side of a card can be found.
+func searchLanguage(query string) string {
+	for _, r := range query {
+		if unicode.Is(unicode.Greek, r) {
+			return "el"
+		}
+	}
+	return "en"
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+func TestSearchFlashcardsPicksLanguage(t *testing.T) {
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, nil, nil, nil)
+
+	tests := map[string]string{
+		"hello":        "en",
+		"Γειά":         "el",
+		"say γεια σας": "el",
+	}
+	for query, want := range tests {
+		results, err := svc.SearchFlashcards(&models.SearchRequest{Query: query})
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", query, err)
+		}
+		if results == nil {
+			t.Fatalf("%q: expected an empty result list, got nil", query)
+		}
+		if repo.searchLang != want {
+			t.Errorf("%q: expected language %q, got %q", query, want, repo.searchLang)
+		}
+	}
+}
+
+func TestSearchFlashcardsEscapesSnippets(t *testing.T) {
+	repo := &mockRepo{searchResults: []*models.SearchResult{{
+		Flashcard: &models.Flashcard{ID: 1},
+		Snippet:   "<img src=x onerror=alert(1)> — " + db.SnippetStartSel + "γεια" + db.SnippetStopSel + " & σας",
+	}}}
+	svc := NewFlashcardService(repo, nil, nil, nil)
+
+	results, err := svc.SearchFlashcards(&models.SearchRequest{Query: "γεια"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "&lt;img src=x onerror=alert(1)&gt; — <mark>γεια</mark> &amp; σας"
+	if results[0].Snippet != want {
+		t.Fatalf("expected snippet %q, got %q", want, results[0].Snippet)
+	}
+}
+
+func TestSearchFlashcardsEmptyQuery(t *testing.T) {
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
+
+	if _, err := svc.SearchFlashcards(&models.SearchRequest{Query: "   "}); !errors.Is(err, ErrEmptySearchQuery) {
+		t.Fatalf("expected ErrEmptySearchQuery, got %v", err)
+	}
+}
+
+func TestSearchFlashcardsFuzzyThreshold(t *testing.T) {
+	repo := &mockRepo{}
+	svc := NewFlashcardService(repo, nil, nil, nil)
+
+	if _, err := svc.SearchFlashcards(&models.SearchRequest{Query: "helo", Mode: models.SearchModeFuzzy}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.fuzzyThreshold != DefaultFuzzyThreshold {
+		t.Fatalf("expected default threshold %v, got %v", DefaultFuzzyThreshold, repo.fuzzyThreshold)
+	}
+
+	for _, threshold := range []float64{0.5, 0} {
+		if _, err := svc.SearchFlashcards(&models.SearchRequest{Query: "helo", Mode: models.SearchModeFuzzy, Threshold: &threshold}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if repo.fuzzyThreshold != threshold {
+			t.Fatalf("expected threshold %v, got %v", threshold, repo.fuzzyThreshold)
+		}
+	}
+}
+
+func TestSearchFlashcardsInvalidOptions(t *testing.T) {
+	svc := NewFlashcardService(&mockRepo{}, nil, nil, nil)
+
+	for _, req := range []*models.SearchRequest{
+		{Query: "helo", Mode: "regex"},
+		{Query: "helo", Mode: models.SearchModeFuzzy, Threshold: floatPtr(1.5)},
+		{Query: "helo", Mode: models.SearchModeFuzzy, Threshold: floatPtr(-0.1)},
+		{Query: "helo", Mode: models.SearchModeFuzzy, Threshold: floatPtr(math.NaN())},
+		{Query: "helo", Mode: models.SearchModeFuzzy, Threshold: floatPtr(math.Inf(1))},
+	} {
+		if _, err := svc.SearchFlashcards(req); !errors.Is(err, ErrInvalidSearch) {
+			t.Errorf("%+v: expected ErrInvalidSearch, got %v", req, err)
+		}
+	}
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// SM2SchedulerName identifies schedules produced by the SM-2 scheduler.
+const SM2SchedulerName = "sm2"
+
+const (
+	// MinGrade and MaxGrade bound the SM-2 grading scale accepted by reviews.
+	MinGrade = 0
+	MaxGrade = 5
+
+	// sm2PassingGrade is the lowest grade that counts as a successful recall.
+	sm2PassingGrade = 3
+
+	sm2InitialEaseFactor = 2.5
+	sm2MinEaseFactor     = 1.3
+)
+
+// SM2Scheduler implements the SuperMemo-2 spaced-repetition algorithm.
+type SM2Scheduler struct{}
+
+func NewSM2Scheduler() *SM2Scheduler {
+	return &SM2Scheduler{}
+}
+
+func (s *SM2Scheduler) Name() string {
+	return SM2SchedulerName
+}
+
+// Schedule implements Scheduler. A schedule produced by another algorithm carries no ease factor and
+// is treated as a fresh card.
+func (s *SM2Scheduler) Schedule(flashcardID int, prev *models.CardSchedule, grade int, now time.Time) (*models.CardSchedule, error) {
+	if err := validateGrade(grade); err != nil {
+		return nil, err
+	}
+
+	next := models.CardSchedule{
+		FlashcardID: flashcardID,
+		Scheduler:   SM2SchedulerName,
+		EaseFactor:  sm2InitialEaseFactor,
+	}
+	if prev != nil && prev.Scheduler == SM2SchedulerName {
+		next.EaseFactor = prev.EaseFactor
+		next.IntervalDays = prev.IntervalDays
+		next.Repetitions = prev.Repetitions
+	}
+
+	if grade >= sm2PassingGrade {
+		switch next.Repetitions {
+		case 0:
+			next.IntervalDays = 1
+		case 1:
+			next.IntervalDays = 6
+		default:
+			next.IntervalDays = int(math.Round(float64(next.IntervalDays) * next.EaseFactor))
+		}
+		next.Repetitions++
+	} else {
+		next.Repetitions = 0
+		next.IntervalDays = 1
+	}
+
+	q := float64(MaxGrade - grade)
+	next.EaseFactor = math.Max(sm2MinEaseFactor, next.EaseFactor+0.1-q*(0.08+q*0.02))
+
+	reviewedAt := now
+	next.LastReviewedAt = &reviewedAt
+	next.DueAt = now.AddDate(0, 0, next.IntervalDays)
+
+	return &next, nil
+}
+
+func validateGrade(grade int) error {
+	if grade < MinGrade || grade > MaxGrade {
+		return fmt.Errorf("grade must be between %d and %d", MinGrade, MaxGrade)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSM2SchedulerIntervalProgression(t *testing.T) {
+	s := NewSM2Scheduler()
+	now := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	first, err := s.Schedule(1, nil, 4, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.IntervalDays != 1 || first.Repetitions != 1 {
+		t.Fatalf("expected interval 1 and 1 repetition, got %d and %d", first.IntervalDays, first.Repetitions)
+	}
+	if !first.DueAt.Equal(now.AddDate(0, 0, 1)) {
+		t.Fatalf("expected card to be due in one day, got %v", first.DueAt)
+	}
+
+	second, _ := s.Schedule(1, first, 4, first.DueAt)
+	if second.IntervalDays != 6 {
+		t.Fatalf("expected interval 6 after second review, got %d", second.IntervalDays)
+	}
+
+	third, _ := s.Schedule(1, second, 5, second.DueAt)
+	if third.IntervalDays != 15 {
+		t.Fatalf("expected interval 15 after third review, got %d", third.IntervalDays)
+	}
+	if third.EaseFactor <= second.EaseFactor {
+		t.Fatalf("expected ease factor to grow after a perfect response")
+	}
+}
+
+func TestSM2SchedulerLapseResetsRepetitions(t *testing.T) {
+	s := NewSM2Scheduler()
+	now := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	prev, _ := s.Schedule(1, nil, 5, now)
+	prev, _ = s.Schedule(1, prev, 5, prev.DueAt)
+
+	lapsed, err := s.Schedule(1, prev, 1, prev.DueAt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lapsed.Repetitions != 0 || lapsed.IntervalDays != 1 {
+		t.Fatalf("expected lapse to reset repetitions and interval, got %d and %d", lapsed.Repetitions, lapsed.IntervalDays)
+	}
+}
+
+func TestSM2SchedulerEaseFactorFloor(t *testing.T) {
+	s := NewSM2Scheduler()
+	now := time.Now()
+
+	schedule, _ := s.Schedule(1, nil, 0, now)
+	for i := 0; i < 10; i++ {
+		schedule, _ = s.Schedule(1, schedule, 0, now)
+	}
+	if schedule.EaseFactor < sm2MinEaseFactor {
+		t.Fatalf("expected ease factor to stay above %v, got %v", sm2MinEaseFactor, schedule.EaseFactor)
+	}
+}
+
+func TestSM2SchedulerRejectsInvalidGrade(t *testing.T) {
+	s := NewSM2Scheduler()
+
+	if _, err := s.Schedule(1, nil, 6, time.Now()); err == nil {
+		t.Fatalf("expected error for grade above %d", MaxGrade)
+	}
+	if _, err := s.Schedule(1, nil, -1, time.Now()); err == nil {
+		t.Fatalf("expected error for grade below %d", MinGrade)
+	}
+}
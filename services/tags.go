@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// MaxTagLength is the longest tag name accepted, in characters.
+const MaxTagLength = 50
+
+const (
+	tagAlternativeSeparator = "|"
+	tagNegationPrefix       = "!"
+)
+
+// ErrInvalidTag is returned for tag names that are empty, too long or contain filter syntax.
+var ErrInvalidTag = errors.New("invalid tag")
+
+// NormalizeTags trims tag names and drops duplicates, keeping the first occurrence of each. Tags are
+// case-sensitive, so "A2" and "a2" are different tags.
+func NormalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if err := validateTag(tag); err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+func validateTag(tag string) error {
+	switch {
+	case tag == "":
+		return fmt.Errorf("%w: tag must not be empty", ErrInvalidTag)
+	case utf8.RuneCountInString(tag) > MaxTagLength:
+		return fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidTag, tag, MaxTagLength)
+	case strings.Contains(tag, tagAlternativeSeparator) || strings.HasPrefix(tag, tagNegationPrefix):
+		return fmt.Errorf("%w: %q must not contain %q or start with %q", ErrInvalidTag, tag, tagAlternativeSeparator, tagNegationPrefix)
+	}
+	return nil
+}
+
+// ParseTagFilter parses the values of repeated tag query parameters. Every value must match, so
+// tag=verb&tag=A2 selects cards tagged both verb and A2. A value listing alternatives separated by
+// "|" matches cards with any of them (tag=A1|A2), and a value prefixed with "!" excludes cards with
+// that tag (tag=!food). It returns nil if there are no values.
+func ParseTagFilter(values []string) (*models.TagFilter, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	var filter models.TagFilter
+	for _, value := range values {
+		if negated, ok := strings.CutPrefix(value, tagNegationPrefix); ok {
+			tag := strings.TrimSpace(negated)
+			if err := validateTag(tag); err != nil {
+				return nil, err
+			}
+			filter.NoneOf = append(filter.NoneOf, tag)
+			continue
+		}
+
+		var group []string
+		for _, alternative := range strings.Split(value, tagAlternativeSeparator) {
+			tag := strings.TrimSpace(alternative)
+			if err := validateTag(tag); err != nil {
+				return nil, err
+			}
+			group = append(group, tag)
+		}
+		filter.AnyOf = append(filter.AnyOf, group)
+	}
+	return &filter, nil
+}
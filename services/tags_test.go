@@ -0,0 +1,54 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+func TestParseTagFilter(t *testing.T) {
+	filter, err := ParseTagFilter([]string{"verb", "A1| A2", "!food"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := &models.TagFilter{
+		AnyOf:  [][]string{{"verb"}, {"A1", "A2"}},
+		NoneOf: []string{"food"},
+	}
+	if !reflect.DeepEqual(filter, want) {
+		t.Fatalf("expected %+v, got %+v", want, filter)
+	}
+}
+
+func TestParseTagFilterWithoutValues(t *testing.T) {
+	filter, err := ParseTagFilter(nil)
+	if err != nil || filter != nil {
+		t.Fatalf("expected nil filter, got %+v, %v", filter, err)
+	}
+}
+
+func TestParseTagFilterInvalid(t *testing.T) {
+	for _, values := range [][]string{{""}, {"verb|"}, {"!"}, {"!!food"}, {strings.Repeat("x", MaxTagLength+1)}} {
+		if _, err := ParseTagFilter(values); !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("%q: expected ErrInvalidTag, got %v", values, err)
+		}
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{"food", " A2", "food", "a2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"food", "A2", "a2"}; !reflect.DeepEqual(tags, want) {
+		t.Fatalf("expected %v, got %v", want, tags)
+	}
+
+	if _, err := NormalizeTags([]string{"  "}); !errors.Is(err, ErrInvalidTag) {
+		t.Fatalf("expected ErrInvalidTag for blank tag, got %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// ErrSelectedTranslation is returned when the candidate chosen from a translation preview cannot fill
+// in a side of a new card.
+var ErrSelectedTranslation = errors.New("the chosen candidate does not fit the card")
+
+// ErrTranslationPreviewExpired is returned when a card is created from a translation preview older
+// than TranslationPreviewTTL.
+var ErrTranslationPreviewExpired = errors.New("translation preview has expired")
+
+// ErrInvalidCandidateCount is returned when too few or too many candidate translations are requested.
+var ErrInvalidCandidateCount = fmt.Errorf("n must be between 1 and %d", maxTranslationCandidates)
+
+// ErrTranslationFailed is returned when the language model fails to translate a side of a new card.
+var ErrTranslationFailed = errors.New("failed to translate")
+
+// ErrTranslationSide is returned when a translation preview does not have exactly one empty side.
+var ErrTranslationSide = errors.New("exactly one of question and answer must be empty to preview its translation")
+
+// TranslationPreviewTTL is how long the candidates of a translation preview can be chosen from. Older
+// previews are deleted when new ones are created.
+const TranslationPreviewTTL = time.Hour
+
+const (
+	defaultTranslationCandidates = 3
+	maxTranslationCandidates     = 5
+)
+
+// maxTranslationAlternatives caps the alternatives kept from a translation.
+const maxTranslationAlternatives = 5
+
+// translationQuotes pairs the opening and closing quotation marks that models wrap translations in.
+var translationQuotes = [][2]string{
+	{`"`, `"`}, {`'`, `'`}, {"“", "”"}, {"‘", "’"}, {"«", "»"}, {"„", "“"}, {"„", "”"}, {"`", "`"},
+}
+
+// translationLabels are prefixes that models put before a translation.
+var translationLabels = []string{"translation:", "answer:"}
+
+// translationExplanations are labels of lines in which a model explains a translation instead of
+// giving it.
+var translationExplanations = []string{"note:", "notes:", "explanation:", "literally:", "literal translation:"}
+
+// sanitizeTranslation strips what a model adds around a translation of source: explanations on later
+// lines, labels such as "Translation:", wrapping quotes and a trailing period the source does not
+// have. A translation of a single line is cut to its first line; the lines of a translation of
+// several lines are kept, except for those that look like explanations.
+func sanitizeTranslation(translation, source string) string {
+	translation = strings.TrimSpace(translation)
+	if !strings.Contains(strings.TrimSpace(source), "\n") {
+		translation, _, _ = strings.Cut(translation, "\n")
+	} else {
+		lines := strings.Split(translation, "\n")
+		translation = strings.Join(slices.DeleteFunc(lines, isTranslationExplanation), "\n")
+	}
+	translation = strings.TrimSpace(translation)
+	for _, label := range translationLabels {
+		if len(translation) >= len(label) && strings.EqualFold(translation[:len(label)], label) {
+			translation = strings.TrimSpace(translation[len(label):])
+		}
+	}
+
+	for unquoted := false; !unquoted; {
+		unquoted = true
+		for _, quotes := range translationQuotes {
+			if len(translation) > len(quotes[0])+len(quotes[1]) && strings.HasPrefix(translation, quotes[0]) && strings.HasSuffix(translation, quotes[1]) {
+				translation = strings.TrimSpace(translation[len(quotes[0]) : len(translation)-len(quotes[1])])
+				unquoted = false
+			}
+		}
+	}
+
+	if !strings.HasSuffix(strings.TrimSpace(source), ".") {
+		translation = strings.TrimRight(strings.TrimSuffix(translation, "."), " ")
+	}
+	return collapseSpaces(translation)
+}
+
+func isTranslationExplanation(line string) bool {
+	line = strings.ToLower(strings.TrimSpace(line))
+	for _, prefix := range translationExplanations {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// collapseSpaces collapses runs of spaces and tabs into single spaces and trims every line, keeping
+// line breaks. Blank lines around the text are dropped.
+func collapseSpaces(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.FieldsFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == '\r'
+		}), " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// cleanTranslation validates a translation of source returned by a model and sanitises its text.
+// Alternatives that are empty or repeat the translation are dropped.
+func cleanTranslation(translation *models.Translation, source string) error {
+	translation.Translation = sanitizeTranslation(translation.Translation, source)
+	if translation.Translation == "" {
+		return errors.New("translation is empty")
+	}
+	if translation.Confidence < 0 || translation.Confidence > 1 {
+		return fmt.Errorf("confidence %v is not between 0 and 1", translation.Confidence)
+	}
+
+	translation.PartOfSpeech = strings.ToLower(strings.TrimSpace(translation.PartOfSpeech))
+	if translation.PartOfSpeech != "" && !slices.Contains(models.TranslationPartsOfSpeech, translation.PartOfSpeech) {
+		return fmt.Errorf("unknown part of speech %q", translation.PartOfSpeech)
+	}
+
+	seen := map[string]bool{strings.ToLower(translation.Translation): true}
+	var alternatives []string
+	for _, alternative := range translation.Alternatives {
+		alternative = sanitizeTranslation(alternative, source)
+		key := strings.ToLower(alternative)
+		if alternative == "" || seen[key] || len(alternatives) == maxTranslationAlternatives {
+			continue
+		}
+		seen[key] = true
+		alternatives = append(alternatives, alternative)
+	}
+	translation.Alternatives = alternatives
+	return nil
+}
+
+// cleanTranslationCandidates validates candidate translations of source returned by a model and
+// sanitises their text. Candidates that are empty or repeat an earlier translation are dropped, and at
+// most n are kept.
+func cleanTranslationCandidates(candidates []models.TranslationCandidate, source string, n int) ([]models.TranslationCandidate, error) {
+	seen := map[string]bool{}
+	var cleaned []models.TranslationCandidate
+	for _, candidate := range candidates {
+		candidate.Translation = sanitizeTranslation(candidate.Translation, source)
+		key := strings.ToLower(candidate.Translation)
+		if candidate.Translation == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		candidate.PartOfSpeech = strings.ToLower(strings.TrimSpace(candidate.PartOfSpeech))
+		if candidate.PartOfSpeech != "" && !slices.Contains(models.TranslationPartsOfSpeech, candidate.PartOfSpeech) {
+			return nil, fmt.Errorf("unknown part of speech %q", candidate.PartOfSpeech)
+		}
+		candidate.Register = strings.ToLower(strings.TrimSpace(candidate.Register))
+		if candidate.Register != "" && !slices.Contains(models.TranslationRegisters, candidate.Register) {
+			return nil, fmt.Errorf("unknown register %q", candidate.Register)
+		}
+		candidate.Usage = strings.Join(strings.Fields(candidate.Usage), " ")
+
+		cleaned = append(cleaned, candidate)
+	}
+	if len(cleaned) == 0 {
+		return nil, errors.New("no translations")
+	}
+	if len(cleaned) > n {
+		cleaned = cleaned[:n]
+	}
+	return cleaned, nil
+}
+
+// applyTranslationPreview fills in the empty side of a new card with the candidate chosen from the
+// translation preview of its other side, and returns the preview. The card must have the source and
+// languages of the preview.
+func (s *FlashcardService) applyTranslationPreview(req *models.CreateFlashcardRequest) (*models.StoredTranslationPreview, error) {
+	preview, err := s.repo.GetTranslationPreview(req.PreviewID)
+	if err != nil {
+		return nil, err
+	}
+	if s.clock.Now().Sub(preview.CreatedAt) >= TranslationPreviewTTL {
+		return nil, ErrTranslationPreviewExpired
+	}
+
+	if req.Candidate < 0 || req.Candidate >= len(preview.Candidates) {
+		return nil, fmt.Errorf("%w: candidate must be between 0 and %d", ErrSelectedTranslation, len(preview.Candidates)-1)
+	}
+	if req.QuestionLang != preview.QuestionLang || req.AnswerLang != preview.AnswerLang {
+		return nil, fmt.Errorf("%w: the languages differ from those of the preview", ErrSelectedTranslation)
+	}
+
+	translation := preview.Candidates[req.Candidate].Translation
+	switch {
+	case preview.TranslatedField == "answer" && req.Answer == "" && req.Question == preview.Source:
+		req.Answer = translation
+	case preview.TranslatedField == "question" && req.Question == "" && req.Answer == preview.Source:
+		req.Question = translation
+	default:
+		return nil, fmt.Errorf("%w: the %s must be empty and the other side must be the source of the preview", ErrSelectedTranslation, preview.TranslatedField)
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/db"
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// TranslationCacheInterface defines the cache operations the handlers depend on.
+type TranslationCacheInterface interface {
+	Purge(expiredOnly bool) (*models.TranslationCachePurge, error)
+}
+
+// TranslationCache keeps translations in Postgres for a fixed time, optionally with an in-memory LRU
+// in front of it so that repeated lookups do not reach the database. Cache failures are logged and
+// treated as misses, so they never fail a translation.
+type TranslationCache struct {
+	repo  db.TranslationCacheRepository
+	ttl   time.Duration
+	lru   *translationLRU // nil when the in-memory cache is disabled
+	clock Clock
+}
+
+// NewTranslationCache creates a cache whose entries expire after ttl. lruSize is the number of
+// translations also kept in memory; 0 disables the in-memory cache.
+func NewTranslationCache(repo db.TranslationCacheRepository, ttl time.Duration, lruSize int) *TranslationCache {
+	if repo == nil {
+		panic("repository cannot be nil")
+	}
+	cache := &TranslationCache{repo: repo, ttl: ttl, clock: systemClock{}}
+	if lruSize > 0 {
+		cache.lru = newTranslationLRU(lruSize)
+	}
+	return cache
+}
+
+// translationCacheKey builds the cache key of a translation request to the named provider and model.
+// Whitespace in the text is collapsed and language codes are put in canonical case; codes that are
+// not well-formed are kept as they are.
+func translationCacheKey(text, sourceLang, targetLang, provider, model string) models.TranslationCacheKey {
+	key := models.TranslationCacheKey{
+		Text:          collapseSpaces(text),
+		SourceLang:    sourceLang,
+		TargetLang:    targetLang,
+		PromptVersion: promptVersion,
+		Provider:      provider,
+		Model:         model,
+	}
+	if canonical, err := CanonicalLanguageTag(sourceLang); err == nil {
+		key.SourceLang = canonical
+	}
+	if canonical, err := CanonicalLanguageTag(targetLang); err == nil {
+		key.TargetLang = canonical
+	}
+	return key
+}
+
+// Get returns a copy of the cached translation for a key, or nil on a miss.
+func (c *TranslationCache) Get(key models.TranslationCacheKey) *models.Translation {
+	now := c.clock.Now()
+	if c.lru != nil {
+		if translation := c.lru.get(key, now); translation != nil {
+			return cloneTranslation(translation)
+		}
+	}
+
+	translation, expiresAt, err := c.repo.Get(key, now)
+	if err != nil {
+		log.Printf("Translation cache lookup failed: %v", err)
+		return nil
+	}
+	if translation == nil {
+		return nil
+	}
+
+	if c.lru != nil {
+		c.lru.put(key, cloneTranslation(translation), expiresAt)
+	}
+	return translation
+}
+
+// Put caches a translation produced by the model of its key.
+func (c *TranslationCache) Put(key models.TranslationCacheKey, translation *models.Translation) {
+	expiresAt := c.clock.Now().Add(c.ttl)
+	if err := c.repo.Put(key, translation, expiresAt); err != nil {
+		log.Printf("Translation cache update failed: %v", err)
+	}
+	if c.lru != nil {
+		c.lru.put(key, cloneTranslation(translation), expiresAt)
+	}
+}
+
+// Purge deletes cached translations: only the expired ones when expiredOnly is set, or all of them.
+// The in-memory cache is cleared either way, after the database so that a concurrent lookup cannot
+// reload a translation that is about to be deleted.
+func (c *TranslationCache) Purge(expiredOnly bool) (*models.TranslationCachePurge, error) {
+	var expiredBefore *time.Time
+	if expiredOnly {
+		now := c.clock.Now()
+		expiredBefore = &now
+	}
+
+	deleted, err := c.repo.Purge(expiredBefore)
+	if err != nil {
+		return nil, err
+	}
+	if c.lru != nil {
+		c.lru.clear()
+	}
+	return &models.TranslationCachePurge{Deleted: deleted}, nil
+}
+
+func cloneTranslation(translation *models.Translation) *models.Translation {
+	clone := *translation
+	clone.Alternatives = slices.Clone(translation.Alternatives)
+	return &clone
+}
+
+// CachingLLMClient is an LLMClient that answers translations from a TranslationCache, asking the
+// wrapped client only on a miss. Other operations are passed through uncached. Translations are
+// cached per provider and model, so a cached translation was always produced by the current model.
+type CachingLLMClient struct {
+	LLMClient
+	provider string
+	cache    *TranslationCache
+}
+
+// NewCachingLLMClient wraps client, whose model is served by the named provider, with a translation
+// cache.
+func NewCachingLLMClient(client LLMClient, provider string, cache *TranslationCache) *CachingLLMClient {
+	if client == nil {
+		panic("LLM client cannot be nil")
+	}
+	if cache == nil {
+		panic("translation cache cannot be nil")
+	}
+	return &CachingLLMClient{LLMClient: client, provider: provider, cache: cache}
+}
+
+// Translate returns the cached translation of text if there is one, and translates and caches it
+// otherwise. Failed translations are not cached.
+func (c *CachingLLMClient) Translate(ctx context.Context, text, sourceLang, targetLang string) (*models.Translation, error) {
+	key := translationCacheKey(text, sourceLang, targetLang, c.provider, c.LLMClient.Model())
+	if translation := c.cache.Get(key); translation != nil {
+		return translation, nil
+	}
+
+	translation, err := c.LLMClient.Translate(ctx, text, sourceLang, targetLang)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Put(key, translation)
+	return translation, nil
+}
+
+// translationLRU is a fixed-size in-memory cache of translations that evicts the least recently used
+// entry when full. It is safe for concurrent use.
+type translationLRU struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // most recently used first
+	entries map[models.TranslationCacheKey]*list.Element
+}
+
+type lruEntry struct {
+	key         models.TranslationCacheKey
+	translation *models.Translation
+	expiresAt   time.Time
+}
+
+func newTranslationLRU(size int) *translationLRU {
+	return &translationLRU{size: size, order: list.New(), entries: make(map[models.TranslationCacheKey]*list.Element)}
+}
+
+// get returns the translation stored for key, or nil if there is none or it has expired.
+func (l *translationLRU) get(key models.TranslationCacheKey, now time.Time) *models.Translation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := element.Value.(*lruEntry)
+	if !now.Before(entry.expiresAt) {
+		l.order.Remove(element)
+		delete(l.entries, key)
+		return nil
+	}
+	l.order.MoveToFront(element)
+	return entry.translation
+}
+
+func (l *translationLRU) put(key models.TranslationCacheKey, translation *models.Translation, expiresAt time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.entries[key]; ok {
+		element.Value = &lruEntry{key: key, translation: translation, expiresAt: expiresAt}
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, translation: translation, expiresAt: expiresAt})
+	if l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (l *translationLRU) clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.order.Init()
+	clear(l.entries)
+}
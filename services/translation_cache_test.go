@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akolybelnikov/flashcards/models"
+)
+
+// mockTranslationCacheRepo is an in-memory db.TranslationCacheRepository.
+type mockTranslationCacheRepo struct {
+	entries map[models.TranslationCacheKey]cachedTranslation
+	gets    int
+	err     error
+}
+
+type cachedTranslation struct {
+	translation models.Translation
+	expiresAt   time.Time
+}
+
+func newMockTranslationCacheRepo() *mockTranslationCacheRepo {
+	return &mockTranslationCacheRepo{entries: make(map[models.TranslationCacheKey]cachedTranslation)}
+}
+
+func (m *mockTranslationCacheRepo) Get(key models.TranslationCacheKey, now time.Time) (*models.Translation, time.Time, error) {
+	m.gets++
+	if m.err != nil {
+		return nil, time.Time{}, m.err
+	}
+	entry, ok := m.entries[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		return nil, time.Time{}, nil
+	}
+	translation := entry.translation
+	return &translation, entry.expiresAt, nil
+}
+
+func (m *mockTranslationCacheRepo) Put(key models.TranslationCacheKey, translation *models.Translation, expiresAt time.Time) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.entries[key] = cachedTranslation{translation: *translation, expiresAt: expiresAt}
+	return nil
+}
+
+func (m *mockTranslationCacheRepo) Purge(expiredBefore *time.Time) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	var deleted int64
+	for key, entry := range m.entries {
+		if expiredBefore == nil || !entry.expiresAt.After(*expiredBefore) {
+			delete(m.entries, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// countingLLMClient returns a MockLLMClient that counts its translations.
+func countingLLMClient(calls *int) *MockLLMClient {
+	return &MockLLMClient{
+		ModelName: "gpt-test",
+		TranslateFunc: func(_ context.Context, text, sourceLang, targetLang string) (*models.Translation, error) {
+			*calls++
+			return &models.Translation{Translation: mockTranslation(text, sourceLang, targetLang), Alternatives: []string{"γεια"}}, nil
+		},
+	}
+}
+
+func TestCachingLLMClientTranslatesOnce(t *testing.T) {
+	now := time.Date(2025, 11, 21, 12, 0, 0, 0, time.UTC)
+	var calls int
+	repo := newMockTranslationCacheRepo()
+	cache := NewTranslationCache(repo, time.Hour, 10)
+	cache.clock = fakeClock{now: now}
+	client := NewCachingLLMClient(countingLLMClient(&calls), OpenAIProvider, cache)
+
+	first, err := client.Translate(context.Background(), "hello", "en", "el")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first.Alternatives[0] = "changed"
+
+	// Whitespace and the case of language codes do not change the key
+	second, err := client.Translate(context.Background(), "  hello ", "EN", "el")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single model call, got %d", calls)
+	}
+	if second.Translation != "γεια σας" || second.Alternatives[0] != "γεια" {
+		t.Fatalf("expected the cached translation unchanged, got %+v", second)
+	}
+
+	key := models.TranslationCacheKey{Text: "hello", SourceLang: "en", TargetLang: "el", PromptVersion: promptVersion, Provider: OpenAIProvider, Model: "gpt-test"}
+	entry, ok := repo.entries[key]
+	if !ok || !entry.expiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("unexpected stored entry %+v", repo.entries)
+	}
+	if repo.gets != 1 {
+		t.Errorf("expected the second lookup to be served from memory, got %d database lookups", repo.gets)
+	}
+	if client.Model() != "gpt-test" {
+		t.Errorf("expected the wrapped model name, got %q", client.Model())
+	}
+}
+
+func TestCachingLLMClientKeysByModel(t *testing.T) {
+	var calls int
+	repo := newMockTranslationCacheRepo()
+	cache := NewTranslationCache(repo, time.Hour, 10)
+
+	llm := countingLLMClient(&calls)
+	if _, err := NewCachingLLMClient(llm, OpenAIProvider, cache).Translate(context.Background(), "hello", "en", "el"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A translation from another model or provider is not served for the new one
+	llm.ModelName = "gpt-other"
+	if _, err := NewCachingLLMClient(llm, OpenAIProvider, cache).Translate(context.Background(), "hello", "en", "el"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := NewCachingLLMClient(llm, OllamaProvider, cache).Translate(context.Background(), "hello", "en", "el"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 || len(repo.entries) != 3 {
+		t.Fatalf("expected a model call and an entry per model, got %d calls and %d entries", calls, len(repo.entries))
+	}
+}
+
+func TestCachingLLMClientReadsThroughToDatabase(t *testing.T) {
+	now := time.Date(2025, 11, 21, 12, 0, 0, 0, time.UTC)
+	var calls int
+	repo := newMockTranslationCacheRepo()
+
+	// A separate cache, as after a restart, finds the stored translation
+	for range 2 {
+		cache := NewTranslationCache(repo, time.Hour, 0)
+		cache.clock = fakeClock{now: now}
+		if _, err := NewCachingLLMClient(countingLLMClient(&calls), OpenAIProvider, cache).Translate(context.Background(), "hello", "en", "el"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single model call, got %d", calls)
+	}
+}
+
+func TestCachingLLMClientExpiresEntries(t *testing.T) {
+	now := time.Date(2025, 11, 21, 12, 0, 0, 0, time.UTC)
+	var calls int
+	cache := NewTranslationCache(newMockTranslationCacheRepo(), time.Hour, 10)
+	client := NewCachingLLMClient(countingLLMClient(&calls), OpenAIProvider, cache)
+
+	for _, at := range []time.Time{now, now.Add(59 * time.Minute), now.Add(time.Hour)} {
+		cache.clock = fakeClock{now: at}
+		if _, err := client.Translate(context.Background(), "hello", "en", "el"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected the expired translation to be requested again, got %d calls", calls)
+	}
+}
+
+func TestCachingLLMClientIgnoresCacheFailures(t *testing.T) {
+	var calls int
+	repo := newMockTranslationCacheRepo()
+	repo.err = errors.New("connection refused")
+	client := NewCachingLLMClient(countingLLMClient(&calls), OpenAIProvider, NewTranslationCache(repo, time.Hour, 0))
+
+	for range 2 {
+		translation, err := client.Translate(context.Background(), "hello", "en", "el")
+		if err != nil || translation.Translation != "γεια σας" {
+			t.Fatalf("expected the translation despite the cache failing, got %+v, %v", translation, err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected every translation to reach the model, got %d calls", calls)
+	}
+}
+
+func TestCachingLLMClientDoesNotCacheErrors(t *testing.T) {
+	repo := newMockTranslationCacheRepo()
+	llm := &MockLLMClient{TranslateFunc: func(context.Context, string, string, string) (*models.Translation, error) {
+		return nil, errors.New("rate limited")
+	}}
+	client := NewCachingLLMClient(llm, OpenAIProvider, NewTranslationCache(repo, time.Hour, 10))
+
+	if _, err := client.Translate(context.Background(), "hello", "en", "el"); err == nil {
+		t.Fatal("expected the model error")
+	}
+	if len(repo.entries) != 0 {
+		t.Fatalf("expected nothing to be cached, got %+v", repo.entries)
+	}
+}
+
+func TestTranslationLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	now := time.Date(2025, 11, 21, 12, 0, 0, 0, time.UTC)
+	lru := newTranslationLRU(2)
+	key := func(text string) models.TranslationCacheKey {
+		return models.TranslationCacheKey{Text: text, SourceLang: "en", TargetLang: "el"}
+	}
+	expiresAt := now.Add(time.Hour)
+
+	lru.put(key("a"), &models.Translation{Translation: "α"}, expiresAt)
+	lru.put(key("b"), &models.Translation{Translation: "β"}, expiresAt)
+	lru.get(key("a"), now)
+	lru.put(key("c"), &models.Translation{Translation: "γ"}, expiresAt)
+
+	if lru.get(key("b"), now) != nil {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	if lru.get(key("a"), now) == nil || lru.get(key("c"), now) == nil {
+		t.Error("expected the recently used entries to be kept")
+	}
+}
+
+func TestTranslationCachePurge(t *testing.T) {
+	now := time.Date(2025, 11, 21, 12, 0, 0, 0, time.UTC)
+	repo := newMockTranslationCacheRepo()
+	cache := NewTranslationCache(repo, time.Hour, 10)
+
+	cache.clock = fakeClock{now: now.Add(-2 * time.Hour)}
+	cache.Put(translationCacheKey("old", "en", "el", OpenAIProvider, "gpt-test"), &models.Translation{Translation: "παλιό"})
+	cache.clock = fakeClock{now: now}
+	cache.Put(translationCacheKey("new", "en", "el", OpenAIProvider, "gpt-test"), &models.Translation{Translation: "νέο"})
+
+	purge, err := cache.Purge(true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if purge.Deleted != 1 || len(repo.entries) != 1 {
+		t.Fatalf("expected only the expired translation to be purged, got %+v", purge)
+	}
+
+	purge, err = cache.Purge(false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if purge.Deleted != 1 || cache.Get(translationCacheKey("new", "en", "el", OpenAIProvider, "gpt-test")) != nil {
+		t.Fatalf("expected every translation to be purged from the database and memory, got %+v", purge)
+	}
+}
+
+func TestTranslationCacheKeyKeepsLineBreaks(t *testing.T) {
+	if translationCacheKey("  good \t morning ", "en", "el", OpenAIProvider, "gpt-test") != translationCacheKey("good morning", "en", "el", OpenAIProvider, "gpt-test") {
+		t.Fatal("expected texts differing only in spacing to share a key")
+	}
+	if translationCacheKey("a\nb", "en", "el", OpenAIProvider, "gpt-test") == translationCacheKey("a b", "en", "el", OpenAIProvider, "gpt-test") {
+		t.Fatal("expected texts differing in line breaks to have different keys")
+	}
+}
+
+func TestTranslationCachePurgeFailureKeepsMemory(t *testing.T) {
+	repo := newMockTranslationCacheRepo()
+	cache := NewTranslationCache(repo, time.Hour, 10)
+	key := translationCacheKey("hello", "en", "el", OpenAIProvider, "gpt-test")
+	cache.Put(key, &models.Translation{Translation: "γεια"})
+
+	repo.err = errors.New("connection refused")
+	if _, err := cache.Purge(false); err == nil {
+		t.Fatal("expected the database error")
+	}
+	if cache.Get(key) == nil {
+		t.Fatal("expected the in-memory cache to be kept when the database purge fails")
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import "testing"
+
+func TestSanitizeTranslation(t *testing.T) {
+	tests := []struct {
+		name        string
+		translation string
+		source      string
+		want        string
+	}{
+		{"plain", "γεια σας", "hello", "γεια σας"},
+		{"double quotes", `"γεια σας"`, "hello", "γεια σας"},
+		{"nested quotes", `'"γεια σας"'`, "hello", "γεια σας"},
+		{"guillemets", "«γεια σας»", "hello", "γεια σας"},
+		{"curly quotes", "“hello”", "γεια σας", "hello"},
+		{"trailing period", "γεια σας.", "hello", "γεια σας"},
+		{"quoted trailing period", `"γεια σας."`, "hello", "γεια σας"},
+		{"period kept for sentences", "Είμαι καλά.", "I am fine.", "Είμαι καλά."},
+		{"label", "Translation: γεια σας", "hello", "γεια σας"},
+		{"explanation on later lines", "γεια σας\n\nThis is the formal greeting.", "hello", "γεια σας"},
+		{"extra whitespace", "  γεια   σας ", "hello", "γεια σας"},
+		{"greek question mark kept", "Τι κάνεις;", "How are you?", "Τι κάνεις;"},
+		{"lone quote kept", `"`, "quote", `"`},
+		{"lines of a multi-line source kept", "Καλημέρα.\nΤι  κάνεις;", "Good morning.\nHow are you?", "Καλημέρα.\nΤι κάνεις;"},
+		{"explanations of a multi-line source dropped", "Καλημέρα.\nΤι κάνεις;\n\nNote: informal greeting.", "Good morning.\nHow are you?", "Καλημέρα.\nΤι κάνεις;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeTranslation(tt.translation, tt.source); got != tt.want {
+				t.Errorf("sanitizeTranslation(%q, %q) = %q, want %q", tt.translation, tt.source, got, tt.want)
+			}
+		})
+	}
+}
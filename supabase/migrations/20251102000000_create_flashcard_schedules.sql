@@ -0,0 +1,12 @@
+-- Create a table holding the spaced-repetition schedule of each flashcard
+CREATE TABLE IF NOT EXISTS flashcard_schedules (
+    flashcard_id INTEGER PRIMARY KEY REFERENCES flashcards(id) ON DELETE CASCADE,
+    ease_factor DOUBLE PRECISION NOT NULL DEFAULT 2.5,
+    interval_days INTEGER NOT NULL DEFAULT 0,
+    repetitions INTEGER NOT NULL DEFAULT 0,
+    due_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_reviewed_at TIMESTAMP
+);
+
+-- Create an index on due_at for faster due queue lookups
+CREATE INDEX IF NOT EXISTS idx_flashcard_schedules_due_at ON flashcard_schedules(due_at);
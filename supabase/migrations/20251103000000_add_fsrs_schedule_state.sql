@@ -0,0 +1,6 @@
+-- Track which scheduler produced a schedule along with the FSRS memory state
+ALTER TABLE flashcard_schedules
+    ADD COLUMN IF NOT EXISTS scheduler TEXT NOT NULL DEFAULT 'sm2',
+    ADD COLUMN IF NOT EXISTS stability DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS difficulty DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS retrievability DOUBLE PRECISION;
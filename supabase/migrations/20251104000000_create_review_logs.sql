@@ -0,0 +1,15 @@
+-- Create a table recording every review of a flashcard
+CREATE TABLE IF NOT EXISTS review_logs (
+    id BIGSERIAL PRIMARY KEY,
+    flashcard_id INTEGER NOT NULL REFERENCES flashcards(id) ON DELETE CASCADE,
+    grade SMALLINT NOT NULL CHECK (grade BETWEEN 0 AND 5),
+    duration_ms INTEGER,
+    elapsed_days DOUBLE PRECISION,
+    scheduler TEXT NOT NULL,
+    state_before JSONB,
+    state_after JSONB NOT NULL,
+    reviewed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create an index for paging through a card's history, newest first
+CREATE INDEX IF NOT EXISTS idx_review_logs_flashcard_id ON review_logs(flashcard_id, id DESC);
@@ -0,0 +1,13 @@
+-- Attribute reviews to a user so that scheduling parameters can be fitted per user
+ALTER TABLE review_logs ADD COLUMN IF NOT EXISTS user_id TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_review_logs_user_id ON review_logs(user_id, flashcard_id, reviewed_at);
+
+-- Create a table holding FSRS weights fitted from each user's review history
+CREATE TABLE IF NOT EXISTS fsrs_parameters (
+    user_id TEXT PRIMARY KEY,
+    weights DOUBLE PRECISION[] NOT NULL,
+    log_loss DOUBLE PRECISION NOT NULL,
+    review_count INTEGER NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
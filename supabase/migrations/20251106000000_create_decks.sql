@@ -0,0 +1,13 @@
+-- Create a table grouping flashcards into named decks
+CREATE TABLE IF NOT EXISTS decks (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Cards outlive their deck; deleting a deck leaves its cards unassigned
+ALTER TABLE flashcards ADD COLUMN IF NOT EXISTS deck_id INTEGER REFERENCES decks(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_flashcards_deck_id ON flashcards(deck_id);
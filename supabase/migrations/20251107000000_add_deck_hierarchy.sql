@@ -0,0 +1,11 @@
+-- Allow decks to be nested; deleting a deck deletes its sub-decks
+ALTER TABLE decks ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES decks(id) ON DELETE CASCADE;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'decks_parent_not_self') THEN
+        ALTER TABLE decks ADD CONSTRAINT decks_parent_not_self CHECK (parent_id IS NULL OR parent_id <> id);
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_decks_parent_id ON decks(parent_id);
@@ -0,0 +1,14 @@
+-- Create tables labelling flashcards with tags
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS flashcard_tags (
+    flashcard_id INTEGER NOT NULL REFERENCES flashcards(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (flashcard_id, tag_id)
+);
+
+-- Create an index on tag_id for faster tag filtering
+CREATE INDEX IF NOT EXISTS idx_flashcard_tags_tag_id ON flashcard_tags(tag_id);
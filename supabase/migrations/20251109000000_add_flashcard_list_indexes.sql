@@ -0,0 +1,10 @@
+-- Timestamps are sort keys for paginated listing and must always be set
+UPDATE flashcards SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+UPDATE flashcards SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE flashcards ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE flashcards ALTER COLUMN updated_at SET NOT NULL;
+
+-- Create indexes matching the keyset pagination orderings of GET /flashcards
+CREATE INDEX IF NOT EXISTS idx_flashcards_created_at_id ON flashcards(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_flashcards_updated_at_id ON flashcards(updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_flashcards_question_id ON flashcards(question, id);
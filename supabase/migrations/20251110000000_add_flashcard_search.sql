@@ -0,0 +1,32 @@
+-- Enable accent folding for full-text search
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Create text search configurations that fold case and diacritics (including the Greek tonos and
+-- dialytika) before stemming, so that "γεια" matches "Γειά" and "cafe" matches "café"
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'flashcards_el') THEN
+        CREATE TEXT SEARCH CONFIGURATION flashcards_el (COPY = greek);
+        ALTER TEXT SEARCH CONFIGURATION flashcards_el
+            ALTER MAPPING FOR hword, hword_part, word WITH unaccent, greek_stem;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'flashcards_en') THEN
+        CREATE TEXT SEARCH CONFIGURATION flashcards_en (COPY = english);
+        ALTER TEXT SEARCH CONFIGURATION flashcards_en
+            ALTER MAPPING FOR hword, hword_part, word WITH unaccent, english_stem;
+    END IF;
+END
+$$;
+
+-- Index every card under both configurations so that either language can be searched; questions
+-- weigh more than answers when ranking
+ALTER TABLE flashcards ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('flashcards_en', question), 'A') ||
+    setweight(to_tsvector('flashcards_el', question), 'A') ||
+    setweight(to_tsvector('flashcards_en', answer), 'B') ||
+    setweight(to_tsvector('flashcards_el', answer), 'B')
+) STORED;
+
+-- Create a GIN index on search_vector for fast full-text search
+CREATE INDEX IF NOT EXISTS idx_flashcards_search_vector ON flashcards USING GIN (search_vector);
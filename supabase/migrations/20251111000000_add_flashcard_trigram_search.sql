@@ -0,0 +1,6 @@
+-- Enable trigram similarity for typo-tolerant search
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Create trigram indexes on question and answer for fast fuzzy matching
+CREATE INDEX IF NOT EXISTS idx_flashcards_question_trgm ON flashcards USING GIN (question gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_flashcards_answer_trgm ON flashcards USING GIN (answer gin_trgm_ops);
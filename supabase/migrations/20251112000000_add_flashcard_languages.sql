@@ -0,0 +1,6 @@
+-- Add the BCP 47 language codes of each side of a flashcard
+ALTER TABLE flashcards ADD COLUMN IF NOT EXISTS question_lang TEXT;
+ALTER TABLE flashcards ADD COLUMN IF NOT EXISTS answer_lang TEXT;
+
+-- Create an index on the language pair for faster language filtering
+CREATE INDEX IF NOT EXISTS idx_flashcards_languages ON flashcards(question_lang, answer_lang);
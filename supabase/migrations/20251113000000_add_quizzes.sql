@@ -0,0 +1,16 @@
+-- Create a table of multiple-choice quizzes; the correct choice never leaves the server
+CREATE TABLE IF NOT EXISTS quizzes (
+    id TEXT PRIMARY KEY,
+    flashcard_id INTEGER NOT NULL REFERENCES flashcards(id) ON DELETE CASCADE,
+    choices TEXT[] NOT NULL,
+    correct_index INTEGER NOT NULL CHECK (correct_index >= 0),
+    choice INTEGER,
+    answered_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create an index on flashcard_id so deleting a flashcard removes its quizzes quickly
+CREATE INDEX IF NOT EXISTS idx_quizzes_flashcard_id ON quizzes(flashcard_id);
+
+-- Create an index on created_at so expired quizzes are pruned quickly
+CREATE INDEX IF NOT EXISTS idx_quizzes_created_at ON quizzes(created_at);
@@ -0,0 +1,27 @@
+-- Create a table of cloze notes, texts with {{c1::...}} deletions that each become a flashcard
+CREATE TABLE IF NOT EXISTS cloze_notes (
+    id SERIAL PRIMARY KEY,
+    text TEXT NOT NULL,
+    lang TEXT,
+    deck_id INTEGER REFERENCES decks(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Link each cloze card to its note and the index of the deletion it tests
+ALTER TABLE flashcards
+    ADD COLUMN IF NOT EXISTS cloze_note_id INTEGER REFERENCES cloze_notes(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS cloze_index INTEGER;
+
+-- Constraints have no IF NOT EXISTS, so check for them to keep the migration rerunnable
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT FROM pg_constraint WHERE conname = 'flashcards_cloze_check') THEN
+        ALTER TABLE flashcards
+            ADD CONSTRAINT flashcards_cloze_check CHECK ((cloze_note_id IS NULL) = (cloze_index IS NULL));
+    END IF;
+    IF NOT EXISTS (SELECT FROM pg_constraint WHERE conname = 'flashcards_cloze_note_index_key') THEN
+        ALTER TABLE flashcards
+            ADD CONSTRAINT flashcards_cloze_note_index_key UNIQUE (cloze_note_id, cloze_index);
+    END IF;
+END $$;
@@ -0,0 +1,7 @@
+-- Link a reverse card (answer -> question) to the card it was generated from. Deleting the original
+-- card deletes its reverse.
+ALTER TABLE flashcards
+    ADD COLUMN IF NOT EXISTS reverse_of INTEGER REFERENCES flashcards(id) ON DELETE CASCADE;
+
+-- Each card has at most one reverse card
+CREATE UNIQUE INDEX IF NOT EXISTS idx_flashcards_reverse_of ON flashcards(reverse_of);
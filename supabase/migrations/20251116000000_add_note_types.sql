@@ -0,0 +1,26 @@
+-- Create a table of note types, which name the fields of a note and render its card with templates
+CREATE TABLE IF NOT EXISTS note_types (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    fields TEXT[] NOT NULL,
+    front_template TEXT NOT NULL,
+    back_template TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create a table of notes holding field values; a note type cannot be deleted while it has notes
+CREATE TABLE IF NOT EXISTS notes (
+    id SERIAL PRIMARY KEY,
+    note_type_id INTEGER NOT NULL REFERENCES note_types(id),
+    fields JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notes_note_type_id ON notes(note_type_id);
+
+-- Link each note to its card. The card's question and answer hold the last rendering of the note
+-- for listing and search; study endpoints render the note again.
+ALTER TABLE flashcards
+    ADD COLUMN IF NOT EXISTS note_id INTEGER UNIQUE REFERENCES notes(id) ON DELETE CASCADE;
@@ -0,0 +1,11 @@
+-- Store AI-generated example sentences for a flashcard, so they are generated once and reused
+CREATE TABLE IF NOT EXISTS card_examples (
+    id SERIAL PRIMARY KEY,
+    flashcard_id INTEGER NOT NULL REFERENCES flashcards(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    sentence TEXT NOT NULL,
+    translation TEXT NOT NULL,
+    level TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (flashcard_id, position)
+);
@@ -0,0 +1,2 @@
+-- Store structured grammar data (noun forms, verb principal parts and conjugations) on flashcards
+ALTER TABLE flashcards ADD COLUMN IF NOT EXISTS grammar JSONB;
@@ -0,0 +1,9 @@
+-- Record which fields of a flashcard a language model generated, with which model and prompts
+ALTER TABLE flashcards
+    ADD COLUMN IF NOT EXISTS ai_fields TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS ai_model TEXT,
+    ADD COLUMN IF NOT EXISTS ai_prompt_version TEXT,
+    ADD COLUMN IF NOT EXISTS ai_generated_at TIMESTAMP;
+
+-- Create an index for auditing machine-generated cards
+CREATE INDEX IF NOT EXISTS idx_flashcards_ai_generated ON flashcards(ai_generated_at) WHERE cardinality(ai_fields) > 0;
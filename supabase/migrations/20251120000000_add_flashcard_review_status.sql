@@ -0,0 +1,9 @@
+-- Cards with machine-generated fields wait for a person to approve them before they are studied.
+-- Existing cards stay active.
+ALTER TABLE flashcards
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'pending_review')),
+    ADD COLUMN IF NOT EXISTS approved_by TEXT,
+    ADD COLUMN IF NOT EXISTS approved_at TIMESTAMP;
+
+-- Create an index for the review queue, oldest first
+CREATE INDEX IF NOT EXISTS idx_flashcards_pending_review ON flashcards(created_at, id) WHERE status = 'pending_review';
@@ -0,0 +1,17 @@
+-- Create a table caching translations by normalised text, language pair, prompt version and the
+-- model that produced them
+CREATE TABLE IF NOT EXISTS translation_cache (
+    text TEXT NOT NULL,
+    source_lang TEXT NOT NULL,
+    target_lang TEXT NOT NULL,
+    prompt_version TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    model TEXT NOT NULL,
+    translation JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (text, source_lang, target_lang, prompt_version, provider, model)
+);
+
+-- Create an index for purging expired translations
+CREATE INDEX IF NOT EXISTS idx_translation_cache_expires_at ON translation_cache(expires_at);
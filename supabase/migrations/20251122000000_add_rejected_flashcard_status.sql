@@ -0,0 +1,9 @@
+-- Rejected cards are kept with who rejected them and when, rather than deleted, so that the review
+-- decision can be audited. They are never studied.
+ALTER TABLE flashcards
+    ADD COLUMN IF NOT EXISTS rejected_by TEXT,
+    ADD COLUMN IF NOT EXISTS rejected_at TIMESTAMP;
+
+ALTER TABLE flashcards DROP CONSTRAINT IF EXISTS flashcards_status_check;
+ALTER TABLE flashcards
+    ADD CONSTRAINT flashcards_status_check CHECK (status IN ('active', 'pending_review', 'rejected'));
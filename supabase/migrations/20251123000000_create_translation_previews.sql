@@ -0,0 +1,16 @@
+-- Create a table of translation previews, so that a card filled in with a previewed candidate records
+-- the model and prompt version that generated it
+CREATE TABLE IF NOT EXISTS translation_previews (
+    id TEXT PRIMARY KEY,
+    source TEXT NOT NULL,
+    translated_field TEXT NOT NULL CHECK (translated_field IN ('question', 'answer')),
+    question_lang TEXT NOT NULL,
+    answer_lang TEXT NOT NULL,
+    candidates JSONB NOT NULL,
+    model TEXT NOT NULL,
+    prompt_version TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create an index on created_at so expired previews are pruned quickly
+CREATE INDEX IF NOT EXISTS idx_translation_previews_created_at ON translation_previews(created_at);